@@ -44,7 +44,7 @@ func (a *App) initConfig(_ context.Context) error {
 }
 
 func (a *App) initServiceProvider(_ context.Context) error {
-	a.sp = newServiceProvider(a.cfg.DBUrl, a.cfg.JWTSignedKey, a.cfg.AccessTokenExpiresIn, a.cfg.RefreshTokenExpiresIn, a.logger)
+	a.sp = newServiceProvider(a.cfg, a.logger)
 	return nil
 }
 
@@ -64,7 +64,7 @@ func (a *App) initUserHandler(_ context.Context) error {
 		return err
 	}
 
-	if err = usermuximpl.Register(a.router, userService, sessionService, a.sp.TokenManager(), a.logger); err != nil {
+	if err = usermuximpl.Register(a.router, userService, sessionService, a.sp.TokenManager(), a.sp.RevocationList(), a.cfg.DummyLoginEnabled, a.logger); err != nil {
 		return err
 	}
 
@@ -77,7 +77,7 @@ func (a *App) initApartmentHandler(_ context.Context) error {
 		return err
 	}
 
-	if err = apartmentmuximpl.Register(a.router, apartmentService, a.sp.TokenManager(), a.logger); err != nil {
+	if err = apartmentmuximpl.Register(a.router, apartmentService, a.sp.TokenManager(), a.sp.RevocationList(), a.logger); err != nil {
 		return err
 	}
 	return nil
@@ -89,7 +89,17 @@ func (a *App) initHouseHandler(_ context.Context) error {
 		return err
 	}
 
-	if err = housemuximpl.Register(a.router, houseService, a.sp.TokenManager(), a.logger); err != nil {
+	apartmentService, err := a.sp.ApartmentService()
+	if err != nil {
+		return err
+	}
+
+	subscriptionService, err := a.sp.SubscriptionService()
+	if err != nil {
+		return err
+	}
+
+	if err = housemuximpl.Register(a.router, houseService, apartmentService, subscriptionService, a.sp.TokenManager(), a.sp.RevocationList(), a.logger); err != nil {
 		return err
 	}
 	return nil
@@ -130,6 +140,20 @@ func (a *App) runHTTPServer() error {
 }
 
 func (a *App) Run(ctx context.Context) error {
+	n, err := a.sp.Notifier()
+	if err != nil {
+		a.logger.Error("Failed to init notifier", "error", err.Error())
+		return err
+	}
+	n.Start()
+
+	r, err := a.sp.ClaimReaper()
+	if err != nil {
+		a.logger.Error("Failed to init claim reaper", "error", err.Error())
+		return err
+	}
+	r.Start()
+
 	g, _ := errgroup.WithContext(ctx)
 	g.Go(func() error {
 		return a.runHTTPServer()
@@ -150,23 +174,24 @@ func (a *App) Stop(ctx context.Context) error {
 		return err
 	}
 
-	if a.sp.houseRepository != nil {
-		if err := a.sp.houseRepository.CloseConnection(); err != nil {
-			a.logger.Error("Failed to close house repository", "error", err.Error())
+	if a.sp.claimReaper != nil {
+		if err := a.sp.claimReaper.Stop(ctx); err != nil {
+			a.logger.Error("Failed to stop claim reaper", "error", err.Error())
 			return err
 		}
 	}
 
-	if a.sp.sessionRepository != nil {
-		if err := a.sp.sessionRepository.CloseConnection(); err != nil {
-			a.logger.Error("Failed to close session repository", "error", err.Error())
+	if a.sp.notifier != nil {
+		if err := a.sp.notifier.Stop(ctx); err != nil {
+			a.logger.Error("Failed to stop notifier", "error", err.Error())
 			return err
 		}
 	}
 
-	if a.sp.userRepository != nil {
-		if err := a.sp.userRepository.CloseConnection(); err != nil {
-			a.logger.Error("Failed to close user repository", "error", err.Error())
+	//THE POOL GOES LAST, EVERYTHING ABOVE MAY STILL BE USING IT
+	if a.sp.db != nil {
+		if err := a.sp.db.Close(); err != nil {
+			a.logger.Error("Failed to close database", "error", err.Error())
 			return err
 		}
 	}
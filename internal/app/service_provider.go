@@ -1,36 +1,57 @@
 package app
 
 import (
+	"avito/internal/config"
+	"avito/internal/notifier"
+	notifierimpl "avito/internal/notifier/implementation"
+	"avito/internal/reaper"
+	reaperimpl "avito/internal/reaper/implementation"
 	apartmentrepository "avito/internal/repository/apartment"
 	apartmentrepositorypostgres "avito/internal/repository/apartment/postgres"
 	houserepository "avito/internal/repository/house"
 	houserepositorypostgres "avito/internal/repository/house/postgres"
 	sessionrepository "avito/internal/repository/session"
 	sessionrepositorypostgres "avito/internal/repository/session/postgres"
+	subscriptionrepository "avito/internal/repository/subscription"
+	subscriptionrepositorypostgres "avito/internal/repository/subscription/postgres"
 	userrepository "avito/internal/repository/user"
 	userrepositorypostgres "avito/internal/repository/user/postgres"
 	apartmentservice "avito/internal/service/apartment"
+	apartmentservicecached "avito/internal/service/apartment/cached"
 	apartmentserviceimpl "avito/internal/service/apartment/implementation"
 	houseservice "avito/internal/service/house"
 	houseserviceimpl "avito/internal/service/house/implementation"
 	sessionservice "avito/internal/service/session"
 	sessionserviceimpl "avito/internal/service/session/implementation"
+	subscriptionservice "avito/internal/service/subscription"
+	subscriptionserviceimpl "avito/internal/service/subscription/implementation"
 	userservice "avito/internal/service/user"
 	userserviceimpl "avito/internal/service/user/implementation"
+	"avito/internal/transaction"
+	transactionpostgres "avito/internal/transaction/postgres"
+	"avito/pkg/cache/lru"
+	"avito/pkg/database"
+	"avito/pkg/revocation"
+	revocationmemory "avito/pkg/revocation/memory"
+	"avito/pkg/sender"
+	senderimpl "avito/pkg/sender/implementation"
 	tokenmanager "avito/pkg/token_manager"
 	tokenmanagerimpl "avito/pkg/token_manager/implementation"
 	"log/slog"
-	"time"
 )
 
 type serviceProvider struct {
-	tokenManager tokenmanager.Manager
+	tokenManager   tokenmanager.Manager
+	revocationList revocation.List
 
-	dbURL string
+	cfg *config.Config
 
-	jwtSignedKey          string
-	accessTokenExpiresIn  time.Duration
-	refreshTokenExpiresIn time.Duration
+	dbConfig database.Config
+	db       *database.DB
+
+	txManager transaction.Manager
+
+	tokenManagerConfig tokenmanagerimpl.Config
 
 	sessionRepository sessionrepository.Repository
 	sessionService    sessionservice.Service
@@ -44,16 +65,50 @@ type serviceProvider struct {
 	houseRepository houserepository.Repository
 	houseService    houseservice.Service
 
+	subscriptionRepository subscriptionrepository.Repository
+	subscriptionService    subscriptionservice.Service
+
+	sender   sender.Sender
+	notifier notifier.Notifier
+
+	claimReaper reaper.Reaper
+
 	logger *slog.Logger
 }
 
+func (sp *serviceProvider) DB() (*database.DB, error) {
+	if sp.db == nil {
+		db, err := database.New(sp.cfg.DBUrl, sp.dbConfig, sp.logger)
+		if err != nil {
+			return nil, err
+		}
+
+		sp.db = db
+	}
+
+	return sp.db, nil
+}
+
+func (sp *serviceProvider) TxManager() (transaction.Manager, error) {
+	if sp.txManager == nil {
+		db, err := sp.DB()
+		if err != nil {
+			return nil, err
+		}
+
+		sp.txManager = transactionpostgres.New(db, sp.logger)
+	}
+
+	return sp.txManager, nil
+}
+
 func (sp *serviceProvider) SessionRepository() (sessionrepository.Repository, error) {
 	if sp.sessionRepository == nil {
-		rep, err := sessionrepositorypostgres.New(sp.logger, sp.dbURL)
+		db, err := sp.DB()
 		if err != nil {
 			return nil, err
 		}
-		sp.sessionRepository = rep
+		sp.sessionRepository = sessionrepositorypostgres.New(db, sp.logger)
 	}
 	return sp.sessionRepository, nil
 }
@@ -65,7 +120,17 @@ func (sp *serviceProvider) SessionService() (sessionservice.Service, error) {
 			return nil, err
 		}
 
-		sp.sessionService = sessionserviceimpl.New(rep, sp.TokenManager(), sp.logger)
+		userRep, err := sp.UserRepository()
+		if err != nil {
+			return nil, err
+		}
+
+		txManager, err := sp.TxManager()
+		if err != nil {
+			return nil, err
+		}
+
+		sp.sessionService = sessionserviceimpl.New(rep, userRep, txManager, sp.TokenManager(), sp.RevocationList(), sp.logger)
 	}
 
 	return sp.sessionService, nil
@@ -73,12 +138,12 @@ func (sp *serviceProvider) SessionService() (sessionservice.Service, error) {
 
 func (sp *serviceProvider) UserRepository() (userrepository.Repository, error) {
 	if sp.userRepository == nil {
-		rep, err := userrepositorypostgres.New(sp.dbURL, sp.logger)
+		db, err := sp.DB()
 		if err != nil {
 			return nil, err
 		}
 
-		sp.userRepository = rep
+		sp.userRepository = userrepositorypostgres.New(db, sp.logger)
 	}
 
 	return sp.userRepository, nil
@@ -91,7 +156,17 @@ func (sp *serviceProvider) UserService() (userservice.Service, error) {
 			return nil, err
 		}
 
-		sp.userService = userserviceimpl.New(rep, sp.TokenManager(), sp.logger)
+		sessionService, err := sp.SessionService()
+		if err != nil {
+			return nil, err
+		}
+
+		txManager, err := sp.TxManager()
+		if err != nil {
+			return nil, err
+		}
+
+		sp.userService = userserviceimpl.New(rep, sessionService, txManager, sp.TokenManager(), sp.logger)
 	}
 
 	return sp.userService, nil
@@ -99,12 +174,12 @@ func (sp *serviceProvider) UserService() (userservice.Service, error) {
 
 func (sp *serviceProvider) ApartmentRepository() (apartmentrepository.Repository, error) {
 	if sp.apartmentRepository == nil {
-		apartmentRepository, err := apartmentrepositorypostgres.New(sp.dbURL, sp.logger)
+		db, err := sp.DB()
 		if err != nil {
 			return nil, err
 		}
 
-		sp.apartmentRepository = apartmentRepository
+		sp.apartmentRepository = apartmentrepositorypostgres.New(db, sp.logger)
 	}
 	return sp.apartmentRepository, nil
 }
@@ -116,19 +191,29 @@ func (sp *serviceProvider) ApartmentService() (apartmentservice.Service, error)
 			return nil, err
 		}
 
-		sp.apartmentService = apartmentserviceimpl.New(apartmentRepository, sp.logger)
+		n, err := sp.Notifier()
+		if err != nil {
+			return nil, err
+		}
+
+		sp.apartmentService = apartmentserviceimpl.New(apartmentRepository, n, sp.logger)
+
+		if sp.cfg.ApartmentsCacheEnabled {
+			c := lru.New[int64, *apartmentservicecached.HouseLists](sp.cfg.ApartmentsCacheSize)
+			sp.apartmentService = apartmentservicecached.New(sp.apartmentService, c, sp.cfg.ApartmentsCacheTTL, sp.logger)
+		}
 	}
 	return sp.apartmentService, nil
 }
 
 func (sp *serviceProvider) HouseRepository() (houserepository.Repository, error) {
 	if sp.houseRepository == nil {
-		houseRepository, err := houserepositorypostgres.New(sp.dbURL, sp.logger)
+		db, err := sp.DB()
 		if err != nil {
 			return nil, err
 		}
 
-		sp.houseRepository = houseRepository
+		sp.houseRepository = houserepositorypostgres.New(db, sp.logger)
 	}
 
 	return sp.houseRepository, nil
@@ -145,20 +230,107 @@ func (sp *serviceProvider) HouseService() (houseservice.Service, error) {
 	return sp.houseService, nil
 }
 
+func (sp *serviceProvider) SubscriptionRepository() (subscriptionrepository.Repository, error) {
+	if sp.subscriptionRepository == nil {
+		db, err := sp.DB()
+		if err != nil {
+			return nil, err
+		}
+
+		sp.subscriptionRepository = subscriptionrepositorypostgres.New(db, sp.logger)
+	}
+
+	return sp.subscriptionRepository, nil
+}
+
+func (sp *serviceProvider) SubscriptionService() (subscriptionservice.Service, error) {
+	if sp.subscriptionService == nil {
+		subscriptionRepository, err := sp.SubscriptionRepository()
+		if err != nil {
+			return nil, err
+		}
+		sp.subscriptionService = subscriptionserviceimpl.New(subscriptionRepository, sp.logger)
+	}
+
+	return sp.subscriptionService, nil
+}
+
+func (sp *serviceProvider) Sender() sender.Sender {
+	if sp.sender == nil {
+		sp.sender = senderimpl.New()
+	}
+	return sp.sender
+}
+
+func (sp *serviceProvider) Notifier() (notifier.Notifier, error) {
+	if sp.notifier == nil {
+		subscriptionService, err := sp.SubscriptionService()
+		if err != nil {
+			return nil, err
+		}
+
+		sp.notifier = notifierimpl.New(subscriptionService, sp.Sender(), sp.cfg.NotifierWorkers, sp.cfg.NotifierQueueSize, sp.logger)
+	}
+
+	return sp.notifier, nil
+}
+
+func (sp *serviceProvider) ClaimReaper() (reaper.Reaper, error) {
+	if sp.claimReaper == nil {
+		apartmentService, err := sp.ApartmentService()
+		if err != nil {
+			return nil, err
+		}
+
+		sessionService, err := sp.SessionService()
+		if err != nil {
+			return nil, err
+		}
+
+		sp.claimReaper, err = reaperimpl.New(apartmentService, sessionService, sp.cfg.ModerationClaimTimeout, sp.cfg.ClaimReaperInterval, sp.logger)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return sp.claimReaper, nil
+}
+
 func (sp *serviceProvider) TokenManager() tokenmanager.Manager {
 	if sp.tokenManager == nil {
-		sp.tokenManager = tokenmanagerimpl.New(sp.jwtSignedKey, sp.accessTokenExpiresIn, sp.refreshTokenExpiresIn)
+		sp.tokenManager = tokenmanagerimpl.New(sp.tokenManagerConfig)
 	}
 	return sp.tokenManager
 }
 
-func newServiceProvider(dbURL string, jwtSignedKey string, accessTokenExpiresIn, refreshTokenExpiresIn time.Duration, logger *slog.Logger) *serviceProvider {
+// RevocationList keeps revoked tokens for the leeway on top of their lifetime,
+// the parser would still accept them during it.
+func (sp *serviceProvider) RevocationList() revocation.List {
+	if sp.revocationList == nil {
+		sp.revocationList = revocationmemory.New(sp.tokenManagerConfig.Leeway)
+	}
+	return sp.revocationList
+}
+
+func newServiceProvider(cfg *config.Config, logger *slog.Logger) *serviceProvider {
 	sp := &serviceProvider{
-		dbURL:                 dbURL,
-		jwtSignedKey:          jwtSignedKey,
-		accessTokenExpiresIn:  accessTokenExpiresIn,
-		refreshTokenExpiresIn: refreshTokenExpiresIn,
-		logger:                logger,
+		cfg: cfg,
+		dbConfig: database.Config{
+			MaxOpenConns:    cfg.DBMaxOpenConns,
+			MaxIdleConns:    cfg.DBMaxIdleConns,
+			ConnMaxLifetime: cfg.DBConnMaxLifetime,
+			ConnMaxIdleTime: cfg.DBConnMaxIdleTime,
+			MaxStatements:   cfg.DBMaxStatements,
+		},
+		tokenManagerConfig: tokenmanagerimpl.Config{
+			SignedKey:             cfg.JWTSignedKey,
+			AccessTokenExpiresIn:  cfg.AccessTokenExpiresIn,
+			RefreshTokenExpiresIn: cfg.RefreshTokenExpiresIn,
+			Issuer:                cfg.JWTIssuer,
+			Audience:              cfg.JWTAudience,
+			Leeway:                cfg.JWTLeeway,
+		},
+		logger: logger,
 	}
 
 	return sp
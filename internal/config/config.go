@@ -10,11 +10,31 @@ type Config struct {
 	Host string `env:"HOST" env-required:"true"`
 	Port string `env:"PORT" env-required:"true"`
 
-	DBUrl string `env:"DATABASE_URL" env-required:"true"`
+	DBUrl             string        `env:"DATABASE_URL" env-required:"true"`
+	DBMaxOpenConns    int           `env:"DB_MAX_OPEN_CONNS" env-default:"25"`
+	DBMaxIdleConns    int           `env:"DB_MAX_IDLE_CONNS" env-default:"25"`
+	DBConnMaxLifetime time.Duration `env:"DB_CONN_MAX_LIFETIME" env-default:"30m"`
+	DBConnMaxIdleTime time.Duration `env:"DB_CONN_MAX_IDLE_TIME" env-default:"5m"`
+	DBMaxStatements   int           `env:"DB_MAX_STATEMENTS" env-default:"256"`
 
 	JWTSignedKey          string        `env:"JWT_SIGNED_KEY" env-required:"true"`
 	AccessTokenExpiresIn  time.Duration `env:"ACCESS_TOKEN_EXPIRES_IN" env-required:"true"`
 	RefreshTokenExpiresIn time.Duration `env:"REFRESH_TOKEN_EXPIRES_IN" env-required:"true"`
+	JWTIssuer             string        `env:"JWT_ISSUER" env-default:"avito"`
+	JWTAudience           string        `env:"JWT_AUDIENCE" env-default:"avito"`
+	JWTLeeway             time.Duration `env:"JWT_LEEWAY" env-default:"30s"`
+
+	DummyLoginEnabled bool `env:"DUMMY_LOGIN_ENABLED" env-default:"false"`
+
+	NotifierWorkers   int `env:"NOTIFIER_WORKERS" env-default:"4"`
+	NotifierQueueSize int `env:"NOTIFIER_QUEUE_SIZE" env-default:"1000"`
+
+	ModerationClaimTimeout time.Duration `env:"MODERATION_CLAIM_TIMEOUT" env-default:"30m"`
+	ClaimReaperInterval    time.Duration `env:"CLAIM_REAPER_INTERVAL" env-default:"1m"`
+
+	ApartmentsCacheEnabled bool          `env:"APARTMENTS_CACHE_ENABLED" env-default:"false"`
+	ApartmentsCacheSize    int           `env:"APARTMENTS_CACHE_SIZE" env-default:"10000"`
+	ApartmentsCacheTTL     time.Duration `env:"APARTMENTS_CACHE_TTL" env-default:"30s"`
 }
 
 func New(configPath string, l *slog.Logger) (*Config, error) {
@@ -0,0 +1,14 @@
+package apartmenthandlerconverter
+
+import (
+	apartmenthandlermodel "avito/internal/handler/apartment/model"
+	"avito/internal/model"
+)
+
+func ToApartmentEditDTO(apartmentID int64, edit apartmenthandlermodel.ApartmentEdit) model.Apartment {
+	return model.Apartment{
+		ID:            apartmentID,
+		Price:         edit.Price,
+		NumberOfRooms: edit.NumberOfRooms,
+	}
+}
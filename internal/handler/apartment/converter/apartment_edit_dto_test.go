@@ -0,0 +1,17 @@
+package apartmenthandlerconverter
+
+import (
+	apartmenthandlermodel "avito/internal/handler/apartment/model"
+	"testing"
+)
+
+func BenchmarkToApartmentEditDTO(b *testing.B) {
+	b.ReportAllocs()
+
+	edit := apartmenthandlermodel.ApartmentEdit{}
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		ToApartmentEditDTO(1, edit)
+	}
+}
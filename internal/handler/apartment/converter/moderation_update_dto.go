@@ -0,0 +1,15 @@
+package apartmenthandlerconverter
+
+import (
+	apartmenthandlermodel "avito/internal/handler/apartment/model"
+	"avito/internal/model"
+)
+
+func ToModerationUpdateDTO(apartmentID int64, update apartmenthandlermodel.ModerationUpdate) model.Apartment {
+	return model.Apartment{
+		ID:               apartmentID,
+		ModerationStatus: update.ModerationStatus,
+		DeclineReason:    update.DeclineReason,
+		ModeratorComment: update.ModeratorComment,
+	}
+}
@@ -0,0 +1,17 @@
+package apartmenthandlerconverter
+
+import (
+	apartmenthandlermodel "avito/internal/handler/apartment/model"
+	"testing"
+)
+
+func BenchmarkToModerationUpdateDTO(b *testing.B) {
+	b.ReportAllocs()
+
+	update := apartmenthandlermodel.ModerationUpdate{}
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		ToModerationUpdateDTO(1, update)
+	}
+}
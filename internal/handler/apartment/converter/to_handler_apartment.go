@@ -13,5 +13,10 @@ func ToHandlerModelApartment(apartment model.Apartment) apartmenthandlermodel.Ap
 		Price:            apartment.Price,
 		NumberOfRooms:    apartment.NumberOfRooms,
 		ModerationStatus: apartment.ModerationStatus,
+		OwnerID:          apartment.OwnerID,
+		ModeratorID:      apartment.ModeratorID,
+		DeclineReason:    apartment.DeclineReason,
+		ModeratorComment: apartment.ModeratorComment,
+		RemovalReason:    apartment.RemovalReason,
 	}
 }
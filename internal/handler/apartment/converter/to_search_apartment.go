@@ -0,0 +1,17 @@
+package apartmenthandlerconverter
+
+import (
+	apartmenthandlermodel "avito/internal/handler/apartment/model"
+	"avito/internal/model"
+)
+
+func ToSearchApartment(result model.ApartmentWithHouse) apartmenthandlermodel.SearchApartment {
+	return apartmenthandlermodel.SearchApartment{
+		Apartment: ToHandlerModelApartment(result.Apartment),
+		House: apartmenthandlermodel.SearchHouse{
+			Address:   result.House.Address,
+			Year:      result.House.Year,
+			Developer: result.House.Developer,
+		},
+	}
+}
@@ -0,0 +1,17 @@
+package apartmenthandlerconverter
+
+import (
+	"avito/internal/model"
+	"testing"
+)
+
+func BenchmarkToSearchApartment(b *testing.B) {
+	b.ReportAllocs()
+
+	result := model.ApartmentWithHouse{}
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		ToSearchApartment(result)
+	}
+}
@@ -3,5 +3,16 @@ package apartmenthandler
 import "errors"
 
 var (
-	ErrInvalidHouseID = errors.New("invalid house id")
+	ErrInvalidHouseID         = errors.New("invalid house id")
+	ErrApartmentNotFound      = errors.New("apartment not found")
+	ErrApartmentAlreadyExists = errors.New("apartment with this number already exists in the house")
+	ErrModerationConflict     = errors.New("apartment is claimed by another moderator or has a different moderation status")
+	ErrInvalidTransition      = errors.New("invalid moderation status transition")
+	ErrModerationQueueEmpty   = errors.New("no apartments awaiting moderation")
+	ErrInvalidQueueFilter     = errors.New("invalid moderation queue filter")
+	ErrInvalidSearchFilter    = errors.New("invalid apartment search filter")
+
+	ErrDeclineReasonRequired = errors.New("decline reason and moderator comment are required to decline an apartment")
+	ErrNotApartmentOwner     = errors.New("apartment belongs to another user")
+	ErrRemovalReasonRequired = errors.New("removal reason is required to remove an apartment")
 )
@@ -1,4 +1,15 @@
 package apartmenthandler
 
+import "net/http"
+
 type Handler interface {
+	Create() http.HandlerFunc
+	Apartment() http.HandlerFunc
+	Update() http.HandlerFunc
+	Edit() http.HandlerFunc
+	Withdraw() http.HandlerFunc
+	Remove() http.HandlerFunc
+	Search() http.HandlerFunc
+	MyApartments() http.HandlerFunc
+	NextForModeration() http.HandlerFunc
 }
@@ -6,18 +6,28 @@ import (
 )
 
 type Apartment struct {
-	ID               uint32 `json:"id,omitempty"`
+	ID               int64  `json:"id,omitempty"`
 	ApartmentNumber  int    `json:"apartmentNumber"`
-	HouseID          uint32 `json:"house_id" validate:"required"`
+	HouseID          int64  `json:"house_id" validate:"required"`
 	Price            uint32 `json:"price" validate:"required"`
 	NumberOfRooms    uint32 `json:"number_of_rooms" validate:"required"`
 	ModerationStatus string `json:"moderation_status" validate:"moderation_status"`
+	OwnerID          int64  `json:"owner_id,omitempty"`
+	ModeratorID      int64  `json:"moderator_id,omitempty"`
+	DeclineReason    string `json:"decline_reason,omitempty"`
+	ModeratorComment string `json:"moderator_comment,omitempty"`
+	RemovalReason    string `json:"removal_reason,omitempty"`
 }
 
 var (
 	PossibleModerationStatus = []string{"created", "approved", "declined", "on moderation"}
+	PossibleDeclineReasons   = []string{"prohibited_content", "wrong_price", "duplicate"}
 )
 
 func ModerationStatusValidation(fl validator.FieldLevel) bool {
 	return slices.Contains(PossibleModerationStatus, fl.Field().String())
 }
+
+func DeclineReasonValidation(fl validator.FieldLevel) bool {
+	return slices.Contains(PossibleDeclineReasons, fl.Field().String())
+}
@@ -0,0 +1,6 @@
+package apartmenthandlermodel
+
+type ApartmentEdit struct {
+	Price         uint32 `json:"price" validate:"required"`
+	NumberOfRooms uint32 `json:"number_of_rooms" validate:"required"`
+}
@@ -0,0 +1,7 @@
+package apartmenthandlermodel
+
+type ModerationUpdate struct {
+	ModerationStatus string `json:"moderation_status" validate:"required,moderation_status"`
+	DeclineReason    string `json:"decline_reason,omitempty" validate:"omitempty,decline_reason"`
+	ModeratorComment string `json:"moderator_comment,omitempty"`
+}
@@ -0,0 +1,5 @@
+package apartmenthandlermodel
+
+type Removal struct {
+	Reason string `json:"reason" validate:"required"`
+}
@@ -0,0 +1,19 @@
+package apartmenthandlermodel
+
+type SearchHouse struct {
+	Address   string `json:"address"`
+	Year      int    `json:"year"`
+	Developer string `json:"developer,omitempty"`
+}
+
+type SearchApartment struct {
+	Apartment
+	House SearchHouse `json:"house"`
+}
+
+type SearchResult struct {
+	Total      *int              `json:"total,omitempty"`
+	Offset     int               `json:"offset"`
+	Limit      int               `json:"limit"`
+	Apartments []SearchApartment `json:"apartments"`
+}
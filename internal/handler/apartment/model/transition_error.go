@@ -0,0 +1,8 @@
+package apartmenthandlermodel
+
+type TransitionError struct {
+	Error           string   `json:"error"`
+	From            string   `json:"from"`
+	To              string   `json:"to"`
+	AllowedStatuses []string `json:"allowed_statuses"`
+}
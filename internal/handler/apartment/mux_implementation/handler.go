@@ -6,12 +6,15 @@ import (
 	apartmenthandlermodel "avito/internal/handler/apartment/model"
 	userhandler "avito/internal/handler/user"
 	"avito/internal/middleware"
+	"avito/internal/model"
 	apartmentservice "avito/internal/service/apartment"
 	"avito/internal/validator"
 	"avito/pkg/logger"
+	"avito/pkg/revocation"
 	tokenmanager "avito/pkg/token_manager"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"github.com/gorilla/mux"
 	"log/slog"
 	"net/http"
@@ -21,9 +24,9 @@ import (
 
 const (
 	defaultModerationStatus = "created"
+	defaultSearchLimit      = 20
+	maxSearchLimit          = 100
 	moderator               = "moderator"
-
-	defaultLimit = 20
 )
 
 const (
@@ -56,6 +59,8 @@ func (h *handler) Create() http.HandlerFunc {
 			return
 		}
 		apartment.ModerationStatus = defaultModerationStatus
+		apartment.DeclineReason = ""
+		apartment.ModeratorComment = ""
 
 		//VALIDATION
 		if err := h.validator.Validate(apartment); err != nil {
@@ -64,12 +69,68 @@ func (h *handler) Create() http.HandlerFunc {
 			return
 		}
 
+		ownerID, ok := middleware.UserIDFromContext(r.Context())
+		if !ok {
+			l.Error("Failed to get user id from context")
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
 		apartmentDTO := apartmenthandlerconverter.ToApartmentDTO(apartment)
-		if err := h.apartmentService.Create(r.Context(), apartmentDTO); err != nil {
+		apartmentDTO.OwnerID = ownerID
+		created, err := h.apartmentService.Create(r.Context(), apartmentDTO)
+		if err != nil {
 			switch {
 			case errors.Is(err, apartmentservice.ErrInvalidHouseID):
 				http.Error(w, apartmenthandler.ErrInvalidHouseID.Error(), http.StatusBadRequest)
 				return
+			case errors.Is(err, apartmentservice.ErrApartmentAlreadyExists):
+				http.Error(w, apartmenthandler.ErrApartmentAlreadyExists.Error(), http.StatusConflict)
+				return
+			default:
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				return
+			}
+		}
+
+		w.Header().Set(ContentTypeKey, ContentTypeJSON)
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(apartmenthandlerconverter.ToHandlerModelApartment(created))
+	}
+}
+
+func (h *handler) Apartment() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		l := logger.EndToEndLogging(r.Context(), h.logger)
+
+		apartmentIDStr := mux.Vars(r)[apartmenthandler.ApartmentID]
+		apartmentID, err := strconv.ParseInt(apartmentIDStr, 10, 64)
+		if err != nil {
+			l.Error("Invalid apartmentID", "error", err.Error())
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		userID, ok := middleware.UserIDFromContext(r.Context())
+		if !ok {
+			l.Error("Failed to get user id from context")
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		role, ok := middleware.RoleFromContext(r.Context())
+		if !ok {
+			l.Error("Failed to get role from context")
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		apartment, err := h.apartmentService.Apartment(r.Context(), apartmentID, userID, role)
+		if err != nil {
+			switch {
+			case errors.Is(err, apartmentservice.ErrApartmentNotFound):
+				http.Error(w, apartmenthandler.ErrApartmentNotFound.Error(), http.StatusNotFound)
+				return
 			default:
 				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 				return
@@ -78,7 +139,7 @@ func (h *handler) Create() http.HandlerFunc {
 
 		w.Header().Set(ContentTypeKey, ContentTypeJSON)
 		w.WriteHeader(http.StatusOK)
-		json.NewEncoder(w).Encode(apartmenthandlerconverter.ToHandlerModelApartment(apartmentDTO))
+		json.NewEncoder(w).Encode(apartmenthandlerconverter.ToHandlerModelApartment(apartmentservice.Redact(apartment, userID, role)))
 	}
 }
 
@@ -87,32 +148,58 @@ func (h *handler) Update() http.HandlerFunc {
 		l := logger.EndToEndLogging(r.Context(), h.logger)
 
 		apartmentIDStr := mux.Vars(r)[apartmenthandler.ApartmentID]
-		apartmentID, err := strconv.Atoi(apartmentIDStr)
+		apartmentID, err := strconv.ParseInt(apartmentIDStr, 10, 64)
 		if err != nil {
 			l.Error("Invalid apartmentID", "error", err.Error())
 			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
 			return
 		}
 
-		apartment := apartmenthandlermodel.Apartment{}
-		if err = json.NewDecoder(r.Body).Decode(&apartment); err != nil {
+		update := apartmenthandlermodel.ModerationUpdate{}
+		if err = json.NewDecoder(r.Body).Decode(&update); err != nil {
 			l.Error("Failed to decode request body", "error", err.Error())
 			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
 			return
 		}
-		apartment.ID = uint32(apartmentID)
 
-		if err = h.validator.Validate(apartment); err != nil {
+		if err = h.validator.Validate(update); err != nil {
 			l.Error("Invalid data", "error", err.Error())
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
 
-		apartmentDTO := apartmenthandlerconverter.ToApartmentDTO(apartment)
-		if err = h.apartmentService.Update(r.Context(), apartmentDTO); err != nil {
+		moderatorID, ok := middleware.UserIDFromContext(r.Context())
+		if !ok {
+			l.Error("Failed to get user id from context")
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		apartment, err := h.apartmentService.Update(r.Context(), apartmenthandlerconverter.ToModerationUpdateDTO(apartmentID, update), moderatorID)
+		if err != nil {
+			var transitionErr *apartmentservice.TransitionError
 			switch {
-			case errors.Is(err, apartmentservice.ErrInvalidHouseID):
-				http.Error(w, apartmenthandler.ErrInvalidHouseID.Error(), http.StatusBadRequest)
+			case errors.As(err, &transitionErr):
+				w.Header().Set(ContentTypeKey, ContentTypeJSON)
+				w.WriteHeader(http.StatusConflict)
+				json.NewEncoder(w).Encode(apartmenthandlermodel.TransitionError{
+					Error:           apartmenthandler.ErrInvalidTransition.Error(),
+					From:            transitionErr.From,
+					To:              transitionErr.To,
+					AllowedStatuses: transitionErr.Allowed,
+				})
+				return
+			case errors.Is(err, apartmentservice.ErrDeclineReasonRequired):
+				http.Error(w, apartmenthandler.ErrDeclineReasonRequired.Error(), http.StatusBadRequest)
+				return
+			case errors.Is(err, apartmentservice.ErrApartmentNotFound):
+				http.Error(w, apartmenthandler.ErrApartmentNotFound.Error(), http.StatusNotFound)
+				return
+			case errors.Is(err, apartmentservice.ErrModerationConflict):
+				http.Error(w, apartmenthandler.ErrModerationConflict.Error(), http.StatusConflict)
+				return
+			case errors.Is(err, apartmentservice.ErrApartmentAlreadyExists):
+				http.Error(w, apartmenthandler.ErrApartmentAlreadyExists.Error(), http.StatusConflict)
 				return
 			default:
 				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
@@ -122,22 +209,156 @@ func (h *handler) Update() http.HandlerFunc {
 
 		w.Header().Set(ContentTypeKey, ContentTypeJSON)
 		w.WriteHeader(http.StatusOK)
-		json.NewEncoder(w).Encode(apartmenthandlerconverter.ToHandlerModelApartment(apartmentDTO))
+		json.NewEncoder(w).Encode(apartmenthandlerconverter.ToHandlerModelApartment(apartment))
 	}
 }
 
-func (h *handler) Apartments() http.HandlerFunc {
+func (h *handler) Edit() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		l := logger.EndToEndLogging(r.Context(), h.logger)
 
-		houseIDStr := mux.Vars(r)[apartmenthandler.HouseID]
-		houseID, err := strconv.Atoi(houseIDStr)
+		apartmentIDStr := mux.Vars(r)[apartmenthandler.ApartmentID]
+		apartmentID, err := strconv.ParseInt(apartmentIDStr, 10, 64)
 		if err != nil {
-			l.Error("Invalid houseID", "error", err.Error())
+			l.Error("Invalid apartmentID", "error", err.Error())
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		apartmentEdit := apartmenthandlermodel.ApartmentEdit{}
+		if err = json.NewDecoder(r.Body).Decode(&apartmentEdit); err != nil {
+			l.Error("Failed to decode request body", "error", err.Error())
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		if err = h.validator.Validate(apartmentEdit); err != nil {
+			l.Error("Invalid data", "error", err.Error())
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		ownerID, ok := middleware.UserIDFromContext(r.Context())
+		if !ok {
+			l.Error("Failed to get user id from context")
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		apartment, err := h.apartmentService.Edit(r.Context(), apartmenthandlerconverter.ToApartmentEditDTO(apartmentID, apartmentEdit), ownerID)
+		if err != nil {
+			switch {
+			case errors.Is(err, apartmentservice.ErrApartmentNotFound):
+				http.Error(w, apartmenthandler.ErrApartmentNotFound.Error(), http.StatusNotFound)
+				return
+			case errors.Is(err, apartmentservice.ErrNotApartmentOwner):
+				http.Error(w, apartmenthandler.ErrNotApartmentOwner.Error(), http.StatusForbidden)
+				return
+			case errors.Is(err, apartmentservice.ErrModerationConflict):
+				http.Error(w, apartmenthandler.ErrModerationConflict.Error(), http.StatusConflict)
+				return
+			default:
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				return
+			}
+		}
+
+		w.Header().Set(ContentTypeKey, ContentTypeJSON)
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(apartmenthandlerconverter.ToHandlerModelApartment(apartment))
+	}
+}
+
+func (h *handler) Withdraw() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		l := logger.EndToEndLogging(r.Context(), h.logger)
+
+		apartmentIDStr := mux.Vars(r)[apartmenthandler.ApartmentID]
+		apartmentID, err := strconv.ParseInt(apartmentIDStr, 10, 64)
+		if err != nil {
+			l.Error("Invalid apartmentID", "error", err.Error())
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		ownerID, ok := middleware.UserIDFromContext(r.Context())
+		if !ok {
+			l.Error("Failed to get user id from context")
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		apartment, err := h.apartmentService.Withdraw(r.Context(), apartmentID, ownerID)
+		if err != nil {
+			switch {
+			case errors.Is(err, apartmentservice.ErrApartmentNotFound):
+				http.Error(w, apartmenthandler.ErrApartmentNotFound.Error(), http.StatusNotFound)
+				return
+			case errors.Is(err, apartmentservice.ErrNotApartmentOwner):
+				http.Error(w, apartmenthandler.ErrNotApartmentOwner.Error(), http.StatusForbidden)
+				return
+			default:
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				return
+			}
+		}
+
+		w.Header().Set(ContentTypeKey, ContentTypeJSON)
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(apartmenthandlerconverter.ToHandlerModelApartment(apartment))
+	}
+}
+
+func (h *handler) Remove() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		l := logger.EndToEndLogging(r.Context(), h.logger)
+
+		apartmentIDStr := mux.Vars(r)[apartmenthandler.ApartmentID]
+		apartmentID, err := strconv.ParseInt(apartmentIDStr, 10, 64)
+		if err != nil {
+			l.Error("Invalid apartmentID", "error", err.Error())
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		removal := apartmenthandlermodel.Removal{}
+		if err = json.NewDecoder(r.Body).Decode(&removal); err != nil {
+			l.Error("Failed to decode request body", "error", err.Error())
 			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
 			return
 		}
 
+		if err = h.validator.Validate(removal); err != nil {
+			l.Error("Invalid data", "error", err.Error())
+			http.Error(w, apartmenthandler.ErrRemovalReasonRequired.Error(), http.StatusBadRequest)
+			return
+		}
+
+		apartment, err := h.apartmentService.Remove(r.Context(), apartmentID, removal.Reason)
+		if err != nil {
+			switch {
+			case errors.Is(err, apartmentservice.ErrRemovalReasonRequired):
+				http.Error(w, apartmenthandler.ErrRemovalReasonRequired.Error(), http.StatusBadRequest)
+				return
+			case errors.Is(err, apartmentservice.ErrApartmentNotFound):
+				http.Error(w, apartmenthandler.ErrApartmentNotFound.Error(), http.StatusNotFound)
+				return
+			default:
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				return
+			}
+		}
+
+		w.Header().Set(ContentTypeKey, ContentTypeJSON)
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(apartmenthandlerconverter.ToHandlerModelApartment(apartment))
+	}
+}
+
+func (h *handler) Search() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		l := logger.EndToEndLogging(r.Context(), h.logger)
+
 		//PARSE URL PARAMS
 		u, err := url.Parse(r.RequestURI)
 		if err != nil {
@@ -153,25 +374,60 @@ func (h *handler) Apartments() http.HandlerFunc {
 			return
 		}
 
-		limitStr := values.Get(apartmenthandler.LimitQueryParams)
-		limit, err := strconv.Atoi(limitStr)
+		criteria, err := apartmentSearchCriteria(values)
 		if err != nil {
-			limit = defaultLimit
+			l.Error("Invalid apartment search filter", "error", err.Error())
+			http.Error(w, apartmenthandler.ErrInvalidSearchFilter.Error(), http.StatusBadRequest)
+			return
+		}
+
+		claims, ok := middleware.ClaimsFromContext(r.Context())
+		if !ok {
+			l.Error("Failed to get claims from context")
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		apartments, total, err := h.apartmentService.Search(r.Context(), criteria)
+		if err != nil {
+			l.Error("Failed to search apartments", slog.String("error", err.Error()))
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		result := apartmenthandlermodel.SearchResult{
+			Offset:     criteria.Offset,
+			Limit:      criteria.Limit,
+			Apartments: make([]apartmenthandlermodel.SearchApartment, 0, len(apartments)),
+		}
+		if criteria.WithTotal {
+			result.Total = &total
+		}
+		for _, apartment := range apartments {
+			apartment.Apartment = apartmentservice.Redact(apartment.Apartment, claims.UserID, claims.Role)
+			result.Apartments = append(result.Apartments, apartmenthandlerconverter.ToSearchApartment(apartment))
 		}
 
-		offsetStr := values.Get(apartmenthandler.OffsetQueryParams)
-		offset, _ := strconv.Atoi(offsetStr)
+		w.Header().Set(ContentTypeKey, ContentTypeJSON)
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(result)
+	}
+}
 
-		role, ok := r.Context().Value(middleware.RoleCtxKey).(string)
+func (h *handler) MyApartments() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		l := logger.EndToEndLogging(r.Context(), h.logger)
+
+		ownerID, ok := middleware.UserIDFromContext(r.Context())
 		if !ok {
-			l.Error("Failed to get role from context")
+			l.Error("Failed to get user id from context")
 			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
 			return
 		}
 
-		apartments, err := h.apartmentService.Apartments(r.Context(), uint32(houseID), offset, limit, role)
+		apartments, err := h.apartmentService.OwnerApartments(r.Context(), ownerID)
 		if err != nil {
-			l.Error("Failed to get apartments", slog.String("error", err.Error()))
+			l.Error("Failed to get owner apartments", slog.String("error", err.Error()))
 			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 			return
 		}
@@ -187,7 +443,172 @@ func (h *handler) Apartments() http.HandlerFunc {
 	}
 }
 
-func Register(router *mux.Router, apartmentService apartmentservice.Service, tm tokenmanager.Manager, logger *slog.Logger) error {
+func (h *handler) NextForModeration() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		l := logger.EndToEndLogging(r.Context(), h.logger)
+
+		//PARSE URL PARAMS
+		u, err := url.Parse(r.RequestURI)
+		if err != nil {
+			l.Error("Failed to parse request URI", slog.String("error", err.Error()))
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		values, err := url.ParseQuery(u.RawQuery)
+		if err != nil {
+			l.Error("Failed to parse query parameters", slog.String("error", err.Error()))
+			http.Error(w, userhandler.ErrInvalidURLParams.Error(), http.StatusBadRequest)
+			return
+		}
+
+		filter, err := moderationQueueFilter(values)
+		if err != nil {
+			l.Error("Invalid moderation queue filter", "error", err.Error())
+			http.Error(w, apartmenthandler.ErrInvalidQueueFilter.Error(), http.StatusBadRequest)
+			return
+		}
+
+		moderatorID, ok := middleware.UserIDFromContext(r.Context())
+		if !ok {
+			l.Error("Failed to get user id from context")
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		apartment, err := h.apartmentService.NextForModeration(r.Context(), moderatorID, filter)
+		if err != nil {
+			switch {
+			case errors.Is(err, apartmentservice.ErrModerationQueueEmpty):
+				http.Error(w, apartmenthandler.ErrModerationQueueEmpty.Error(), http.StatusNotFound)
+				return
+			default:
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				return
+			}
+		}
+
+		w.Header().Set(ContentTypeKey, ContentTypeJSON)
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(apartmenthandlerconverter.ToHandlerModelApartment(apartment))
+	}
+}
+
+func moderationQueueFilter(values url.Values) (filter model.ModerationQueueFilter, err error) {
+	if str := values.Get(apartmenthandler.HouseIDQueryParam); str != "" {
+		if filter.HouseID, err = strconv.ParseInt(str, 10, 64); err != nil {
+			return model.ModerationQueueFilter{}, err
+		}
+	}
+
+	params := []struct {
+		name  string
+		value *uint32
+	}{
+		{name: apartmenthandler.MinPriceQueryParam, value: &filter.MinPrice},
+		{name: apartmenthandler.MaxPriceQueryParam, value: &filter.MaxPrice},
+	}
+
+	for _, param := range params {
+		str := values.Get(param.name)
+		if str == "" {
+			continue
+		}
+
+		v, err := strconv.ParseUint(str, 10, 32)
+		if err != nil {
+			return model.ModerationQueueFilter{}, err
+		}
+		*param.value = uint32(v)
+	}
+
+	return filter, nil
+}
+
+func apartmentSearchCriteria(values url.Values) (criteria model.ApartmentSearchCriteria, err error) {
+	criteria.Limit = defaultSearchLimit
+
+	uintParams := []struct {
+		name  string
+		value *uint32
+	}{
+		{name: apartmenthandler.MinPriceQueryParam, value: &criteria.MinPrice},
+		{name: apartmenthandler.MaxPriceQueryParam, value: &criteria.MaxPrice},
+		{name: apartmenthandler.MinRoomsQueryParam, value: &criteria.MinRooms},
+		{name: apartmenthandler.MaxRoomsQueryParam, value: &criteria.MaxRooms},
+	}
+
+	for _, param := range uintParams {
+		str := values.Get(param.name)
+		if str == "" {
+			continue
+		}
+
+		v, err := strconv.ParseUint(str, 10, 32)
+		if err != nil {
+			return model.ApartmentSearchCriteria{}, err
+		}
+		*param.value = uint32(v)
+	}
+
+	intParams := []struct {
+		name  string
+		value *int
+	}{
+		{name: apartmenthandler.MinYearQueryParam, value: &criteria.MinYear},
+		{name: apartmenthandler.MaxYearQueryParam, value: &criteria.MaxYear},
+		{name: apartmenthandler.OffsetQueryParam, value: &criteria.Offset},
+		{name: apartmenthandler.LimitQueryParam, value: &criteria.Limit},
+	}
+
+	for _, param := range intParams {
+		str := values.Get(param.name)
+		if str == "" {
+			continue
+		}
+
+		v, err := strconv.Atoi(str)
+		if err != nil || v < 0 {
+			return model.ApartmentSearchCriteria{}, fmt.Errorf("invalid %s %q", param.name, str)
+		}
+		*param.value = v
+	}
+
+	//AN EXACT NUMBER OF ROOMS IS A RANGE OF ONE
+	if str := values.Get(apartmenthandler.RoomsQueryParam); str != "" {
+		if criteria.MinRooms != 0 || criteria.MaxRooms != 0 {
+			return model.ApartmentSearchCriteria{}, fmt.Errorf("%s can't be combined with a rooms range", apartmenthandler.RoomsQueryParam)
+		}
+
+		v, err := strconv.ParseUint(str, 10, 32)
+		if err != nil {
+			return model.ApartmentSearchCriteria{}, err
+		}
+		criteria.MinRooms, criteria.MaxRooms = uint32(v), uint32(v)
+	}
+
+	if criteria.Limit == 0 || criteria.Limit > maxSearchLimit {
+		return model.ApartmentSearchCriteria{}, fmt.Errorf("%s must be between 1 and %d", apartmenthandler.LimitQueryParam, maxSearchLimit)
+	}
+
+	if criteria.MaxPrice != 0 && criteria.MinPrice > criteria.MaxPrice ||
+		criteria.MaxRooms != 0 && criteria.MinRooms > criteria.MaxRooms ||
+		criteria.MaxYear != 0 && criteria.MinYear > criteria.MaxYear {
+		return model.ApartmentSearchCriteria{}, errors.New("lower bound of a range is greater than the upper one")
+	}
+
+	criteria.Developer = values.Get(apartmenthandler.DeveloperQueryParam)
+
+	if str := values.Get(apartmenthandler.WithTotalQueryParam); str != "" {
+		if criteria.WithTotal, err = strconv.ParseBool(str); err != nil {
+			return model.ApartmentSearchCriteria{}, err
+		}
+	}
+
+	return criteria, nil
+}
+
+func Register(router *mux.Router, apartmentService apartmentservice.Service, tm tokenmanager.Manager, revoked revocation.List, logger *slog.Logger) error {
 	h := &handler{
 		router:           router,
 		apartmentService: apartmentService,
@@ -201,15 +622,29 @@ func Register(router *mux.Router, apartmentService apartmentservice.Service, tm
 		return err
 	}
 
+	if err := h.validator.RegisterTag(validator.DeclineReasonTag, apartmenthandlermodel.DeclineReasonValidation); err != nil {
+		logger.Error("Failed to register decline reason validation", "error", err.Error())
+		return err
+	}
+
 	apiRouter := router.PathPrefix(apartmenthandler.APIUrl).Subrouter()
-	apiRouter.Use(middleware.Log(logger), middleware.AuthOnly(tm))
+	apiRouter.Use(middleware.Log(logger), middleware.AuthOnly(tm, revoked))
 
 	apiRouter.Path(apartmenthandler.CreateApartmentUrl).Handler(h.Create()).Methods(http.MethodPost)
-	apiRouter.Path(apartmenthandler.ApartmentsByHouseIDUrl).Handler(h.Apartments()).Methods(http.MethodGet)
+	apiRouter.Path(apartmenthandler.SearchApartmentsUrl).Handler(h.Search()).Methods(http.MethodGet)
+	apiRouter.Path(apartmenthandler.ApartmentByIDUrl).Handler(h.Apartment()).Methods(http.MethodGet)
+
+	ownerRouter := apiRouter.NewRoute().Subrouter()
+	ownerRouter.Use(middleware.RegisteredOnly())
+	ownerRouter.Path(apartmenthandler.EditApartmentUrl).Handler(h.Edit()).Methods(http.MethodPut)
+	ownerRouter.Path(apartmenthandler.WithdrawApartmentUrl).Handler(h.Withdraw()).Methods(http.MethodPost)
+	ownerRouter.Path(apartmenthandler.MyApartmentsUrl).Handler(h.MyApartments()).Methods(http.MethodGet)
 
 	moderationRouter := apiRouter.NewRoute().Subrouter()
-	moderationRouter.Use(middleware.CheckRole(tm, moderator))
+	moderationRouter.Use(middleware.CheckRole(tm, revoked, moderator))
 	moderationRouter.Path(apartmenthandler.UpdateApartmentUrl).Handler(h.Update()).Methods(http.MethodPut)
+	moderationRouter.Path(apartmenthandler.RemoveApartmentUrl).Handler(h.Remove()).Methods(http.MethodPost)
+	moderationRouter.Path(apartmenthandler.NextForModerationUrl).Handler(h.NextForModeration()).Methods(http.MethodGet)
 
 	return nil
 }
@@ -4,14 +4,16 @@ import (
 	apartmenthandler "avito/internal/handler/apartment"
 	apartmenthandlermodel "avito/internal/handler/apartment/model"
 	"avito/internal/middleware"
+	"avito/internal/model"
 	apartmentservice "avito/internal/service/apartment"
+	"avito/internal/validator"
+	"avito/pkg/revocation/memory"
 	stubwriter "avito/pkg/stub_writer"
 	tokenmanager "avito/pkg/token_manager"
-	tokenmanagerimpl "avito/pkg/token_manager/implementation"
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
@@ -19,9 +21,9 @@ import (
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strings"
 	"testing"
-	"time"
 )
 
 func TestCreate(t *testing.T) {
@@ -50,13 +52,16 @@ func TestCreate(t *testing.T) {
 				req := httptest.NewRequest(http.MethodPost, apartmenthandler.APIUrl+apartmenthandler.CreateApartmentUrl, bytes.NewReader(apartmentBytes))
 				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
 
-				m := make(jwt.MapClaims)
-				m[tokenmanagerimpl.UserIDClaimsTag] = float64(uuid.New().ID())
-				m[tokenmanagerimpl.RoleClaimsTag] = "client"
-				m[tokenmanagerimpl.ExpClaimsTag] = float64(time.Now().Add(5 * time.Minute).Unix())
+				ownerID := int64(uuid.New().ID())
 
-				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(m, nil)
-				mockApartmentService.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+				claims := tokenmanager.Claims{UserID: ownerID, Role: "client"}
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockApartmentService.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, apartment model.Apartment) (model.Apartment, error) {
+					assert.Equal(t, ownerID, apartment.OwnerID)
+					apartment.ID = 1
+					return apartment, nil
+				})
 
 				return req
 			},
@@ -112,12 +117,9 @@ func TestCreateErr(t *testing.T) {
 				req := httptest.NewRequest(http.MethodPost, apartmenthandler.APIUrl+apartmenthandler.CreateApartmentUrl, bytes.NewReader(apartmentBytes))
 				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
 
-				m := make(jwt.MapClaims)
-				m[tokenmanagerimpl.UserIDClaimsTag] = float64(uuid.New().ID())
-				m[tokenmanagerimpl.RoleClaimsTag] = "client"
-				m[tokenmanagerimpl.ExpClaimsTag] = float64(time.Now().Add(5 * time.Minute).Unix())
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "client"}
 
-				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(m, nil)
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
 
 				return req
 			},
@@ -140,13 +142,36 @@ func TestCreateErr(t *testing.T) {
 				req := httptest.NewRequest(http.MethodPost, apartmenthandler.APIUrl+apartmenthandler.CreateApartmentUrl, bytes.NewReader(apartmentBytes))
 				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
 
-				m := make(jwt.MapClaims)
-				m[tokenmanagerimpl.UserIDClaimsTag] = float64(uuid.New().ID())
-				m[tokenmanagerimpl.RoleClaimsTag] = "client"
-				m[tokenmanagerimpl.ExpClaimsTag] = float64(time.Now().Add(5 * time.Minute).Unix())
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "client"}
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockApartmentService.EXPECT().Create(gomock.Any(), gomock.Any()).Return(model.Apartment{}, apartmentservice.ErrInvalidHouseID)
+
+				return req
+			},
+		},
+		{
+			name:           "ERR APARTMENT ALREADY EXISTS",
+			statusCode:     http.StatusConflict,
+			expectedErrMsg: apartmenthandler.ErrApartmentAlreadyExists.Error(),
+			prepareFunc: func() *http.Request {
+				apartment := apartmenthandlermodel.Apartment{
+					ApartmentNumber: 1,
+					HouseID:         1,
+					Price:           1,
+					NumberOfRooms:   1,
+				}
+
+				apartmentBytes, err := json.Marshal(apartment)
+				assert.NoError(t, err)
+
+				req := httptest.NewRequest(http.MethodPost, apartmenthandler.APIUrl+apartmenthandler.CreateApartmentUrl, bytes.NewReader(apartmentBytes))
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "client"}
 
-				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(m, nil)
-				mockApartmentService.EXPECT().Create(gomock.Any(), gomock.Any()).Return(apartmentservice.ErrInvalidHouseID)
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockApartmentService.EXPECT().Create(gomock.Any(), gomock.Any()).Return(model.Apartment{}, apartmentservice.ErrApartmentAlreadyExists)
 
 				return req
 			},
@@ -169,13 +194,171 @@ func TestCreateErr(t *testing.T) {
 				req := httptest.NewRequest(http.MethodPost, apartmenthandler.APIUrl+apartmenthandler.CreateApartmentUrl, bytes.NewReader(apartmentBytes))
 				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
 
-				m := make(jwt.MapClaims)
-				m[tokenmanagerimpl.UserIDClaimsTag] = float64(uuid.New().ID())
-				m[tokenmanagerimpl.RoleClaimsTag] = "client"
-				m[tokenmanagerimpl.ExpClaimsTag] = float64(time.Now().Add(5 * time.Minute).Unix())
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "client"}
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockApartmentService.EXPECT().Create(gomock.Any(), gomock.Any()).Return(model.Apartment{}, apartmentservice.ErrInternal)
+
+				return req
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := c.prepareFunc()
+
+			recorder := httptest.NewRecorder()
+
+			router.ServeHTTP(recorder, req)
+
+			assert.Equal(t, c.statusCode, recorder.Code)
+		})
+	}
+}
+
+func TestApartment(t *testing.T) {
+	ctrl, mockApartmentService, mockTokenManager, router := testHandler(t)
+	defer ctrl.Finish()
+
+	userID := int64(uuid.New().ID())
+
+	cases := []struct {
+		name            string
+		statusCode      int
+		expectedOwnerID int64
+		prepareFunc     func() *http.Request
+	}{
+		{
+			//THE SELLER AND THE MODERATOR STAY HIDDEN FROM OTHER CLIENTS
+			name:       "OK CLIENT",
+			statusCode: http.StatusOK,
+			prepareFunc: func() *http.Request {
+				req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("%s%s/%s", apartmenthandler.APIUrl, apartmenthandler.ApartmentsUrl, "1"), http.NoBody)
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				claims := tokenmanager.Claims{UserID: userID, Role: "client"}
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockApartmentService.EXPECT().Apartment(gomock.Any(), int64(1), userID, "client").Return(model.Apartment{ID: 1, ModerationStatus: "approved", OwnerID: userID + 1, ModeratorID: userID + 2}, nil)
+
+				return req
+			},
+		},
+		{
+			name:            "OK MODERATOR",
+			statusCode:      http.StatusOK,
+			expectedOwnerID: userID + 1,
+			prepareFunc: func() *http.Request {
+				req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("%s%s/%s", apartmenthandler.APIUrl, apartmenthandler.ApartmentsUrl, "1"), http.NoBody)
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				claims := tokenmanager.Claims{UserID: userID, Role: "moderator"}
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockApartmentService.EXPECT().Apartment(gomock.Any(), int64(1), userID, "moderator").Return(model.Apartment{ID: 1, ModerationStatus: "declined", OwnerID: userID + 1}, nil)
+
+				return req
+			},
+		},
+		{
+			name:            "OK OWNER",
+			statusCode:      http.StatusOK,
+			expectedOwnerID: userID,
+			prepareFunc: func() *http.Request {
+				req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("%s%s/%s", apartmenthandler.APIUrl, apartmenthandler.ApartmentsUrl, "1"), http.NoBody)
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				claims := tokenmanager.Claims{UserID: userID, Role: "client"}
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockApartmentService.EXPECT().Apartment(gomock.Any(), int64(1), userID, "client").Return(model.Apartment{ID: 1, ModerationStatus: "declined", OwnerID: userID}, nil)
+
+				return req
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := c.prepareFunc()
+
+			recorder := httptest.NewRecorder()
+
+			router.ServeHTTP(recorder, req)
+
+			assert.Equal(t, c.statusCode, recorder.Code)
+
+			apartment := apartmenthandlermodel.Apartment{}
+			assert.NoError(t, json.NewDecoder(recorder.Body).Decode(&apartment))
+			assert.Equal(t, c.expectedOwnerID, apartment.OwnerID)
+		})
+	}
+}
+
+func TestApartmentErr(t *testing.T) {
+	ctrl, mockApartmentService, mockTokenManager, router := testHandler(t)
+	defer ctrl.Finish()
+
+	userID := int64(uuid.New().ID())
+
+	cases := []struct {
+		name           string
+		statusCode     int
+		expectedErrMsg string
+		prepareFunc    func() *http.Request
+	}{
+		{
+			name:       "UNAUTHORIZED",
+			statusCode: http.StatusUnauthorized,
+			prepareFunc: func() *http.Request {
+				req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("%s%s/%d", apartmenthandler.APIUrl, apartmenthandler.ApartmentsUrl, 1), http.NoBody)
+
+				return req
+			},
+		},
+		{
+			name:       "ERR INVALID APARTMENT ID",
+			statusCode: http.StatusBadRequest,
+			prepareFunc: func() *http.Request {
+				req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("%s%s/%s", apartmenthandler.APIUrl, apartmenthandler.ApartmentsUrl, "99999999999999999999"), http.NoBody)
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				claims := tokenmanager.Claims{UserID: userID, Role: "client"}
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+
+				return req
+			},
+		},
+		{
+			name:           "ERR NOT FOUND",
+			statusCode:     http.StatusNotFound,
+			expectedErrMsg: apartmenthandler.ErrApartmentNotFound.Error(),
+			prepareFunc: func() *http.Request {
+				req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("%s%s/%s", apartmenthandler.APIUrl, apartmenthandler.ApartmentsUrl, "1"), http.NoBody)
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				claims := tokenmanager.Claims{UserID: userID, Role: "client"}
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockApartmentService.EXPECT().Apartment(gomock.Any(), int64(1), gomock.Any(), gomock.Any()).Return(model.Apartment{}, apartmentservice.ErrApartmentNotFound)
+
+				return req
+			},
+		},
+		{
+			name:           "ERR INTERNAL",
+			statusCode:     http.StatusInternalServerError,
+			expectedErrMsg: http.StatusText(http.StatusInternalServerError),
+			prepareFunc: func() *http.Request {
+				req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("%s%s/%s", apartmenthandler.APIUrl, apartmenthandler.ApartmentsUrl, "1"), http.NoBody)
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				claims := tokenmanager.Claims{UserID: userID, Role: "client"}
 
-				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(m, nil)
-				mockApartmentService.EXPECT().Create(gomock.Any(), gomock.Any()).Return(apartmentservice.ErrInternal)
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockApartmentService.EXPECT().Apartment(gomock.Any(), int64(1), gomock.Any(), gomock.Any()).Return(model.Apartment{}, apartmentservice.ErrInternal)
 
 				return req
 			},
@@ -191,6 +374,7 @@ func TestCreateErr(t *testing.T) {
 			router.ServeHTTP(recorder, req)
 
 			assert.Equal(t, c.statusCode, recorder.Code)
+			assert.Contains(t, recorder.Body.String(), c.expectedErrMsg)
 		})
 	}
 }
@@ -224,14 +408,51 @@ func TestUpdate(t *testing.T) {
 				req := httptest.NewRequest(http.MethodPut, updateUrl, bytes.NewReader(apartmentBytes))
 				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
 
-				m := make(jwt.MapClaims)
-				m[tokenmanagerimpl.UserIDClaimsTag] = float64(uuid.New().ID())
-				m[tokenmanagerimpl.RoleClaimsTag] = "moderator"
-				m[tokenmanagerimpl.ExpClaimsTag] = float64(time.Now().Add(5 * time.Minute).Unix())
+				moderatorID := int64(uuid.New().ID())
+
+				claims := tokenmanager.Claims{UserID: moderatorID, Role: "moderator"}
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockApartmentService.EXPECT().Update(gomock.Any(), gomock.Any(), moderatorID).DoAndReturn(func(_ context.Context, apartment model.Apartment, _ int64) (model.Apartment, error) {
+					assert.Equal(t, int64(1), apartment.ID)
+					return apartment, nil
+				})
+
+				return req
+			},
+		},
+		{
+			name:       "OK DECLINED",
+			statusCode: http.StatusOK,
+			prepareFunc: func() *http.Request {
+				apartment := apartmenthandlermodel.Apartment{
+					ApartmentNumber:  1,
+					HouseID:          1,
+					Price:            1,
+					NumberOfRooms:    1,
+					ModerationStatus: "declined",
+					DeclineReason:    "wrong_price",
+					ModeratorComment: "price is ten times above the market",
+				}
+
+				apartmentBytes, err := json.Marshal(apartment)
+				assert.NoError(t, err)
+
+				updateUrl := strings.ReplaceAll(apartmenthandler.APIUrl+apartmenthandler.UpdateApartmentUrl, fmt.Sprintf("{%s}", apartmenthandler.ApartmentID), "1")
+
+				req := httptest.NewRequest(http.MethodPut, updateUrl, bytes.NewReader(apartmentBytes))
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "moderator"}
 
-				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(m, nil)
-				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(m, nil)
-				mockApartmentService.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockApartmentService.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, apartment model.Apartment, _ int64) (model.Apartment, error) {
+					assert.Equal(t, "wrong_price", apartment.DeclineReason)
+					assert.Equal(t, "price is ten times above the market", apartment.ModeratorComment)
+					return apartment, nil
+				})
 
 				return req
 			},
@@ -292,13 +513,10 @@ func TestUpdateErr(t *testing.T) {
 				req := httptest.NewRequest(http.MethodPut, updateUrl, bytes.NewReader(apartmentBytes))
 				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
 
-				m := make(jwt.MapClaims)
-				m[tokenmanagerimpl.UserIDClaimsTag] = float64(uuid.New().ID())
-				m[tokenmanagerimpl.RoleClaimsTag] = "moderator"
-				m[tokenmanagerimpl.ExpClaimsTag] = float64(time.Now().Add(5 * time.Minute).Unix())
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "moderator"}
 
-				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(m, nil)
-				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(m, nil)
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
 
 				return req
 			},
@@ -317,28 +535,27 @@ func TestUpdateErr(t *testing.T) {
 				req := httptest.NewRequest(http.MethodPut, updateUrl, bytes.NewReader(apartmentBytes))
 				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
 
-				m := make(jwt.MapClaims)
-				m[tokenmanagerimpl.UserIDClaimsTag] = float64(uuid.New().ID())
-				m[tokenmanagerimpl.RoleClaimsTag] = "client"
-				m[tokenmanagerimpl.ExpClaimsTag] = float64(time.Now().Add(5 * time.Minute).Unix())
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "client"}
 
-				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(m, nil)
-				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(m, nil)
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
 
 				return req
 			},
 		},
 		{
-			name:           "ERR INVALID HOUSE ID",
+			name:           "ERR INVALID DECLINE REASON",
 			statusCode:     http.StatusBadRequest,
-			expectedErrMsg: apartmenthandler.ErrInvalidHouseID.Error(),
+			expectedErrMsg: validator.ErrInvalidDeclineReason.Error(),
 			prepareFunc: func() *http.Request {
 				apartment := apartmenthandlermodel.Apartment{
 					ApartmentNumber:  1,
 					HouseID:          1,
 					Price:            1,
 					NumberOfRooms:    1,
-					ModerationStatus: "approved",
+					ModerationStatus: "declined",
+					DeclineReason:    "too_expensive",
+					ModeratorComment: "price is too high",
 				}
 
 				apartmentBytes, err := json.Marshal(apartment)
@@ -349,29 +566,27 @@ func TestUpdateErr(t *testing.T) {
 				req := httptest.NewRequest(http.MethodPut, updateUrl, bytes.NewReader(apartmentBytes))
 				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
 
-				m := make(jwt.MapClaims)
-				m[tokenmanagerimpl.UserIDClaimsTag] = float64(uuid.New().ID())
-				m[tokenmanagerimpl.RoleClaimsTag] = "moderator"
-				m[tokenmanagerimpl.ExpClaimsTag] = float64(time.Now().Add(5 * time.Minute).Unix())
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "moderator"}
 
-				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(m, nil)
-				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(m, nil)
-				mockApartmentService.EXPECT().Update(gomock.Any(), gomock.Any()).Return(apartmentservice.ErrInvalidHouseID)
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
 
 				return req
 			},
 		},
 		{
-			name:           "ERR INTERNAL",
-			statusCode:     http.StatusInternalServerError,
-			expectedErrMsg: http.StatusText(http.StatusInternalServerError),
+			name:           "ERR DECLINE REASON REQUIRED",
+			statusCode:     http.StatusBadRequest,
+			expectedErrMsg: apartmenthandler.ErrDeclineReasonRequired.Error(),
 			prepareFunc: func() *http.Request {
 				apartment := apartmenthandlermodel.Apartment{
 					ApartmentNumber:  1,
 					HouseID:          1,
 					Price:            1,
 					NumberOfRooms:    1,
-					ModerationStatus: "approved",
+					ModerationStatus: "declined",
+					DeclineReason:    "",
+					ModeratorComment: "",
 				}
 
 				apartmentBytes, err := json.Marshal(apartment)
@@ -382,111 +597,1127 @@ func TestUpdateErr(t *testing.T) {
 				req := httptest.NewRequest(http.MethodPut, updateUrl, bytes.NewReader(apartmentBytes))
 				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
 
-				m := make(jwt.MapClaims)
-				m[tokenmanagerimpl.UserIDClaimsTag] = float64(uuid.New().ID())
-				m[tokenmanagerimpl.RoleClaimsTag] = "moderator"
-				m[tokenmanagerimpl.ExpClaimsTag] = float64(time.Now().Add(5 * time.Minute).Unix())
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "moderator"}
 
-				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(m, nil)
-				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(m, nil)
-				mockApartmentService.EXPECT().Update(gomock.Any(), gomock.Any()).Return(apartmentservice.ErrInternal)
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockApartmentService.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any()).Return(model.Apartment{}, apartmentservice.ErrDeclineReasonRequired)
 
 				return req
 			},
 		},
-	}
-
-	for _, c := range cases {
-		t.Run(c.name, func(t *testing.T) {
-			req := c.prepareFunc()
-
-			recorder := httptest.NewRecorder()
-
-			router.ServeHTTP(recorder, req)
+		{
+			name:           "ERR APARTMENT NOT FOUND",
+			statusCode:     http.StatusNotFound,
+			expectedErrMsg: apartmenthandler.ErrApartmentNotFound.Error(),
+			prepareFunc: func() *http.Request {
+				apartment := apartmenthandlermodel.Apartment{
+					ApartmentNumber:  1,
+					HouseID:          1,
+					Price:            1,
+					NumberOfRooms:    1,
+					ModerationStatus: "approved",
+				}
 
-			assert.Equal(t, c.statusCode, recorder.Code)
-		})
-	}
-}
+				apartmentBytes, err := json.Marshal(apartment)
+				assert.NoError(t, err)
 
-func TestApartments(t *testing.T) {
-	ctrl, mockApartmentService, mockTokenManager, router := testHandler(t)
-	defer ctrl.Finish()
+				updateUrl := strings.ReplaceAll(apartmenthandler.APIUrl+apartmenthandler.UpdateApartmentUrl, fmt.Sprintf("{%s}", apartmenthandler.ApartmentID), "1")
 
-	cases := []struct {
-		name        string
-		statusCode  int
-		prepareFunc func() *http.Request
-	}{
-		{
-			name:       "OK",
-			statusCode: http.StatusOK,
-			prepareFunc: func() *http.Request {
-				apartmentsUrl := strings.ReplaceAll(apartmenthandler.APIUrl+apartmenthandler.ApartmentsByHouseIDUrl, fmt.Sprintf("{%s}", apartmenthandler.HouseID), "1")
-				req := httptest.NewRequest(http.MethodGet, apartmentsUrl, http.NoBody)
+				req := httptest.NewRequest(http.MethodPut, updateUrl, bytes.NewReader(apartmentBytes))
 				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
 
-				m := make(jwt.MapClaims)
-				m[tokenmanagerimpl.UserIDClaimsTag] = float64(uuid.New().ID())
-				m[tokenmanagerimpl.RoleClaimsTag] = "client"
-				m[tokenmanagerimpl.ExpClaimsTag] = float64(time.Now().Add(5 * time.Minute).Unix())
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "moderator"}
 
-				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(m, nil)
-				mockApartmentService.EXPECT().Apartments(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockApartmentService.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any()).Return(model.Apartment{}, apartmentservice.ErrApartmentNotFound)
 
 				return req
 			},
 		},
-	}
+		{
+			name:           "ERR CLAIMED BY ANOTHER MODERATOR",
+			statusCode:     http.StatusConflict,
+			expectedErrMsg: apartmenthandler.ErrModerationConflict.Error(),
+			prepareFunc: func() *http.Request {
+				apartment := apartmenthandlermodel.Apartment{
+					ApartmentNumber:  1,
+					HouseID:          1,
+					Price:            1,
+					NumberOfRooms:    1,
+					ModerationStatus: "approved",
+				}
 
-	for _, c := range cases {
-		t.Run(c.name, func(t *testing.T) {
-			req := c.prepareFunc()
+				apartmentBytes, err := json.Marshal(apartment)
+				assert.NoError(t, err)
 
-			recorder := httptest.NewRecorder()
+				updateUrl := strings.ReplaceAll(apartmenthandler.APIUrl+apartmenthandler.UpdateApartmentUrl, fmt.Sprintf("{%s}", apartmenthandler.ApartmentID), "1")
 
-			router.ServeHTTP(recorder, req)
+				req := httptest.NewRequest(http.MethodPut, updateUrl, bytes.NewReader(apartmentBytes))
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
 
-			assert.Equal(t, c.statusCode, recorder.Code)
-		})
-	}
-}
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "moderator"}
 
-func TestApartmentsErr(t *testing.T) {
-	ctrl, mockApartmentService, mockTokenManager, router := testHandler(t)
-	defer ctrl.Finish()
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockApartmentService.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any()).Return(model.Apartment{}, apartmentservice.ErrModerationConflict)
 
-	cases := []struct {
-		name           string
-		statusCode     int
-		expectedErrMsg string
-		prepareFunc    func() *http.Request
-	}{
+				return req
+			},
+		},
 		{
-			name:           "ERR INVALID HOUSE ID",
-			statusCode:     http.StatusBadRequest,
-			expectedErrMsg: http.StatusText(http.StatusBadRequest),
+			name:           "ERR APARTMENT ALREADY EXISTS",
+			statusCode:     http.StatusConflict,
+			expectedErrMsg: apartmenthandler.ErrApartmentAlreadyExists.Error(),
 			prepareFunc: func() *http.Request {
-				apartmentsUrl := strings.ReplaceAll(apartmenthandler.APIUrl+apartmenthandler.ApartmentsByHouseIDUrl, fmt.Sprintf("{%s}", apartmenthandler.HouseID), "invalid_house_id")
-				req := httptest.NewRequest(http.MethodGet, apartmentsUrl, http.NoBody)
+				apartment := apartmenthandlermodel.Apartment{
+					ApartmentNumber:  1,
+					HouseID:          1,
+					Price:            1,
+					NumberOfRooms:    1,
+					ModerationStatus: "approved",
+				}
+
+				apartmentBytes, err := json.Marshal(apartment)
+				assert.NoError(t, err)
+
+				updateUrl := strings.ReplaceAll(apartmenthandler.APIUrl+apartmenthandler.UpdateApartmentUrl, fmt.Sprintf("{%s}", apartmenthandler.ApartmentID), "1")
+
+				req := httptest.NewRequest(http.MethodPut, updateUrl, bytes.NewReader(apartmentBytes))
 				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
 
-				m := make(jwt.MapClaims)
-				m[tokenmanagerimpl.UserIDClaimsTag] = float64(uuid.New().ID())
-				m[tokenmanagerimpl.RoleClaimsTag] = "client"
-				m[tokenmanagerimpl.ExpClaimsTag] = float64(time.Now().Add(5 * time.Minute).Unix())
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "moderator"}
 
-				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(m, nil)
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockApartmentService.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any()).Return(model.Apartment{}, apartmentservice.ErrApartmentAlreadyExists)
 
 				return req
 			},
 		},
 		{
-			name:       "UNAUTHORIZED",
+			name:           "ERR INVALID TRANSITION",
+			statusCode:     http.StatusConflict,
+			expectedErrMsg: `"allowed_statuses":["on moderation"]`,
+			prepareFunc: func() *http.Request {
+				apartment := apartmenthandlermodel.Apartment{
+					ApartmentNumber:  1,
+					HouseID:          1,
+					Price:            1,
+					NumberOfRooms:    1,
+					ModerationStatus: "approved",
+				}
+
+				apartmentBytes, err := json.Marshal(apartment)
+				assert.NoError(t, err)
+
+				updateUrl := strings.ReplaceAll(apartmenthandler.APIUrl+apartmenthandler.UpdateApartmentUrl, fmt.Sprintf("{%s}", apartmenthandler.ApartmentID), "1")
+
+				req := httptest.NewRequest(http.MethodPut, updateUrl, bytes.NewReader(apartmentBytes))
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "moderator"}
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockApartmentService.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any()).Return(model.Apartment{}, apartmentservice.NewTransitionError(apartmentservice.StatusCreated, apartmentservice.StatusApproved))
+
+				return req
+			},
+		},
+		{
+			name:           "ERR INTERNAL",
+			statusCode:     http.StatusInternalServerError,
+			expectedErrMsg: http.StatusText(http.StatusInternalServerError),
+			prepareFunc: func() *http.Request {
+				apartment := apartmenthandlermodel.Apartment{
+					ApartmentNumber:  1,
+					HouseID:          1,
+					Price:            1,
+					NumberOfRooms:    1,
+					ModerationStatus: "approved",
+				}
+
+				apartmentBytes, err := json.Marshal(apartment)
+				assert.NoError(t, err)
+
+				updateUrl := strings.ReplaceAll(apartmenthandler.APIUrl+apartmenthandler.UpdateApartmentUrl, fmt.Sprintf("{%s}", apartmenthandler.ApartmentID), "1")
+
+				req := httptest.NewRequest(http.MethodPut, updateUrl, bytes.NewReader(apartmentBytes))
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "moderator"}
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockApartmentService.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any()).Return(model.Apartment{}, apartmentservice.ErrInternal)
+
+				return req
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := c.prepareFunc()
+
+			recorder := httptest.NewRecorder()
+
+			router.ServeHTTP(recorder, req)
+
+			assert.Equal(t, c.statusCode, recorder.Code)
+			assert.Contains(t, recorder.Body.String(), c.expectedErrMsg)
+		})
+	}
+}
+
+func TestEdit(t *testing.T) {
+	ctrl, mockApartmentService, mockTokenManager, router := testHandler(t)
+	defer ctrl.Finish()
+
+	cases := []struct {
+		name        string
+		statusCode  int
+		prepareFunc func() *http.Request
+	}{
+		{
+			name:       "OK",
+			statusCode: http.StatusOK,
+			prepareFunc: func() *http.Request {
+				apartmentEdit := apartmenthandlermodel.ApartmentEdit{Price: 100, NumberOfRooms: 2}
+
+				apartmentBytes, err := json.Marshal(apartmentEdit)
+				assert.NoError(t, err)
+
+				editUrl := strings.ReplaceAll(apartmenthandler.APIUrl+apartmenthandler.EditApartmentUrl, fmt.Sprintf("{%s}", apartmenthandler.ApartmentID), "1")
+
+				req := httptest.NewRequest(http.MethodPut, editUrl, bytes.NewReader(apartmentBytes))
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				ownerID := int64(uuid.New().ID())
+
+				claims := tokenmanager.Claims{UserID: ownerID, Role: "client"}
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockApartmentService.EXPECT().Edit(gomock.Any(), gomock.Any(), ownerID).DoAndReturn(func(_ context.Context, apartment model.Apartment, ownerID int64) (model.Apartment, error) {
+					assert.Equal(t, int64(1), apartment.ID)
+					assert.Equal(t, uint32(100), apartment.Price)
+					assert.Equal(t, uint32(2), apartment.NumberOfRooms)
+
+					apartment.OwnerID = ownerID
+					apartment.ModerationStatus = apartmentservice.StatusCreated
+					return apartment, nil
+				})
+
+				return req
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := c.prepareFunc()
+
+			recorder := httptest.NewRecorder()
+
+			router.ServeHTTP(recorder, req)
+
+			assert.Equal(t, c.statusCode, recorder.Code)
+		})
+	}
+}
+
+func TestEditErr(t *testing.T) {
+	ctrl, mockApartmentService, mockTokenManager, router := testHandler(t)
+	defer ctrl.Finish()
+
+	cases := []struct {
+		name           string
+		statusCode     int
+		expectedErrMsg string
+		prepareFunc    func() *http.Request
+	}{
+		{
+			name:       "UNAUTHORIZED",
+			statusCode: http.StatusUnauthorized,
+			prepareFunc: func() *http.Request {
+				editUrl := strings.ReplaceAll(apartmenthandler.APIUrl+apartmenthandler.EditApartmentUrl, fmt.Sprintf("{%s}", apartmenthandler.ApartmentID), "1")
+
+				req := httptest.NewRequest(http.MethodPut, editUrl, http.NoBody)
+
+				return req
+			},
+		},
+		{
+			name:       "INVALID DATA",
+			statusCode: http.StatusBadRequest,
+			prepareFunc: func() *http.Request {
+				apartmentEdit := apartmenthandlermodel.ApartmentEdit{}
+
+				apartmentBytes, err := json.Marshal(apartmentEdit)
+				assert.NoError(t, err)
+
+				editUrl := strings.ReplaceAll(apartmenthandler.APIUrl+apartmenthandler.EditApartmentUrl, fmt.Sprintf("{%s}", apartmenthandler.ApartmentID), "1")
+
+				req := httptest.NewRequest(http.MethodPut, editUrl, bytes.NewReader(apartmentBytes))
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "client"}
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+
+				return req
+			},
+		},
+		{
+			name:           "ERR APARTMENT NOT FOUND",
+			statusCode:     http.StatusNotFound,
+			expectedErrMsg: apartmenthandler.ErrApartmentNotFound.Error(),
+			prepareFunc: func() *http.Request {
+				apartmentEdit := apartmenthandlermodel.ApartmentEdit{Price: 1, NumberOfRooms: 1}
+
+				apartmentBytes, err := json.Marshal(apartmentEdit)
+				assert.NoError(t, err)
+
+				editUrl := strings.ReplaceAll(apartmenthandler.APIUrl+apartmenthandler.EditApartmentUrl, fmt.Sprintf("{%s}", apartmenthandler.ApartmentID), "1")
+
+				req := httptest.NewRequest(http.MethodPut, editUrl, bytes.NewReader(apartmentBytes))
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "client"}
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockApartmentService.EXPECT().Edit(gomock.Any(), gomock.Any(), gomock.Any()).Return(model.Apartment{}, apartmentservice.ErrApartmentNotFound)
+
+				return req
+			},
+		},
+		{
+			name:           "ERR NOT OWNER",
+			statusCode:     http.StatusForbidden,
+			expectedErrMsg: apartmenthandler.ErrNotApartmentOwner.Error(),
+			prepareFunc: func() *http.Request {
+				apartmentEdit := apartmenthandlermodel.ApartmentEdit{Price: 1, NumberOfRooms: 1}
+
+				apartmentBytes, err := json.Marshal(apartmentEdit)
+				assert.NoError(t, err)
+
+				editUrl := strings.ReplaceAll(apartmenthandler.APIUrl+apartmenthandler.EditApartmentUrl, fmt.Sprintf("{%s}", apartmenthandler.ApartmentID), "1")
+
+				req := httptest.NewRequest(http.MethodPut, editUrl, bytes.NewReader(apartmentBytes))
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "client"}
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockApartmentService.EXPECT().Edit(gomock.Any(), gomock.Any(), gomock.Any()).Return(model.Apartment{}, apartmentservice.ErrNotApartmentOwner)
+
+				return req
+			},
+		},
+		{
+			name:           "ERR CONFLICT",
+			statusCode:     http.StatusConflict,
+			expectedErrMsg: apartmenthandler.ErrModerationConflict.Error(),
+			prepareFunc: func() *http.Request {
+				apartmentEdit := apartmenthandlermodel.ApartmentEdit{Price: 1, NumberOfRooms: 1}
+
+				apartmentBytes, err := json.Marshal(apartmentEdit)
+				assert.NoError(t, err)
+
+				editUrl := strings.ReplaceAll(apartmenthandler.APIUrl+apartmenthandler.EditApartmentUrl, fmt.Sprintf("{%s}", apartmenthandler.ApartmentID), "1")
+
+				req := httptest.NewRequest(http.MethodPut, editUrl, bytes.NewReader(apartmentBytes))
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "client"}
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockApartmentService.EXPECT().Edit(gomock.Any(), gomock.Any(), gomock.Any()).Return(model.Apartment{}, apartmentservice.ErrModerationConflict)
+
+				return req
+			},
+		},
+		{
+			name:           "ERR INTERNAL",
+			statusCode:     http.StatusInternalServerError,
+			expectedErrMsg: http.StatusText(http.StatusInternalServerError),
+			prepareFunc: func() *http.Request {
+				apartmentEdit := apartmenthandlermodel.ApartmentEdit{Price: 1, NumberOfRooms: 1}
+
+				apartmentBytes, err := json.Marshal(apartmentEdit)
+				assert.NoError(t, err)
+
+				editUrl := strings.ReplaceAll(apartmenthandler.APIUrl+apartmenthandler.EditApartmentUrl, fmt.Sprintf("{%s}", apartmenthandler.ApartmentID), "1")
+
+				req := httptest.NewRequest(http.MethodPut, editUrl, bytes.NewReader(apartmentBytes))
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "client"}
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockApartmentService.EXPECT().Edit(gomock.Any(), gomock.Any(), gomock.Any()).Return(model.Apartment{}, apartmentservice.ErrInternal)
+
+				return req
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := c.prepareFunc()
+
+			recorder := httptest.NewRecorder()
+
+			router.ServeHTTP(recorder, req)
+
+			assert.Equal(t, c.statusCode, recorder.Code)
+			assert.Contains(t, recorder.Body.String(), c.expectedErrMsg)
+		})
+	}
+}
+
+func TestWithdraw(t *testing.T) {
+	ctrl, mockApartmentService, mockTokenManager, router := testHandler(t)
+	defer ctrl.Finish()
+
+	cases := []struct {
+		name        string
+		statusCode  int
+		prepareFunc func() *http.Request
+	}{
+		{
+			name:       "OK",
+			statusCode: http.StatusOK,
+			prepareFunc: func() *http.Request {
+				ownerID := int64(uuid.New().ID())
+
+				withdrawUrl := strings.ReplaceAll(apartmenthandler.APIUrl+apartmenthandler.WithdrawApartmentUrl, fmt.Sprintf("{%s}", apartmenthandler.ApartmentID), "1")
+
+				req := httptest.NewRequest(http.MethodPost, withdrawUrl, http.NoBody)
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				claims := tokenmanager.Claims{UserID: ownerID, Role: "client"}
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockApartmentService.EXPECT().Withdraw(gomock.Any(), int64(1), ownerID).Return(model.Apartment{ID: 1, OwnerID: ownerID, ModerationStatus: apartmentservice.StatusWithdrawn}, nil)
+
+				return req
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := c.prepareFunc()
+
+			recorder := httptest.NewRecorder()
+
+			router.ServeHTTP(recorder, req)
+
+			assert.Equal(t, c.statusCode, recorder.Code)
+		})
+	}
+}
+
+func TestWithdrawErr(t *testing.T) {
+	ctrl, mockApartmentService, mockTokenManager, router := testHandler(t)
+	defer ctrl.Finish()
+
+	cases := []struct {
+		name           string
+		statusCode     int
+		expectedErrMsg string
+		prepareFunc    func() *http.Request
+	}{
+		{
+			name:       "UNAUTHORIZED",
+			statusCode: http.StatusUnauthorized,
+			prepareFunc: func() *http.Request {
+				withdrawUrl := strings.ReplaceAll(apartmenthandler.APIUrl+apartmenthandler.WithdrawApartmentUrl, fmt.Sprintf("{%s}", apartmenthandler.ApartmentID), "1")
+
+				req := httptest.NewRequest(http.MethodPost, withdrawUrl, http.NoBody)
+
+				return req
+			},
+		},
+		{
+			name:           "ERR APARTMENT NOT FOUND",
+			statusCode:     http.StatusNotFound,
+			expectedErrMsg: apartmenthandler.ErrApartmentNotFound.Error(),
+			prepareFunc: func() *http.Request {
+				withdrawUrl := strings.ReplaceAll(apartmenthandler.APIUrl+apartmenthandler.WithdrawApartmentUrl, fmt.Sprintf("{%s}", apartmenthandler.ApartmentID), "1")
+
+				req := httptest.NewRequest(http.MethodPost, withdrawUrl, http.NoBody)
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "client"}
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockApartmentService.EXPECT().Withdraw(gomock.Any(), int64(1), gomock.Any()).Return(model.Apartment{}, apartmentservice.ErrApartmentNotFound)
+
+				return req
+			},
+		},
+		{
+			name:           "ERR NOT OWNER",
+			statusCode:     http.StatusForbidden,
+			expectedErrMsg: apartmenthandler.ErrNotApartmentOwner.Error(),
+			prepareFunc: func() *http.Request {
+				withdrawUrl := strings.ReplaceAll(apartmenthandler.APIUrl+apartmenthandler.WithdrawApartmentUrl, fmt.Sprintf("{%s}", apartmenthandler.ApartmentID), "1")
+
+				req := httptest.NewRequest(http.MethodPost, withdrawUrl, http.NoBody)
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "client"}
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockApartmentService.EXPECT().Withdraw(gomock.Any(), int64(1), gomock.Any()).Return(model.Apartment{}, apartmentservice.ErrNotApartmentOwner)
+
+				return req
+			},
+		},
+		{
+			name:           "ERR INTERNAL",
+			statusCode:     http.StatusInternalServerError,
+			expectedErrMsg: http.StatusText(http.StatusInternalServerError),
+			prepareFunc: func() *http.Request {
+				withdrawUrl := strings.ReplaceAll(apartmenthandler.APIUrl+apartmenthandler.WithdrawApartmentUrl, fmt.Sprintf("{%s}", apartmenthandler.ApartmentID), "1")
+
+				req := httptest.NewRequest(http.MethodPost, withdrawUrl, http.NoBody)
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "client"}
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockApartmentService.EXPECT().Withdraw(gomock.Any(), int64(1), gomock.Any()).Return(model.Apartment{}, apartmentservice.ErrInternal)
+
+				return req
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := c.prepareFunc()
+
+			recorder := httptest.NewRecorder()
+
+			router.ServeHTTP(recorder, req)
+
+			assert.Equal(t, c.statusCode, recorder.Code)
+			assert.Contains(t, recorder.Body.String(), c.expectedErrMsg)
+		})
+	}
+}
+
+func TestRemove(t *testing.T) {
+	ctrl, mockApartmentService, mockTokenManager, router := testHandler(t)
+	defer ctrl.Finish()
+
+	cases := []struct {
+		name        string
+		statusCode  int
+		prepareFunc func() *http.Request
+	}{
+		{
+			name:       "OK",
+			statusCode: http.StatusOK,
+			prepareFunc: func() *http.Request {
+				removal := apartmenthandlermodel.Removal{Reason: "fraudulent listing"}
+
+				removalBytes, err := json.Marshal(removal)
+				assert.NoError(t, err)
+
+				removeUrl := strings.ReplaceAll(apartmenthandler.APIUrl+apartmenthandler.RemoveApartmentUrl, fmt.Sprintf("{%s}", apartmenthandler.ApartmentID), "1")
+
+				req := httptest.NewRequest(http.MethodPost, removeUrl, bytes.NewReader(removalBytes))
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "moderator"}
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockApartmentService.EXPECT().Remove(gomock.Any(), int64(1), "fraudulent listing").Return(model.Apartment{ID: 1, ModerationStatus: apartmentservice.StatusRemoved, RemovalReason: "fraudulent listing"}, nil)
+
+				return req
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := c.prepareFunc()
+
+			recorder := httptest.NewRecorder()
+
+			router.ServeHTTP(recorder, req)
+
+			assert.Equal(t, c.statusCode, recorder.Code)
+		})
+	}
+}
+
+func TestRemoveErr(t *testing.T) {
+	ctrl, mockApartmentService, mockTokenManager, router := testHandler(t)
+	defer ctrl.Finish()
+
+	cases := []struct {
+		name           string
+		statusCode     int
+		expectedErrMsg string
+		prepareFunc    func() *http.Request
+	}{
+		{
+			name:       "ERR FORBIDDEN",
+			statusCode: http.StatusForbidden,
+			prepareFunc: func() *http.Request {
+				removal := apartmenthandlermodel.Removal{Reason: "fraudulent listing"}
+
+				removalBytes, err := json.Marshal(removal)
+				assert.NoError(t, err)
+
+				removeUrl := strings.ReplaceAll(apartmenthandler.APIUrl+apartmenthandler.RemoveApartmentUrl, fmt.Sprintf("{%s}", apartmenthandler.ApartmentID), "1")
+
+				req := httptest.NewRequest(http.MethodPost, removeUrl, bytes.NewReader(removalBytes))
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "client"}
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+
+				return req
+			},
+		},
+		{
+			name:           "ERR REASON REQUIRED",
+			statusCode:     http.StatusBadRequest,
+			expectedErrMsg: apartmenthandler.ErrRemovalReasonRequired.Error(),
+			prepareFunc: func() *http.Request {
+				removal := apartmenthandlermodel.Removal{Reason: ""}
+
+				removalBytes, err := json.Marshal(removal)
+				assert.NoError(t, err)
+
+				removeUrl := strings.ReplaceAll(apartmenthandler.APIUrl+apartmenthandler.RemoveApartmentUrl, fmt.Sprintf("{%s}", apartmenthandler.ApartmentID), "1")
+
+				req := httptest.NewRequest(http.MethodPost, removeUrl, bytes.NewReader(removalBytes))
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "moderator"}
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+
+				return req
+			},
+		},
+		{
+			name:           "ERR APARTMENT NOT FOUND",
+			statusCode:     http.StatusNotFound,
+			expectedErrMsg: apartmenthandler.ErrApartmentNotFound.Error(),
+			prepareFunc: func() *http.Request {
+				removal := apartmenthandlermodel.Removal{Reason: "fraudulent listing"}
+
+				removalBytes, err := json.Marshal(removal)
+				assert.NoError(t, err)
+
+				removeUrl := strings.ReplaceAll(apartmenthandler.APIUrl+apartmenthandler.RemoveApartmentUrl, fmt.Sprintf("{%s}", apartmenthandler.ApartmentID), "1")
+
+				req := httptest.NewRequest(http.MethodPost, removeUrl, bytes.NewReader(removalBytes))
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "moderator"}
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockApartmentService.EXPECT().Remove(gomock.Any(), int64(1), gomock.Any()).Return(model.Apartment{}, apartmentservice.ErrApartmentNotFound)
+
+				return req
+			},
+		},
+		{
+			name:           "ERR INTERNAL",
+			statusCode:     http.StatusInternalServerError,
+			expectedErrMsg: http.StatusText(http.StatusInternalServerError),
+			prepareFunc: func() *http.Request {
+				removal := apartmenthandlermodel.Removal{Reason: "fraudulent listing"}
+
+				removalBytes, err := json.Marshal(removal)
+				assert.NoError(t, err)
+
+				removeUrl := strings.ReplaceAll(apartmenthandler.APIUrl+apartmenthandler.RemoveApartmentUrl, fmt.Sprintf("{%s}", apartmenthandler.ApartmentID), "1")
+
+				req := httptest.NewRequest(http.MethodPost, removeUrl, bytes.NewReader(removalBytes))
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "moderator"}
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockApartmentService.EXPECT().Remove(gomock.Any(), int64(1), gomock.Any()).Return(model.Apartment{}, apartmentservice.ErrInternal)
+
+				return req
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := c.prepareFunc()
+
+			recorder := httptest.NewRecorder()
+
+			router.ServeHTTP(recorder, req)
+
+			assert.Equal(t, c.statusCode, recorder.Code)
+			assert.Contains(t, recorder.Body.String(), c.expectedErrMsg)
+		})
+	}
+}
+
+func TestSearch(t *testing.T) {
+	ctrl, mockApartmentService, mockTokenManager, router := testHandler(t)
+	defer ctrl.Finish()
+
+	criteria := model.ApartmentSearchCriteria{
+		MinPrice:  1000,
+		MaxPrice:  5000,
+		MinRooms:  2,
+		MaxRooms:  2,
+		MinYear:   2000,
+		MaxYear:   2020,
+		Developer: "developer",
+		Offset:    10,
+		Limit:     5,
+		WithTotal: true,
+	}
+	results := []model.ApartmentWithHouse{
+		{
+			Apartment: model.Apartment{ID: 1, HouseID: 1, Price: 2000, NumberOfRooms: 2, ModerationStatus: "approved", OwnerID: 2, ModeratorID: 3},
+			House:     model.House{HouseId: 1, Address: "Moscow, Lenina 1", Year: 2010, Developer: "developer"},
+		},
+	}
+
+	values := make(url.Values)
+	values.Set(apartmenthandler.MinPriceQueryParam, "1000")
+	values.Set(apartmenthandler.MaxPriceQueryParam, "5000")
+	values.Set(apartmenthandler.RoomsQueryParam, "2")
+	values.Set(apartmenthandler.MinYearQueryParam, "2000")
+	values.Set(apartmenthandler.MaxYearQueryParam, "2020")
+	values.Set(apartmenthandler.DeveloperQueryParam, "developer")
+	values.Set(apartmenthandler.OffsetQueryParam, "10")
+	values.Set(apartmenthandler.LimitQueryParam, "5")
+	values.Set(apartmenthandler.WithTotalQueryParam, "true")
+
+	req := httptest.NewRequest(http.MethodGet, apartmenthandler.APIUrl+apartmenthandler.SearchApartmentsUrl+"?"+values.Encode(), http.NoBody)
+	req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+	claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "client"}
+
+	mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+	mockApartmentService.EXPECT().Search(gomock.Any(), criteria).Return(results, 11, nil)
+
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	res := apartmenthandlermodel.SearchResult{}
+	assert.NoError(t, json.NewDecoder(recorder.Body).Decode(&res))
+	assert.Equal(t, 11, *res.Total)
+	assert.Len(t, res.Apartments, 1)
+	assert.Equal(t, "Moscow, Lenina 1", res.Apartments[0].House.Address)
+	assert.Equal(t, 2010, res.Apartments[0].House.Year)
+	assert.Zero(t, res.Apartments[0].OwnerID)
+	assert.Zero(t, res.Apartments[0].ModeratorID)
+}
+
+func TestSearchWithoutTotal(t *testing.T) {
+	ctrl, mockApartmentService, mockTokenManager, router := testHandler(t)
+	defer ctrl.Finish()
+
+	req := httptest.NewRequest(http.MethodGet, apartmenthandler.APIUrl+apartmenthandler.SearchApartmentsUrl, http.NoBody)
+	req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+	claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "client"}
+
+	//THE MATCHES AREN'T COUNTED UNLESS THE TOTAL IS ASKED FOR
+	mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+	mockApartmentService.EXPECT().Search(gomock.Any(), model.ApartmentSearchCriteria{Limit: 20}).Return([]model.ApartmentWithHouse{}, 0, nil)
+
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.NotContains(t, recorder.Body.String(), "total")
+}
+
+func TestSearchErr(t *testing.T) {
+	ctrl, mockApartmentService, mockTokenManager, router := testHandler(t)
+	defer ctrl.Finish()
+
+	cases := []struct {
+		name           string
+		statusCode     int
+		expectedErrMsg string
+		prepareFunc    func() *http.Request
+	}{
+		{
+			name:       "UNAUTHORIZED",
+			statusCode: http.StatusUnauthorized,
+			prepareFunc: func() *http.Request {
+				req := httptest.NewRequest(http.MethodGet, apartmenthandler.APIUrl+apartmenthandler.SearchApartmentsUrl, http.NoBody)
+
+				return req
+			},
+		},
+		{
+			name:           "ERR INVALID PRICE",
+			statusCode:     http.StatusBadRequest,
+			expectedErrMsg: apartmenthandler.ErrInvalidSearchFilter.Error(),
+			prepareFunc: func() *http.Request {
+				values := make(url.Values)
+				values.Set(apartmenthandler.MinPriceQueryParam, "cheap")
+
+				req := httptest.NewRequest(http.MethodGet, apartmenthandler.APIUrl+apartmenthandler.SearchApartmentsUrl+"?"+values.Encode(), http.NoBody)
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "client"}
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+
+				return req
+			},
+		},
+		{
+			name:           "ERR INVALID WITH TOTAL",
+			statusCode:     http.StatusBadRequest,
+			expectedErrMsg: apartmenthandler.ErrInvalidSearchFilter.Error(),
+			prepareFunc: func() *http.Request {
+				values := make(url.Values)
+				values.Set(apartmenthandler.WithTotalQueryParam, "sometimes")
+
+				req := httptest.NewRequest(http.MethodGet, apartmenthandler.APIUrl+apartmenthandler.SearchApartmentsUrl+"?"+values.Encode(), http.NoBody)
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "client"}
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+
+				return req
+			},
+		},
+		{
+			name:           "ERR INVALID YEAR RANGE",
+			statusCode:     http.StatusBadRequest,
+			expectedErrMsg: apartmenthandler.ErrInvalidSearchFilter.Error(),
+			prepareFunc: func() *http.Request {
+				values := make(url.Values)
+				values.Set(apartmenthandler.MinYearQueryParam, "2020")
+				values.Set(apartmenthandler.MaxYearQueryParam, "2000")
+
+				req := httptest.NewRequest(http.MethodGet, apartmenthandler.APIUrl+apartmenthandler.SearchApartmentsUrl+"?"+values.Encode(), http.NoBody)
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "client"}
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+
+				return req
+			},
+		},
+		{
+			name:           "ERR LIMIT TOO BIG",
+			statusCode:     http.StatusBadRequest,
+			expectedErrMsg: apartmenthandler.ErrInvalidSearchFilter.Error(),
+			prepareFunc: func() *http.Request {
+				values := make(url.Values)
+				values.Set(apartmenthandler.LimitQueryParam, "1000")
+
+				req := httptest.NewRequest(http.MethodGet, apartmenthandler.APIUrl+apartmenthandler.SearchApartmentsUrl+"?"+values.Encode(), http.NoBody)
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "client"}
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+
+				return req
+			},
+		},
+		{
+			name:           "ERR NEGATIVE OFFSET",
+			statusCode:     http.StatusBadRequest,
+			expectedErrMsg: apartmenthandler.ErrInvalidSearchFilter.Error(),
+			prepareFunc: func() *http.Request {
+				values := make(url.Values)
+				values.Set(apartmenthandler.OffsetQueryParam, "-1")
+
+				req := httptest.NewRequest(http.MethodGet, apartmenthandler.APIUrl+apartmenthandler.SearchApartmentsUrl+"?"+values.Encode(), http.NoBody)
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "client"}
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+
+				return req
+			},
+		},
+		{
+			name:           "ERR INTERNAL",
+			statusCode:     http.StatusInternalServerError,
+			expectedErrMsg: http.StatusText(http.StatusInternalServerError),
+			prepareFunc: func() *http.Request {
+				values := make(url.Values)
+
+				req := httptest.NewRequest(http.MethodGet, apartmenthandler.APIUrl+apartmenthandler.SearchApartmentsUrl+"?"+values.Encode(), http.NoBody)
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "client"}
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockApartmentService.EXPECT().Search(gomock.Any(), gomock.Any()).Return(nil, 0, apartmentservice.ErrInternal)
+
+				return req
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := c.prepareFunc()
+
+			recorder := httptest.NewRecorder()
+
+			router.ServeHTTP(recorder, req)
+
+			assert.Equal(t, c.statusCode, recorder.Code)
+			assert.Contains(t, recorder.Body.String(), c.expectedErrMsg)
+		})
+	}
+}
+
+func TestMyApartments(t *testing.T) {
+	ctrl, mockApartmentService, mockTokenManager, router := testHandler(t)
+	defer ctrl.Finish()
+
+	ownerID := int64(uuid.New().ID())
+	apartments := []model.Apartment{
+		{ID: 1, HouseID: 1, OwnerID: ownerID, ModerationStatus: "approved"},
+		{ID: 2, HouseID: 1, OwnerID: ownerID, ModerationStatus: "declined", DeclineReason: "duplicate", ModeratorComment: "same as apartment 1"},
+	}
+
+	req := httptest.NewRequest(http.MethodGet, apartmenthandler.APIUrl+apartmenthandler.MyApartmentsUrl, http.NoBody)
+	req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+	claims := tokenmanager.Claims{UserID: ownerID, Role: "client"}
+
+	mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+	mockApartmentService.EXPECT().OwnerApartments(gomock.Any(), ownerID).Return(apartments, nil)
+
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	res := make([]apartmenthandlermodel.Apartment, 0)
+	assert.NoError(t, json.NewDecoder(recorder.Body).Decode(&res))
+	assert.Len(t, res, len(apartments))
+	assert.Equal(t, "duplicate", res[1].DeclineReason)
+	assert.Equal(t, "same as apartment 1", res[1].ModeratorComment)
+}
+
+func TestMyApartmentsErr(t *testing.T) {
+	ctrl, mockApartmentService, mockTokenManager, router := testHandler(t)
+	defer ctrl.Finish()
+
+	cases := []struct {
+		name           string
+		statusCode     int
+		expectedErrMsg string
+		prepareFunc    func() *http.Request
+	}{
+		{
+			name:       "UNAUTHORIZED",
 			statusCode: http.StatusUnauthorized,
 			prepareFunc: func() *http.Request {
-				apartmentsUrl := strings.ReplaceAll(apartmenthandler.APIUrl+apartmenthandler.ApartmentsByHouseIDUrl, fmt.Sprintf("{%s}", apartmenthandler.HouseID), "1")
-				req := httptest.NewRequest(http.MethodGet, apartmentsUrl, http.NoBody)
+				req := httptest.NewRequest(http.MethodGet, apartmenthandler.APIUrl+apartmenthandler.MyApartmentsUrl, http.NoBody)
+
+				return req
+			},
+		},
+		{
+			name:           "ERR INTERNAL",
+			statusCode:     http.StatusInternalServerError,
+			expectedErrMsg: http.StatusText(http.StatusInternalServerError),
+			prepareFunc: func() *http.Request {
+				req := httptest.NewRequest(http.MethodGet, apartmenthandler.APIUrl+apartmenthandler.MyApartmentsUrl, http.NoBody)
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "client"}
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockApartmentService.EXPECT().OwnerApartments(gomock.Any(), gomock.Any()).Return(nil, apartmentservice.ErrInternal)
+
+				return req
+			},
+		},
+		{
+			name:           "ERR DUMMY TOKEN",
+			statusCode:     http.StatusForbidden,
+			expectedErrMsg: middleware.ErrDummyToken.Error(),
+			prepareFunc: func() *http.Request {
+				req := httptest.NewRequest(http.MethodGet, apartmenthandler.APIUrl+apartmenthandler.MyApartmentsUrl, http.NoBody)
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				claims := tokenmanager.Claims{UserID: -1, Role: "client", Dummy: true}
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+
+				return req
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := c.prepareFunc()
+
+			recorder := httptest.NewRecorder()
+
+			router.ServeHTTP(recorder, req)
+
+			assert.Equal(t, c.statusCode, recorder.Code)
+			assert.Contains(t, recorder.Body.String(), c.expectedErrMsg)
+		})
+	}
+}
+
+func TestNextForModeration(t *testing.T) {
+	ctrl, mockApartmentService, mockTokenManager, router := testHandler(t)
+	defer ctrl.Finish()
+
+	cases := []struct {
+		name        string
+		statusCode  int
+		prepareFunc func() *http.Request
+	}{
+		{
+			name:       "OK",
+			statusCode: http.StatusOK,
+			prepareFunc: func() *http.Request {
+				req := httptest.NewRequest(http.MethodGet, apartmenthandler.APIUrl+apartmenthandler.NextForModerationUrl, http.NoBody)
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				moderatorID := int64(uuid.New().ID())
+
+				claims := tokenmanager.Claims{UserID: moderatorID, Role: "moderator"}
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockApartmentService.EXPECT().NextForModeration(gomock.Any(), moderatorID, model.ModerationQueueFilter{}).Return(model.Apartment{ID: 1, ModerationStatus: "on moderation", ModeratorID: moderatorID}, nil)
+
+				return req
+			},
+		},
+		{
+			name:       "OK WITH FILTER",
+			statusCode: http.StatusOK,
+			prepareFunc: func() *http.Request {
+				values := make(url.Values)
+				values.Set(apartmenthandler.HouseIDQueryParam, "1")
+				values.Set(apartmenthandler.MinPriceQueryParam, "100")
+				values.Set(apartmenthandler.MaxPriceQueryParam, "200")
+
+				req := httptest.NewRequest(http.MethodGet, apartmenthandler.APIUrl+apartmenthandler.NextForModerationUrl+"?"+values.Encode(), http.NoBody)
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "moderator"}
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockApartmentService.EXPECT().NextForModeration(gomock.Any(), gomock.Any(), model.ModerationQueueFilter{HouseID: 1, MinPrice: 100, MaxPrice: 200}).Return(model.Apartment{ID: 1}, nil)
+
+				return req
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := c.prepareFunc()
+
+			recorder := httptest.NewRecorder()
+
+			router.ServeHTTP(recorder, req)
+
+			assert.Equal(t, c.statusCode, recorder.Code)
+		})
+	}
+}
+
+func TestNextForModerationErr(t *testing.T) {
+	ctrl, mockApartmentService, mockTokenManager, router := testHandler(t)
+	defer ctrl.Finish()
+
+	cases := []struct {
+		name           string
+		statusCode     int
+		expectedErrMsg string
+		prepareFunc    func() *http.Request
+	}{
+		{
+			name:       "ERR FORBIDDEN",
+			statusCode: http.StatusForbidden,
+			prepareFunc: func() *http.Request {
+				req := httptest.NewRequest(http.MethodGet, apartmenthandler.APIUrl+apartmenthandler.NextForModerationUrl, http.NoBody)
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "client"}
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+
+				return req
+			},
+		},
+		{
+			name:           "ERR INVALID FILTER",
+			statusCode:     http.StatusBadRequest,
+			expectedErrMsg: apartmenthandler.ErrInvalidQueueFilter.Error(),
+			prepareFunc: func() *http.Request {
+				values := make(url.Values)
+				values.Set(apartmenthandler.MinPriceQueryParam, "cheap")
+
+				req := httptest.NewRequest(http.MethodGet, apartmenthandler.APIUrl+apartmenthandler.NextForModerationUrl+"?"+values.Encode(), http.NoBody)
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "moderator"}
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+
+				return req
+			},
+		},
+		{
+			name:           "ERR QUEUE EMPTY",
+			statusCode:     http.StatusNotFound,
+			expectedErrMsg: apartmenthandler.ErrModerationQueueEmpty.Error(),
+			prepareFunc: func() *http.Request {
+				req := httptest.NewRequest(http.MethodGet, apartmenthandler.APIUrl+apartmenthandler.NextForModerationUrl, http.NoBody)
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "moderator"}
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockApartmentService.EXPECT().NextForModeration(gomock.Any(), gomock.Any(), gomock.Any()).Return(model.Apartment{}, apartmentservice.ErrModerationQueueEmpty)
 
 				return req
 			},
@@ -496,17 +1727,14 @@ func TestApartmentsErr(t *testing.T) {
 			statusCode:     http.StatusInternalServerError,
 			expectedErrMsg: http.StatusText(http.StatusInternalServerError),
 			prepareFunc: func() *http.Request {
-				apartmentsUrl := strings.ReplaceAll(apartmenthandler.APIUrl+apartmenthandler.ApartmentsByHouseIDUrl, fmt.Sprintf("{%s}", apartmenthandler.HouseID), "1")
-				req := httptest.NewRequest(http.MethodGet, apartmentsUrl, http.NoBody)
+				req := httptest.NewRequest(http.MethodGet, apartmenthandler.APIUrl+apartmenthandler.NextForModerationUrl, http.NoBody)
 				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
 
-				m := make(jwt.MapClaims)
-				m[tokenmanagerimpl.UserIDClaimsTag] = float64(uuid.New().ID())
-				m[tokenmanagerimpl.RoleClaimsTag] = "client"
-				m[tokenmanagerimpl.ExpClaimsTag] = float64(time.Now().Add(5 * time.Minute).Unix())
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "moderator"}
 
-				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(m, nil)
-				mockApartmentService.EXPECT().Apartments(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, apartmentservice.ErrInternal)
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockApartmentService.EXPECT().NextForModeration(gomock.Any(), gomock.Any(), gomock.Any()).Return(model.Apartment{}, apartmentservice.ErrInternal)
 
 				return req
 			},
@@ -522,6 +1750,7 @@ func TestApartmentsErr(t *testing.T) {
 			router.ServeHTTP(recorder, req)
 
 			assert.Equal(t, c.statusCode, recorder.Code)
+			assert.Contains(t, recorder.Body.String(), c.expectedErrMsg)
 		})
 	}
 }
@@ -535,7 +1764,7 @@ func testHandler(t *testing.T) (ctrl *gomock.Controller, mockApartmentService *a
 	router = mux.NewRouter()
 	logger := slog.New(slog.NewTextHandler(&stubwriter.Writer{}, nil))
 
-	err := Register(router, mockApartmentService, mockTokenManager, logger)
+	err := Register(router, mockApartmentService, mockTokenManager, memory.New(0), logger)
 	assert.NoError(t, err)
 
 	return ctrl, mockApartmentService, mockTokenManager, router
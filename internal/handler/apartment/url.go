@@ -7,15 +7,34 @@ var (
 	ApartmentsUrl      = "/apartment"
 	CreateApartmentUrl = fmt.Sprintf("%s/create", ApartmentsUrl)
 
-	ApartmentID        = "apartment_id"
-	ApartmentUrl       = fmt.Sprintf("%s/{%s}", ApartmentsUrl, ApartmentID)
-	UpdateApartmentUrl = fmt.Sprintf("%s/update", ApartmentUrl)
+	ApartmentID          = "apartment_id"
+	ApartmentUrl         = fmt.Sprintf("%s/{%s}", ApartmentsUrl, ApartmentID)
+	ApartmentByIDUrl     = fmt.Sprintf("%s/{%s:[0-9]+}", ApartmentsUrl, ApartmentID)
+	UpdateApartmentUrl   = fmt.Sprintf("%s/update", ApartmentUrl)
+	EditApartmentUrl     = fmt.Sprintf("%s/edit", ApartmentUrl)
+	WithdrawApartmentUrl = fmt.Sprintf("%s/withdraw", ApartmentUrl)
+	RemoveApartmentUrl   = fmt.Sprintf("%s/remove", ApartmentUrl)
 
-	HouseID                = "house_id"
-	ApartmentsByHouseIDUrl = fmt.Sprintf("%s/{%s}", ApartmentsUrl, HouseID)
+	SearchApartmentsUrl = fmt.Sprintf("%s/search", ApartmentsUrl)
+
+	MyApartmentsUrl = "/my/apartments"
+
+	ModerationUrl        = "/moderation"
+	NextForModerationUrl = fmt.Sprintf("%s/next", ModerationUrl)
 )
 
 var (
-	LimitQueryParams  = "limit"
-	OffsetQueryParams = "offset"
+	HouseIDQueryParam  = "house_id"
+	MinPriceQueryParam = "min_price"
+	MaxPriceQueryParam = "max_price"
+
+	RoomsQueryParam     = "rooms"
+	MinRoomsQueryParam  = "min_rooms"
+	MaxRoomsQueryParam  = "max_rooms"
+	MinYearQueryParam   = "min_year"
+	MaxYearQueryParam   = "max_year"
+	DeveloperQueryParam = "developer"
+	LimitQueryParam     = "limit"
+	OffsetQueryParam    = "offset"
+	WithTotalQueryParam = "with_total"
 )
@@ -0,0 +1,13 @@
+package househandlerconverter
+
+import (
+	househandlermodel "avito/internal/handler/house/model"
+	"avito/internal/model"
+)
+
+func ToSubscriptionDTO(subscription househandlermodel.Subscription) model.Subscription {
+	return model.Subscription{
+		HouseID: subscription.HouseID,
+		Email:   subscription.Email,
+	}
+}
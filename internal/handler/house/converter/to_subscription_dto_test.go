@@ -0,0 +1,17 @@
+package househandlerconverter
+
+import (
+	househandlermodel "avito/internal/handler/house/model"
+	"testing"
+)
+
+func BenchmarkToSubscriptionDTO(b *testing.B) {
+	b.ReportAllocs()
+
+	subscription := househandlermodel.Subscription{}
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		ToSubscriptionDTO(subscription)
+	}
+}
@@ -0,0 +1,15 @@
+package househandlerconverter
+
+import (
+	househandlermodel "avito/internal/handler/house/model"
+	"avito/internal/model"
+)
+
+func ToSubscriptionHandlerModel(subscription model.Subscription) househandlermodel.Subscription {
+	return househandlermodel.Subscription{
+		SubscriptionID: subscription.SubscriptionID,
+		HouseID:        subscription.HouseID,
+		Email:          subscription.Email,
+		CreatedAt:      subscription.CreatedAt,
+	}
+}
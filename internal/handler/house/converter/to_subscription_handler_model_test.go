@@ -0,0 +1,17 @@
+package househandlerconverter
+
+import (
+	"avito/internal/model"
+	"testing"
+)
+
+func BenchmarkToSubscriptionHandlerModel(b *testing.B) {
+	b.ReportAllocs()
+
+	subscription := model.Subscription{}
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		ToSubscriptionHandlerModel(subscription)
+	}
+}
@@ -0,0 +1,50 @@
+package househandler
+
+import (
+	"avito/internal/model"
+	"encoding/base64"
+	"encoding/json"
+)
+
+// cursor is the wire form of model.Cursor. Clients get it as an opaque token and must
+// only pass it back, so the fields may change without breaking the API.
+type cursor struct {
+	SortBy     string `json:"s,omitempty"`
+	Descending bool   `json:"d,omitempty"`
+	SortValue  int64  `json:"v,omitempty"`
+	ID         int64  `json:"i"`
+}
+
+func EncodeCursor(c *model.Cursor) string {
+	if c == nil {
+		return ""
+	}
+
+	b, _ := json.Marshal(cursor{
+		SortBy:     c.SortBy,
+		Descending: c.Descending,
+		SortValue:  c.SortValue,
+		ID:         c.ID,
+	})
+
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func DecodeCursor(s string) (*model.Cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var c cursor
+	if err = json.Unmarshal(b, &c); err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	return &model.Cursor{
+		SortBy:     c.SortBy,
+		Descending: c.Descending,
+		SortValue:  c.SortValue,
+		ID:         c.ID,
+	}, nil
+}
@@ -0,0 +1,20 @@
+package househandler
+
+import (
+	"avito/internal/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCursor(t *testing.T) {
+	cursor := &model.Cursor{SortBy: model.SortByRooms, Descending: true, SortValue: 3, ID: 42}
+
+	decoded, err := DecodeCursor(EncodeCursor(cursor))
+	assert.NoError(t, err)
+	assert.Equal(t, cursor, decoded)
+
+	assert.Empty(t, EncodeCursor(nil))
+
+	_, err = DecodeCursor("%%%")
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+}
@@ -5,4 +5,10 @@ import "errors"
 var (
 	ErrHouseAlreadyExists = errors.New("house with this address already exists")
 	ErrHouseNotFound      = errors.New("house not found")
+
+	ErrInvalidApartmentsFilter = errors.New("invalid apartments filter")
+	ErrStatusFilterForbidden   = errors.New("only moderators can filter apartments by moderation status")
+
+	ErrInvalidCursor = errors.New("invalid cursor")
+	ErrInvalidLimit  = errors.New("invalid limit. possible values: 1-100")
 )
@@ -5,4 +5,6 @@ import "net/http"
 type Handler interface {
 	Create() http.HandlerFunc
 	Houses() http.HandlerFunc
+	Apartments() http.HandlerFunc
+	Subscribe() http.HandlerFunc
 }
@@ -3,7 +3,7 @@ package househandlermodel
 import "time"
 
 type House struct {
-	HouseId              uint32    `json:"house_id"`
+	HouseId              int64     `json:"house_id"`
 	Address              string    `json:"address"`
 	Year                 int       `json:"year"`
 	Developer            string    `json:"developer"`
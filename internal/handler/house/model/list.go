@@ -0,0 +1,21 @@
+package househandlermodel
+
+import apartmenthandlermodel "avito/internal/handler/apartment/model"
+
+// Pagination echoes the page that was served. NextCursor is empty on the last page,
+// Offset is omitted when the page was requested by cursor.
+type Pagination struct {
+	Limit      int    `json:"limit"`
+	Offset     int    `json:"offset,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+type HouseList struct {
+	Items      []House    `json:"items"`
+	Pagination Pagination `json:"pagination"`
+}
+
+type ApartmentList struct {
+	Items      []apartmenthandlermodel.Apartment `json:"items"`
+	Pagination Pagination                        `json:"pagination"`
+}
@@ -0,0 +1,10 @@
+package househandlermodel
+
+import "time"
+
+type Subscription struct {
+	SubscriptionID int64     `json:"subscription_id"`
+	HouseID        int64     `json:"house_id"`
+	Email          string    `json:"email" validate:"required,email"`
+	CreatedAt      time.Time `json:"created_at"`
+}
@@ -1,20 +1,29 @@
 package housemuximpl
 
 import (
+	apartmenthandlerconverter "avito/internal/handler/apartment/converter"
+	apartmenthandlermodel "avito/internal/handler/apartment/model"
 	househandler "avito/internal/handler/house"
 	househandlerconverter "avito/internal/handler/house/converter"
 	househandlermodel "avito/internal/handler/house/model"
 	userhandler "avito/internal/handler/user"
 	"avito/internal/middleware"
+	"avito/internal/model"
+	apartmentservice "avito/internal/service/apartment"
 	houseservice "avito/internal/service/house"
+	subscriptionservice "avito/internal/service/subscription"
+	"avito/internal/validator"
 	"avito/pkg/logger"
+	"avito/pkg/revocation"
 	tokenmanager "avito/pkg/token_manager"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"github.com/gorilla/mux"
 	"log/slog"
 	"net/http"
 	"net/url"
+	"slices"
 	"strconv"
 )
 
@@ -23,6 +32,7 @@ var _ househandler.Handler = &handler{}
 const (
 	moderator    = "moderator"
 	defaultLimit = 20
+	maxLimit     = 100
 )
 
 const (
@@ -31,11 +41,15 @@ const (
 )
 
 type handler struct {
-	router       *mux.Router
-	houseService houseservice.Service
+	router              *mux.Router
+	houseService        houseservice.Service
+	apartmentService    apartmentservice.Service
+	subscriptionService subscriptionservice.Service
 
 	tm tokenmanager.Manager
 
+	validator *validator.Validate
+
 	logger *slog.Logger
 }
 
@@ -50,8 +64,8 @@ func (h *handler) Create() http.HandlerFunc {
 			return
 		}
 
-		houseDTO := househandlerconverter.ToHouseDTO(house)
-		if err := h.houseService.Create(r.Context(), houseDTO); err != nil {
+		created, err := h.houseService.Create(r.Context(), househandlerconverter.ToHouseDTO(house))
+		if err != nil {
 			switch {
 			case errors.Is(err, houseservice.ErrHouseAlreadyExists):
 				http.Error(w, househandler.ErrHouseAlreadyExists.Error(), http.StatusBadRequest)
@@ -64,7 +78,7 @@ func (h *handler) Create() http.HandlerFunc {
 
 		w.Header().Set(ContentTypeKey, ContentTypeJSON)
 		w.WriteHeader(http.StatusOK)
-		json.NewEncoder(w).Encode(househandlerconverter.ToHouseHandlerModel(houseDTO))
+		json.NewEncoder(w).Encode(househandlerconverter.ToHouseHandlerModel(created))
 	}
 }
 
@@ -87,16 +101,27 @@ func (h *handler) Houses() http.HandlerFunc {
 			return
 		}
 
-		limitStr := values.Get(househandler.LimitQueryParams)
-		limit, err := strconv.Atoi(limitStr)
+		page, err := listPage(values)
 		if err != nil {
-			limit = defaultLimit
+			l.Error("Invalid page", slog.String("error", err.Error()))
+			switch {
+			case errors.Is(err, househandler.ErrInvalidLimit):
+				http.Error(w, househandler.ErrInvalidLimit.Error(), http.StatusBadRequest)
+				return
+			default:
+				http.Error(w, househandler.ErrInvalidCursor.Error(), http.StatusBadRequest)
+				return
+			}
 		}
 
-		offsetStr := values.Get(househandler.OffsetQueryParams)
-		offset, _ := strconv.Atoi(offsetStr)
+		//A CURSOR OF ANOTHER LIST WOULD SILENTLY SKIP ROWS
+		if page.After != nil && page.After.SortBy != "" {
+			l.Error("Cursor of a sorted list", slog.String("sort", page.After.SortBy))
+			http.Error(w, househandler.ErrInvalidCursor.Error(), http.StatusBadRequest)
+			return
+		}
 
-		houses, err := h.houseService.Houses(r.Context(), offset, limit)
+		houses, next, err := h.houseService.Houses(r.Context(), page)
 		if err != nil {
 			switch {
 			case errors.Is(err, houseservice.ErrHouseNotFound):
@@ -115,25 +140,288 @@ func (h *handler) Houses() http.HandlerFunc {
 
 		w.Header().Set(ContentTypeKey, ContentTypeJSON)
 		w.WriteHeader(http.StatusOK)
-		json.NewEncoder(w).Encode(housesHandlerModel)
+		json.NewEncoder(w).Encode(househandlermodel.HouseList{
+			Items:      housesHandlerModel,
+			Pagination: pagination(page, next),
+		})
+	}
+}
+
+func (h *handler) Apartments() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		l := logger.EndToEndLogging(r.Context(), h.logger)
+
+		houseIDStr := mux.Vars(r)[househandler.HouseID]
+		houseID, err := strconv.ParseInt(houseIDStr, 10, 64)
+		if err != nil {
+			l.Error("Invalid houseID", "error", err.Error())
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		//PARSE URL PARAMS
+		u, err := url.Parse(r.RequestURI)
+		if err != nil {
+			l.Error("Failed to parse request URI", slog.String("error", err.Error()))
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		values, err := url.ParseQuery(u.RawQuery)
+		if err != nil {
+			l.Error("Failed to parse query parameters", slog.String("error", err.Error()))
+			http.Error(w, userhandler.ErrInvalidURLParams.Error(), http.StatusBadRequest)
+			return
+		}
+
+		page, err := listPage(values)
+		if err != nil {
+			l.Error("Invalid page", slog.String("error", err.Error()))
+			switch {
+			case errors.Is(err, househandler.ErrInvalidLimit):
+				http.Error(w, househandler.ErrInvalidLimit.Error(), http.StatusBadRequest)
+				return
+			default:
+				http.Error(w, househandler.ErrInvalidCursor.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+
+		criteria, err := apartmentCriteria(values)
+		if err != nil {
+			l.Error("Invalid apartments filter", slog.String("error", err.Error()))
+			http.Error(w, househandler.ErrInvalidApartmentsFilter.Error(), http.StatusBadRequest)
+			return
+		}
+
+		//A CURSOR OF ANOTHER ORDER WOULD SILENTLY SKIP ROWS
+		if page.After != nil && (page.After.SortBy != criteria.SortBy || page.After.Descending != criteria.Descending) {
+			l.Error("Cursor doesn't match the requested order", slog.String("sort", page.After.SortBy))
+			http.Error(w, househandler.ErrInvalidCursor.Error(), http.StatusBadRequest)
+			return
+		}
+
+		claims, ok := middleware.ClaimsFromContext(r.Context())
+		if !ok {
+			l.Error("Failed to get claims from context")
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		apartments, next, err := h.apartmentService.Apartments(r.Context(), houseID, page, claims.Role, criteria)
+		if err != nil {
+			l.Error("Failed to get apartments", slog.String("error", err.Error()))
+			switch {
+			case errors.Is(err, apartmentservice.ErrStatusFilterForbidden):
+				http.Error(w, househandler.ErrStatusFilterForbidden.Error(), http.StatusForbidden)
+				return
+			default:
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				return
+			}
+		}
+
+		//AN EMPTY FIRST PAGE IS EITHER AN EMPTY HOUSE OR AN UNKNOWN ONE
+		if len(apartments) == 0 && page.After == nil {
+			if _, err = h.houseService.House(r.Context(), houseID); err != nil {
+				switch {
+				case errors.Is(err, houseservice.ErrHouseNotFound):
+					http.Error(w, househandler.ErrHouseNotFound.Error(), http.StatusNotFound)
+					return
+				default:
+					http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+					return
+				}
+			}
+		}
+
+		apartmentsHandlerModel := make([]apartmenthandlermodel.Apartment, 0, len(apartments))
+		for _, apartment := range apartments {
+			apartmentsHandlerModel = append(apartmentsHandlerModel, apartmenthandlerconverter.ToHandlerModelApartment(apartmentservice.Redact(apartment, claims.UserID, claims.Role)))
+		}
+
+		w.Header().Set(ContentTypeKey, ContentTypeJSON)
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(househandlermodel.ApartmentList{
+			Items:      apartmentsHandlerModel,
+			Pagination: pagination(page, next),
+		})
+	}
+}
+
+// listPage reads either limit/offset or limit/cursor. Mixing offset with a cursor is
+// rejected, the cursor already tells where the page starts. A limit above maxLimit is
+// rejected too, the lists are allocated and fetched with one extra row for it.
+func listPage(values url.Values) (model.Page, error) {
+	limit, err := strconv.Atoi(values.Get(househandler.LimitQueryParams))
+	switch {
+	case errors.Is(err, strconv.ErrRange) || err == nil && limit > maxLimit:
+		return model.Page{}, fmt.Errorf("%w: %s", househandler.ErrInvalidLimit, values.Get(househandler.LimitQueryParams))
+	case err != nil || limit <= 0:
+		limit = defaultLimit
+	}
+
+	offset, _ := strconv.Atoi(values.Get(househandler.OffsetQueryParams))
+	if offset < 0 {
+		offset = 0
+	}
+
+	page := model.Page{Offset: offset, Limit: limit}
+
+	if str := values.Get(househandler.CursorQueryParam); str != "" {
+		if values.Has(househandler.OffsetQueryParams) {
+			return model.Page{}, fmt.Errorf("%s can't be combined with %s", househandler.CursorQueryParam, househandler.OffsetQueryParams)
+		}
+
+		page.After, err = househandler.DecodeCursor(str)
+		if err != nil {
+			return model.Page{}, err
+		}
+		page.Offset = 0
+	}
+
+	return page, nil
+}
+
+func pagination(page model.Page, next *model.Cursor) househandlermodel.Pagination {
+	return househandlermodel.Pagination{
+		Limit:      page.Limit,
+		Offset:     page.Offset,
+		NextCursor: househandler.EncodeCursor(next),
+	}
+}
+
+func apartmentCriteria(values url.Values) (criteria model.ApartmentCriteria, err error) {
+	params := []struct {
+		name  string
+		value *uint32
+	}{
+		{name: househandler.MinPriceQueryParam, value: &criteria.MinPrice},
+		{name: househandler.MaxPriceQueryParam, value: &criteria.MaxPrice},
+		{name: househandler.MinRoomsQueryParam, value: &criteria.MinRooms},
+		{name: househandler.MaxRoomsQueryParam, value: &criteria.MaxRooms},
+	}
+
+	for _, param := range params {
+		str := values.Get(param.name)
+		if str == "" {
+			continue
+		}
+
+		v, err := strconv.ParseUint(str, 10, 32)
+		if err != nil {
+			return model.ApartmentCriteria{}, err
+		}
+		*param.value = uint32(v)
+	}
+
+	//AN EXACT NUMBER OF ROOMS IS A RANGE OF ONE
+	if str := values.Get(househandler.RoomsQueryParam); str != "" {
+		if criteria.MinRooms != 0 || criteria.MaxRooms != 0 {
+			return model.ApartmentCriteria{}, fmt.Errorf("%s can't be combined with a rooms range", househandler.RoomsQueryParam)
+		}
+
+		v, err := strconv.ParseUint(str, 10, 32)
+		if err != nil {
+			return model.ApartmentCriteria{}, err
+		}
+		criteria.MinRooms, criteria.MaxRooms = uint32(v), uint32(v)
+	}
+
+	if criteria.MaxPrice != 0 && criteria.MinPrice > criteria.MaxPrice {
+		return model.ApartmentCriteria{}, fmt.Errorf("%s is greater than %s", househandler.MinPriceQueryParam, househandler.MaxPriceQueryParam)
+	}
+
+	if criteria.MaxRooms != 0 && criteria.MinRooms > criteria.MaxRooms {
+		return model.ApartmentCriteria{}, fmt.Errorf("%s is greater than %s", househandler.MinRoomsQueryParam, househandler.MaxRoomsQueryParam)
+	}
+
+	criteria.ModerationStatus = values.Get(househandler.StatusQueryParam)
+	if criteria.ModerationStatus != "" && !slices.Contains(apartmenthandlermodel.PossibleModerationStatus, criteria.ModerationStatus) {
+		return model.ApartmentCriteria{}, fmt.Errorf("unknown %s %q", househandler.StatusQueryParam, criteria.ModerationStatus)
+	}
+
+	criteria.SortBy = values.Get(househandler.SortQueryParam)
+	if criteria.SortBy != "" && criteria.SortBy != model.SortByPrice && criteria.SortBy != model.SortByRooms {
+		return model.ApartmentCriteria{}, fmt.Errorf("unknown %s %q", househandler.SortQueryParam, criteria.SortBy)
+	}
+
+	switch values.Get(househandler.OrderQueryParam) {
+	case "", househandler.SortOrderAsc:
+	case househandler.SortOrderDesc:
+		criteria.Descending = true
+	default:
+		return model.ApartmentCriteria{}, fmt.Errorf("unknown %s %q", househandler.OrderQueryParam, values.Get(househandler.OrderQueryParam))
+	}
+
+	return criteria, nil
+}
+
+func (h *handler) Subscribe() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		l := logger.EndToEndLogging(r.Context(), h.logger)
+
+		houseIDStr := mux.Vars(r)[househandler.HouseID]
+		houseID, err := strconv.ParseInt(houseIDStr, 10, 64)
+		if err != nil {
+			l.Error("Invalid houseID", "error", err.Error())
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		subscription := househandlermodel.Subscription{}
+		if err = json.NewDecoder(r.Body).Decode(&subscription); err != nil {
+			l.Error("Failed to decode request body", "error", err.Error())
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+		subscription.HouseID = houseID
+
+		//VALIDATION
+		if err = h.validator.Validate(subscription); err != nil {
+			l.Error("Invalid data", "error", err.Error())
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		created, err := h.subscriptionService.Subscribe(r.Context(), househandlerconverter.ToSubscriptionDTO(subscription))
+		if err != nil {
+			switch {
+			case errors.Is(err, subscriptionservice.ErrHouseNotFound):
+				http.Error(w, househandler.ErrHouseNotFound.Error(), http.StatusNotFound)
+				return
+			default:
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				return
+			}
+		}
+
+		w.Header().Set(ContentTypeKey, ContentTypeJSON)
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(househandlerconverter.ToSubscriptionHandlerModel(created))
 	}
 }
 
-func Register(router *mux.Router, houseService houseservice.Service, tm tokenmanager.Manager, logger *slog.Logger) error {
+func Register(router *mux.Router, houseService houseservice.Service, apartmentService apartmentservice.Service, subscriptionService subscriptionservice.Service, tm tokenmanager.Manager, revoked revocation.List, logger *slog.Logger) error {
 	h := &handler{
-		router:       router,
-		houseService: houseService,
-		tm:           tm,
-		logger:       logger,
+		router:              router,
+		houseService:        houseService,
+		apartmentService:    apartmentService,
+		subscriptionService: subscriptionService,
+		tm:                  tm,
+		validator:           validator.New(),
+		logger:              logger,
 	}
 
 	apiRouter := router.PathPrefix(househandler.APIUrl).Subrouter()
-	apiRouter.Use(middleware.Log(logger), middleware.AuthOnly(tm))
+	apiRouter.Use(middleware.Log(logger), middleware.AuthOnly(tm, revoked))
 
 	apiRouter.Path(househandler.HouseUrl).Handler(h.Houses()).Methods(http.MethodGet)
+	apiRouter.Path(househandler.HouseByIDUrl).Handler(h.Apartments()).Methods(http.MethodGet)
+	apiRouter.Path(househandler.SubscribeUrl).Handler(h.Subscribe()).Methods(http.MethodPost)
 
 	moderationRouter := apiRouter.NewRoute().Subrouter()
-	moderationRouter.Use(middleware.CheckRole(tm, moderator))
+	moderationRouter.Use(middleware.CheckRole(tm, revoked, moderator))
 	moderationRouter.Path(househandler.CreateHouseUrl).Handler(h.Create()).Methods(http.MethodPost)
 
 	return nil
@@ -4,13 +4,18 @@ import (
 	househandler "avito/internal/handler/house"
 	househandlermodel "avito/internal/handler/house/model"
 	"avito/internal/middleware"
+	"avito/internal/model"
+	apartmentservice "avito/internal/service/apartment"
 	houseservice "avito/internal/service/house"
+	subscriptionservice "avito/internal/service/subscription"
+	"avito/internal/validator"
+	"avito/pkg/revocation/memory"
 	stubwriter "avito/pkg/stub_writer"
 	tokenmanager "avito/pkg/token_manager"
-	tokenmanagerimpl "avito/pkg/token_manager/implementation"
 	"bytes"
+	"context"
 	"encoding/json"
-	"github.com/golang-jwt/jwt/v5"
+	"fmt"
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
@@ -18,12 +23,13 @@ import (
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
-	"time"
 )
 
 func TestCreate(t *testing.T) {
-	ctrl, mockHouseService, mockTokenManager, router := testHandler(t)
+	ctrl, mockHouseService, _, _, mockTokenManager, router := testHandler(t)
 	defer ctrl.Finish()
 
 	cases := []struct {
@@ -45,14 +51,11 @@ func TestCreate(t *testing.T) {
 				req := httptest.NewRequest(http.MethodPost, househandler.APIUrl+househandler.CreateHouseUrl, bytes.NewReader(houseBytes))
 				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
 
-				m := make(jwt.MapClaims)
-				m[tokenmanagerimpl.UserIDClaimsTag] = float64(uuid.New().ID())
-				m[tokenmanagerimpl.RoleClaimsTag] = "moderator"
-				m[tokenmanagerimpl.ExpClaimsTag] = float64(time.Now().Add(5 * time.Minute).Unix())
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "moderator"}
 
-				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(m, nil)
-				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(m, nil)
-				mockHouseService.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockHouseService.EXPECT().Create(gomock.Any(), gomock.Any()).Return(model.House{HouseId: 1}, nil)
 
 				return req
 			},
@@ -73,7 +76,7 @@ func TestCreate(t *testing.T) {
 }
 
 func TestCreateErr(t *testing.T) {
-	ctrl, mockHouseService, mockTokenManager, router := testHandler(t)
+	ctrl, mockHouseService, _, _, mockTokenManager, router := testHandler(t)
 	defer ctrl.Finish()
 
 	cases := []struct {
@@ -96,13 +99,10 @@ func TestCreateErr(t *testing.T) {
 				req := httptest.NewRequest(http.MethodPost, househandler.APIUrl+househandler.CreateHouseUrl, bytes.NewReader(houseBytes))
 				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
 
-				m := make(jwt.MapClaims)
-				m[tokenmanagerimpl.UserIDClaimsTag] = float64(uuid.New().ID())
-				m[tokenmanagerimpl.RoleClaimsTag] = "client"
-				m[tokenmanagerimpl.ExpClaimsTag] = float64(time.Now().Add(5 * time.Minute).Unix())
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "client"}
 
-				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(m, nil)
-				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(m, nil)
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
 
 				return req
 			},
@@ -122,14 +122,11 @@ func TestCreateErr(t *testing.T) {
 				req := httptest.NewRequest(http.MethodPost, househandler.APIUrl+househandler.CreateHouseUrl, bytes.NewReader(houseBytes))
 				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
 
-				m := make(jwt.MapClaims)
-				m[tokenmanagerimpl.UserIDClaimsTag] = float64(uuid.New().ID())
-				m[tokenmanagerimpl.RoleClaimsTag] = "moderator"
-				m[tokenmanagerimpl.ExpClaimsTag] = float64(time.Now().Add(5 * time.Minute).Unix())
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "moderator"}
 
-				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(m, nil)
-				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(m, nil)
-				mockHouseService.EXPECT().Create(gomock.Any(), gomock.Any()).Return(houseservice.ErrHouseAlreadyExists)
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockHouseService.EXPECT().Create(gomock.Any(), gomock.Any()).Return(model.House{}, houseservice.ErrHouseAlreadyExists)
 
 				return req
 			},
@@ -149,14 +146,11 @@ func TestCreateErr(t *testing.T) {
 				req := httptest.NewRequest(http.MethodPost, househandler.APIUrl+househandler.CreateHouseUrl, bytes.NewReader(houseBytes))
 				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
 
-				m := make(jwt.MapClaims)
-				m[tokenmanagerimpl.UserIDClaimsTag] = float64(uuid.New().ID())
-				m[tokenmanagerimpl.RoleClaimsTag] = "moderator"
-				m[tokenmanagerimpl.ExpClaimsTag] = float64(time.Now().Add(5 * time.Minute).Unix())
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "moderator"}
 
-				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(m, nil)
-				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(m, nil)
-				mockHouseService.EXPECT().Create(gomock.Any(), gomock.Any()).Return(houseservice.ErrInternal)
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockHouseService.EXPECT().Create(gomock.Any(), gomock.Any()).Return(model.House{}, houseservice.ErrInternal)
 
 				return req
 			},
@@ -178,7 +172,7 @@ func TestCreateErr(t *testing.T) {
 }
 
 func TestHouses(t *testing.T) {
-	ctrl, mockHouseService, mockTokenManager, router := testHandler(t)
+	ctrl, mockHouseService, _, _, mockTokenManager, router := testHandler(t)
 	defer ctrl.Finish()
 
 	cases := []struct {
@@ -193,13 +187,29 @@ func TestHouses(t *testing.T) {
 				req := httptest.NewRequest(http.MethodGet, househandler.APIUrl+househandler.HouseUrl, http.NoBody)
 				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
 
-				m := make(jwt.MapClaims)
-				m[tokenmanagerimpl.UserIDClaimsTag] = float64(uuid.New().ID())
-				m[tokenmanagerimpl.RoleClaimsTag] = "client"
-				m[tokenmanagerimpl.ExpClaimsTag] = float64(time.Now().Add(5 * time.Minute).Unix())
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "client"}
 
-				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(m, nil)
-				mockHouseService.EXPECT().Houses(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockHouseService.EXPECT().Houses(gomock.Any(), gomock.Any()).Return(nil, nil, nil)
+
+				return req
+			},
+		},
+		{
+			name:       "OK WITH CURSOR",
+			statusCode: http.StatusOK,
+			prepareFunc: func() *http.Request {
+				values := make(url.Values)
+				values.Set(househandler.LimitQueryParams, "2")
+				values.Set(househandler.CursorQueryParam, househandler.EncodeCursor(&model.Cursor{ID: 7}))
+
+				req := httptest.NewRequest(http.MethodGet, househandler.APIUrl+househandler.HouseUrl+"?"+values.Encode(), http.NoBody)
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "client"}
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockHouseService.EXPECT().Houses(gomock.Any(), model.Page{Limit: 2, After: &model.Cursor{ID: 7}}).Return([]model.House{{HouseId: 8}, {HouseId: 9}}, &model.Cursor{ID: 9}, nil)
 
 				return req
 			},
@@ -220,7 +230,7 @@ func TestHouses(t *testing.T) {
 }
 
 func TestHousesErr(t *testing.T) {
-	ctrl, mockHouseService, mockTokenManager, router := testHandler(t)
+	ctrl, mockHouseService, _, _, mockTokenManager, router := testHandler(t)
 	_ = mockTokenManager
 	_ = mockHouseService
 	defer ctrl.Finish()
@@ -248,13 +258,10 @@ func TestHousesErr(t *testing.T) {
 				req := httptest.NewRequest(http.MethodGet, househandler.APIUrl+househandler.HouseUrl, http.NoBody)
 				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
 
-				m := make(jwt.MapClaims)
-				m[tokenmanagerimpl.UserIDClaimsTag] = float64(uuid.New().ID())
-				m[tokenmanagerimpl.RoleClaimsTag] = "client"
-				m[tokenmanagerimpl.ExpClaimsTag] = float64(time.Now().Add(5 * time.Minute).Unix())
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "client"}
 
-				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(m, nil)
-				mockHouseService.EXPECT().Houses(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, houseservice.ErrHouseNotFound)
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockHouseService.EXPECT().Houses(gomock.Any(), gomock.Any()).Return(nil, nil, houseservice.ErrHouseNotFound)
 
 				return req
 			},
@@ -267,13 +274,651 @@ func TestHousesErr(t *testing.T) {
 				req := httptest.NewRequest(http.MethodGet, househandler.APIUrl+househandler.HouseUrl, http.NoBody)
 				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
 
-				m := make(jwt.MapClaims)
-				m[tokenmanagerimpl.UserIDClaimsTag] = float64(uuid.New().ID())
-				m[tokenmanagerimpl.RoleClaimsTag] = "client"
-				m[tokenmanagerimpl.ExpClaimsTag] = float64(time.Now().Add(5 * time.Minute).Unix())
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "client"}
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockHouseService.EXPECT().Houses(gomock.Any(), gomock.Any()).Return(nil, nil, houseservice.ErrInternal)
+
+				return req
+			},
+		},
+		{
+			name:           "ERR INVALID CURSOR",
+			statusCode:     http.StatusBadRequest,
+			expectedErrMsg: househandler.ErrInvalidCursor.Error(),
+			prepareFunc: func() *http.Request {
+				values := make(url.Values)
+				values.Set(househandler.CursorQueryParam, "not a cursor")
+
+				req := httptest.NewRequest(http.MethodGet, househandler.APIUrl+househandler.HouseUrl+"?"+values.Encode(), http.NoBody)
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "client"}
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+
+				return req
+			},
+		},
+		{
+			name:           "ERR CURSOR WITH OFFSET",
+			statusCode:     http.StatusBadRequest,
+			expectedErrMsg: househandler.ErrInvalidCursor.Error(),
+			prepareFunc: func() *http.Request {
+				values := make(url.Values)
+				values.Set(househandler.OffsetQueryParams, "20")
+				values.Set(househandler.CursorQueryParam, househandler.EncodeCursor(&model.Cursor{ID: 7}))
+
+				req := httptest.NewRequest(http.MethodGet, househandler.APIUrl+househandler.HouseUrl+"?"+values.Encode(), http.NoBody)
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "client"}
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+
+				return req
+			},
+		},
+		{
+			name:           "ERR LIMIT TOO LARGE",
+			statusCode:     http.StatusBadRequest,
+			expectedErrMsg: househandler.ErrInvalidLimit.Error(),
+			prepareFunc: func() *http.Request {
+				values := make(url.Values)
+				values.Set(househandler.LimitQueryParams, "9223372036854775807")
+
+				req := httptest.NewRequest(http.MethodGet, househandler.APIUrl+househandler.HouseUrl+"?"+values.Encode(), http.NoBody)
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "client"}
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+
+				return req
+			},
+		},
+		{
+			name:           "ERR CURSOR OF SORTED LIST",
+			statusCode:     http.StatusBadRequest,
+			expectedErrMsg: househandler.ErrInvalidCursor.Error(),
+			prepareFunc: func() *http.Request {
+				values := make(url.Values)
+				values.Set(househandler.CursorQueryParam, househandler.EncodeCursor(&model.Cursor{SortBy: model.SortByPrice, SortValue: 100, ID: 7}))
+
+				req := httptest.NewRequest(http.MethodGet, househandler.APIUrl+househandler.HouseUrl+"?"+values.Encode(), http.NoBody)
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "client"}
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+
+				return req
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := c.prepareFunc()
+
+			recorder := httptest.NewRecorder()
+
+			router.ServeHTTP(recorder, req)
+
+			assert.Equal(t, c.statusCode, recorder.Code)
+			assert.Contains(t, recorder.Body.String(), c.expectedErrMsg)
+		})
+	}
+}
+
+func TestApartments(t *testing.T) {
+	ctrl, mockHouseService, mockApartmentService, _, mockTokenManager, router := testHandler(t)
+	defer ctrl.Finish()
+
+	cases := []struct {
+		name        string
+		statusCode  int
+		prepareFunc func() *http.Request
+	}{
+		{
+			name:       "OK CLIENT",
+			statusCode: http.StatusOK,
+			prepareFunc: func() *http.Request {
+				apartmentsUrl := strings.ReplaceAll(househandler.APIUrl+househandler.HouseByIDUrl, fmt.Sprintf("{%s}", househandler.HouseID), "1")
+				req := httptest.NewRequest(http.MethodGet, apartmentsUrl, http.NoBody)
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "client"}
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockApartmentService.EXPECT().Apartments(gomock.Any(), int64(1), model.Page{Limit: defaultLimit}, "client", model.ApartmentCriteria{}).Return([]model.Apartment{{ID: 1, HouseID: 1}}, nil, nil)
+
+				return req
+			},
+		},
+		{
+			name:       "OK MODERATOR",
+			statusCode: http.StatusOK,
+			prepareFunc: func() *http.Request {
+				apartmentsUrl := strings.ReplaceAll(househandler.APIUrl+househandler.HouseByIDUrl, fmt.Sprintf("{%s}", househandler.HouseID), "1")
+				req := httptest.NewRequest(http.MethodGet, apartmentsUrl, http.NoBody)
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "moderator"}
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockApartmentService.EXPECT().Apartments(gomock.Any(), int64(1), model.Page{Limit: defaultLimit}, "moderator", model.ApartmentCriteria{}).Return([]model.Apartment{{ID: 1, HouseID: 1}}, nil, nil)
+
+				return req
+			},
+		},
+		{
+			name:       "OK WITH CRITERIA",
+			statusCode: http.StatusOK,
+			prepareFunc: func() *http.Request {
+				values := make(url.Values)
+				values.Set(househandler.MinPriceQueryParam, "100")
+				values.Set(househandler.MaxPriceQueryParam, "500")
+				values.Set(househandler.RoomsQueryParam, "2")
+				values.Set(househandler.SortQueryParam, "price")
+				values.Set(househandler.OrderQueryParam, "desc")
+
+				apartmentsUrl := strings.ReplaceAll(househandler.APIUrl+househandler.HouseByIDUrl, fmt.Sprintf("{%s}", househandler.HouseID), "1")
+				req := httptest.NewRequest(http.MethodGet, apartmentsUrl+"?"+values.Encode(), http.NoBody)
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "client"}
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockApartmentService.EXPECT().Apartments(gomock.Any(), int64(1), model.Page{Limit: defaultLimit}, "client", model.ApartmentCriteria{
+					MinPrice:   100,
+					MaxPrice:   500,
+					MinRooms:   2,
+					MaxRooms:   2,
+					SortBy:     model.SortByPrice,
+					Descending: true,
+				}).Return([]model.Apartment{{ID: 1, HouseID: 1}}, nil, nil)
+
+				return req
+			},
+		},
+		{
+			name:       "OK MODERATOR STATUS FILTER",
+			statusCode: http.StatusOK,
+			prepareFunc: func() *http.Request {
+				values := make(url.Values)
+				values.Set(househandler.StatusQueryParam, "on moderation")
+				values.Set(househandler.MinRoomsQueryParam, "1")
+				values.Set(househandler.MaxRoomsQueryParam, "3")
+				values.Set(househandler.SortQueryParam, "rooms")
+
+				apartmentsUrl := strings.ReplaceAll(househandler.APIUrl+househandler.HouseByIDUrl, fmt.Sprintf("{%s}", househandler.HouseID), "1")
+				req := httptest.NewRequest(http.MethodGet, apartmentsUrl+"?"+values.Encode(), http.NoBody)
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "moderator"}
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockApartmentService.EXPECT().Apartments(gomock.Any(), int64(1), model.Page{Limit: defaultLimit}, "moderator", model.ApartmentCriteria{
+					MinRooms:         1,
+					MaxRooms:         3,
+					ModerationStatus: "on moderation",
+					SortBy:           model.SortByRooms,
+				}).Return([]model.Apartment{{ID: 1, HouseID: 1}}, nil, nil)
+
+				return req
+			},
+		},
+		{
+			name:       "OK EMPTY HOUSE",
+			statusCode: http.StatusOK,
+			prepareFunc: func() *http.Request {
+				apartmentsUrl := strings.ReplaceAll(househandler.APIUrl+househandler.HouseByIDUrl, fmt.Sprintf("{%s}", househandler.HouseID), "1")
+				req := httptest.NewRequest(http.MethodGet, apartmentsUrl, http.NoBody)
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "client"}
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockApartmentService.EXPECT().Apartments(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil, nil)
+				mockHouseService.EXPECT().House(gomock.Any(), int64(1)).Return(model.House{HouseId: 1}, nil)
+
+				return req
+			},
+		},
+		{
+			name:       "OK WITH CURSOR",
+			statusCode: http.StatusOK,
+			prepareFunc: func() *http.Request {
+				cursor := &model.Cursor{SortBy: model.SortByPrice, Descending: true, SortValue: 300, ID: 4}
+
+				values := make(url.Values)
+				values.Set(househandler.SortQueryParam, "price")
+				values.Set(househandler.OrderQueryParam, "desc")
+				values.Set(househandler.CursorQueryParam, househandler.EncodeCursor(cursor))
+
+				apartmentsUrl := strings.ReplaceAll(househandler.APIUrl+househandler.HouseByIDUrl, fmt.Sprintf("{%s}", househandler.HouseID), "1")
+				req := httptest.NewRequest(http.MethodGet, apartmentsUrl+"?"+values.Encode(), http.NoBody)
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "client"}
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockApartmentService.EXPECT().Apartments(gomock.Any(), int64(1), model.Page{Limit: defaultLimit, After: cursor}, "client", model.ApartmentCriteria{
+					SortBy:     model.SortByPrice,
+					Descending: true,
+				}).Return(nil, nil, nil)
+
+				return req
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := c.prepareFunc()
+
+			recorder := httptest.NewRecorder()
+
+			router.ServeHTTP(recorder, req)
+
+			assert.Equal(t, c.statusCode, recorder.Code)
+		})
+	}
+}
+
+func TestApartmentsErr(t *testing.T) {
+	ctrl, mockHouseService, mockApartmentService, _, mockTokenManager, router := testHandler(t)
+	defer ctrl.Finish()
+
+	cases := []struct {
+		name           string
+		statusCode     int
+		expectedErrMsg string
+		prepareFunc    func() *http.Request
+	}{
+		{
+			name:           "ERR INVALID HOUSE ID",
+			statusCode:     http.StatusBadRequest,
+			expectedErrMsg: http.StatusText(http.StatusBadRequest),
+			prepareFunc: func() *http.Request {
+				apartmentsUrl := strings.ReplaceAll(househandler.APIUrl+househandler.HouseByIDUrl, fmt.Sprintf("{%s}", househandler.HouseID), "invalid_house_id")
+				req := httptest.NewRequest(http.MethodGet, apartmentsUrl, http.NoBody)
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "client"}
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+
+				return req
+			},
+		},
+		{
+			name:       "UNAUTHORIZED",
+			statusCode: http.StatusUnauthorized,
+			prepareFunc: func() *http.Request {
+				apartmentsUrl := strings.ReplaceAll(househandler.APIUrl+househandler.HouseByIDUrl, fmt.Sprintf("{%s}", househandler.HouseID), "1")
+				req := httptest.NewRequest(http.MethodGet, apartmentsUrl, http.NoBody)
+
+				return req
+			},
+		},
+		{
+			name:           "ERR INVALID PRICE",
+			statusCode:     http.StatusBadRequest,
+			expectedErrMsg: househandler.ErrInvalidApartmentsFilter.Error(),
+			prepareFunc: func() *http.Request {
+				values := make(url.Values)
+				values.Set(househandler.MinPriceQueryParam, "cheap")
+
+				apartmentsUrl := strings.ReplaceAll(househandler.APIUrl+househandler.HouseByIDUrl, fmt.Sprintf("{%s}", househandler.HouseID), "1")
+				req := httptest.NewRequest(http.MethodGet, apartmentsUrl+"?"+values.Encode(), http.NoBody)
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "client"}
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+
+				return req
+			},
+		},
+		{
+			name:           "ERR LIMIT TOO LARGE",
+			statusCode:     http.StatusBadRequest,
+			expectedErrMsg: househandler.ErrInvalidLimit.Error(),
+			prepareFunc: func() *http.Request {
+				values := make(url.Values)
+				values.Set(househandler.LimitQueryParams, "101")
+
+				apartmentsUrl := strings.ReplaceAll(househandler.APIUrl+househandler.HouseByIDUrl, fmt.Sprintf("{%s}", househandler.HouseID), "1")
+				req := httptest.NewRequest(http.MethodGet, apartmentsUrl+"?"+values.Encode(), http.NoBody)
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "client"}
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+
+				return req
+			},
+		},
+		{
+			name:           "ERR INVALID PRICE RANGE",
+			statusCode:     http.StatusBadRequest,
+			expectedErrMsg: househandler.ErrInvalidApartmentsFilter.Error(),
+			prepareFunc: func() *http.Request {
+				values := make(url.Values)
+				values.Set(househandler.MinPriceQueryParam, "500")
+				values.Set(househandler.MaxPriceQueryParam, "100")
+
+				apartmentsUrl := strings.ReplaceAll(househandler.APIUrl+househandler.HouseByIDUrl, fmt.Sprintf("{%s}", househandler.HouseID), "1")
+				req := httptest.NewRequest(http.MethodGet, apartmentsUrl+"?"+values.Encode(), http.NoBody)
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "client"}
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+
+				return req
+			},
+		},
+		{
+			name:           "ERR ROOMS WITH RANGE",
+			statusCode:     http.StatusBadRequest,
+			expectedErrMsg: househandler.ErrInvalidApartmentsFilter.Error(),
+			prepareFunc: func() *http.Request {
+				values := make(url.Values)
+				values.Set(househandler.RoomsQueryParam, "2")
+				values.Set(househandler.MinRoomsQueryParam, "1")
+
+				apartmentsUrl := strings.ReplaceAll(househandler.APIUrl+househandler.HouseByIDUrl, fmt.Sprintf("{%s}", househandler.HouseID), "1")
+				req := httptest.NewRequest(http.MethodGet, apartmentsUrl+"?"+values.Encode(), http.NoBody)
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "client"}
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+
+				return req
+			},
+		},
+		{
+			name:           "ERR UNKNOWN STATUS",
+			statusCode:     http.StatusBadRequest,
+			expectedErrMsg: househandler.ErrInvalidApartmentsFilter.Error(),
+			prepareFunc: func() *http.Request {
+				values := make(url.Values)
+				values.Set(househandler.StatusQueryParam, "sold")
+
+				apartmentsUrl := strings.ReplaceAll(househandler.APIUrl+househandler.HouseByIDUrl, fmt.Sprintf("{%s}", househandler.HouseID), "1")
+				req := httptest.NewRequest(http.MethodGet, apartmentsUrl+"?"+values.Encode(), http.NoBody)
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "moderator"}
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+
+				return req
+			},
+		},
+		{
+			name:           "ERR UNKNOWN SORT",
+			statusCode:     http.StatusBadRequest,
+			expectedErrMsg: househandler.ErrInvalidApartmentsFilter.Error(),
+			prepareFunc: func() *http.Request {
+				values := make(url.Values)
+				values.Set(househandler.SortQueryParam, "price; DROP TABLE apartments")
+
+				apartmentsUrl := strings.ReplaceAll(househandler.APIUrl+househandler.HouseByIDUrl, fmt.Sprintf("{%s}", househandler.HouseID), "1")
+				req := httptest.NewRequest(http.MethodGet, apartmentsUrl+"?"+values.Encode(), http.NoBody)
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "client"}
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+
+				return req
+			},
+		},
+		{
+			name:           "ERR UNKNOWN ORDER",
+			statusCode:     http.StatusBadRequest,
+			expectedErrMsg: househandler.ErrInvalidApartmentsFilter.Error(),
+			prepareFunc: func() *http.Request {
+				values := make(url.Values)
+				values.Set(househandler.SortQueryParam, "price")
+				values.Set(househandler.OrderQueryParam, "up")
+
+				apartmentsUrl := strings.ReplaceAll(househandler.APIUrl+househandler.HouseByIDUrl, fmt.Sprintf("{%s}", househandler.HouseID), "1")
+				req := httptest.NewRequest(http.MethodGet, apartmentsUrl+"?"+values.Encode(), http.NoBody)
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "client"}
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+
+				return req
+			},
+		},
+		{
+			name:           "ERR STATUS FILTER FORBIDDEN",
+			statusCode:     http.StatusForbidden,
+			expectedErrMsg: househandler.ErrStatusFilterForbidden.Error(),
+			prepareFunc: func() *http.Request {
+				values := make(url.Values)
+				values.Set(househandler.StatusQueryParam, "declined")
+
+				apartmentsUrl := strings.ReplaceAll(househandler.APIUrl+househandler.HouseByIDUrl, fmt.Sprintf("{%s}", househandler.HouseID), "1")
+				req := httptest.NewRequest(http.MethodGet, apartmentsUrl+"?"+values.Encode(), http.NoBody)
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "client"}
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockApartmentService.EXPECT().Apartments(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil, apartmentservice.ErrStatusFilterForbidden)
+
+				return req
+			},
+		},
+		{
+			name:           "ERR HOUSE NOT FOUND",
+			statusCode:     http.StatusNotFound,
+			expectedErrMsg: househandler.ErrHouseNotFound.Error(),
+			prepareFunc: func() *http.Request {
+				apartmentsUrl := strings.ReplaceAll(househandler.APIUrl+househandler.HouseByIDUrl, fmt.Sprintf("{%s}", househandler.HouseID), "1")
+				req := httptest.NewRequest(http.MethodGet, apartmentsUrl, http.NoBody)
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "client"}
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockApartmentService.EXPECT().Apartments(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil, nil)
+				mockHouseService.EXPECT().House(gomock.Any(), int64(1)).Return(model.House{}, houseservice.ErrHouseNotFound)
+
+				return req
+			},
+		},
+		{
+			name:           "ERR INTERNAL",
+			statusCode:     http.StatusInternalServerError,
+			expectedErrMsg: http.StatusText(http.StatusInternalServerError),
+			prepareFunc: func() *http.Request {
+				apartmentsUrl := strings.ReplaceAll(househandler.APIUrl+househandler.HouseByIDUrl, fmt.Sprintf("{%s}", househandler.HouseID), "1")
+				req := httptest.NewRequest(http.MethodGet, apartmentsUrl, http.NoBody)
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "client"}
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockApartmentService.EXPECT().Apartments(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil, apartmentservice.ErrInternal)
+
+				return req
+			},
+		},
+		{
+			name:           "ERR CURSOR ORDER MISMATCH",
+			statusCode:     http.StatusBadRequest,
+			expectedErrMsg: househandler.ErrInvalidCursor.Error(),
+			prepareFunc: func() *http.Request {
+				values := make(url.Values)
+				values.Set(househandler.SortQueryParam, "rooms")
+				values.Set(househandler.CursorQueryParam, househandler.EncodeCursor(&model.Cursor{SortBy: model.SortByPrice, SortValue: 300, ID: 4}))
+
+				apartmentsUrl := strings.ReplaceAll(househandler.APIUrl+househandler.HouseByIDUrl, fmt.Sprintf("{%s}", househandler.HouseID), "1")
+				req := httptest.NewRequest(http.MethodGet, apartmentsUrl+"?"+values.Encode(), http.NoBody)
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "client"}
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+
+				return req
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := c.prepareFunc()
+
+			recorder := httptest.NewRecorder()
+
+			router.ServeHTTP(recorder, req)
+
+			assert.Equal(t, c.statusCode, recorder.Code)
+			assert.Contains(t, recorder.Body.String(), c.expectedErrMsg)
+		})
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	ctrl, _, _, mockSubscriptionService, mockTokenManager, router := testHandler(t)
+	defer ctrl.Finish()
+
+	cases := []struct {
+		name        string
+		statusCode  int
+		prepareFunc func() *http.Request
+	}{
+		{
+			name:       "OK",
+			statusCode: http.StatusOK,
+			prepareFunc: func() *http.Request {
+				subscriptionBytes, err := json.Marshal(househandlermodel.Subscription{
+					Email: "test@gmail.com",
+				})
+				assert.NoError(t, err)
+
+				subscribeUrl := strings.ReplaceAll(househandler.APIUrl+househandler.SubscribeUrl, fmt.Sprintf("{%s}", househandler.HouseID), "1")
+				req := httptest.NewRequest(http.MethodPost, subscribeUrl, bytes.NewReader(subscriptionBytes))
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "client"}
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockSubscriptionService.EXPECT().Subscribe(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, subscription model.Subscription) (model.Subscription, error) {
+					assert.Equal(t, int64(1), subscription.HouseID)
+					assert.Equal(t, "test@gmail.com", subscription.Email)
+					subscription.SubscriptionID = 1
+					return subscription, nil
+				})
+
+				return req
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := c.prepareFunc()
+
+			recorder := httptest.NewRecorder()
+
+			router.ServeHTTP(recorder, req)
+
+			assert.Equal(t, c.statusCode, recorder.Code)
+		})
+	}
+}
+
+func TestSubscribeErr(t *testing.T) {
+	ctrl, _, _, mockSubscriptionService, mockTokenManager, router := testHandler(t)
+	defer ctrl.Finish()
+
+	cases := []struct {
+		name           string
+		statusCode     int
+		expectedErrMsg string
+		prepareFunc    func() *http.Request
+	}{
+		{
+			name:       "UNAUTHORIZED",
+			statusCode: http.StatusUnauthorized,
+			prepareFunc: func() *http.Request {
+				subscribeUrl := strings.ReplaceAll(househandler.APIUrl+househandler.SubscribeUrl, fmt.Sprintf("{%s}", househandler.HouseID), "1")
+				return httptest.NewRequest(http.MethodPost, subscribeUrl, http.NoBody)
+			},
+		},
+		{
+			name:           "ERR INVALID EMAIL",
+			statusCode:     http.StatusBadRequest,
+			expectedErrMsg: validator.ErrInvalidEmail.Error(),
+			prepareFunc: func() *http.Request {
+				subscriptionBytes, err := json.Marshal(househandlermodel.Subscription{
+					Email: "invalid email",
+				})
+				assert.NoError(t, err)
+
+				subscribeUrl := strings.ReplaceAll(househandler.APIUrl+househandler.SubscribeUrl, fmt.Sprintf("{%s}", househandler.HouseID), "1")
+				req := httptest.NewRequest(http.MethodPost, subscribeUrl, bytes.NewReader(subscriptionBytes))
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "client"}
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+
+				return req
+			},
+		},
+		{
+			name:           "ERR HOUSE NOT FOUND",
+			statusCode:     http.StatusNotFound,
+			expectedErrMsg: househandler.ErrHouseNotFound.Error(),
+			prepareFunc: func() *http.Request {
+				subscriptionBytes, err := json.Marshal(househandlermodel.Subscription{
+					Email: "test@gmail.com",
+				})
+				assert.NoError(t, err)
+
+				subscribeUrl := strings.ReplaceAll(househandler.APIUrl+househandler.SubscribeUrl, fmt.Sprintf("{%s}", househandler.HouseID), "1")
+				req := httptest.NewRequest(http.MethodPost, subscribeUrl, bytes.NewReader(subscriptionBytes))
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "client"}
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockSubscriptionService.EXPECT().Subscribe(gomock.Any(), gomock.Any()).Return(model.Subscription{}, subscriptionservice.ErrHouseNotFound)
+
+				return req
+			},
+		},
+		{
+			name:           "ERR INTERNAL",
+			statusCode:     http.StatusInternalServerError,
+			expectedErrMsg: http.StatusText(http.StatusInternalServerError),
+			prepareFunc: func() *http.Request {
+				subscriptionBytes, err := json.Marshal(househandlermodel.Subscription{
+					Email: "test@gmail.com",
+				})
+				assert.NoError(t, err)
+
+				subscribeUrl := strings.ReplaceAll(househandler.APIUrl+househandler.SubscribeUrl, fmt.Sprintf("{%s}", househandler.HouseID), "1")
+				req := httptest.NewRequest(http.MethodPost, subscribeUrl, bytes.NewReader(subscriptionBytes))
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "client"}
 
-				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(m, nil)
-				mockHouseService.EXPECT().Houses(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, houseservice.ErrInternal)
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockSubscriptionService.EXPECT().Subscribe(gomock.Any(), gomock.Any()).Return(model.Subscription{}, subscriptionservice.ErrInternal)
 
 				return req
 			},
@@ -296,17 +941,19 @@ func TestHousesErr(t *testing.T) {
 
 //func (h *handler) Houses() http.HandlerFunc {
 
-func testHandler(t *testing.T) (ctrl *gomock.Controller, mockHouseService *houseservice.MockService, mockTokenManager *tokenmanager.MockManager, router *mux.Router) {
+func testHandler(t *testing.T) (ctrl *gomock.Controller, mockHouseService *houseservice.MockService, mockApartmentService *apartmentservice.MockService, mockSubscriptionService *subscriptionservice.MockService, mockTokenManager *tokenmanager.MockManager, router *mux.Router) {
 	ctrl = gomock.NewController(t)
 
 	mockHouseService = houseservice.NewMockService(ctrl)
+	mockApartmentService = apartmentservice.NewMockService(ctrl)
+	mockSubscriptionService = subscriptionservice.NewMockService(ctrl)
 	mockTokenManager = tokenmanager.NewMockManager(ctrl)
 
 	router = mux.NewRouter()
 	logger := slog.New(slog.NewTextHandler(&stubwriter.Writer{}, nil))
 
-	err := Register(router, mockHouseService, mockTokenManager, logger)
+	err := Register(router, mockHouseService, mockApartmentService, mockSubscriptionService, mockTokenManager, memory.New(0), logger)
 	assert.NoError(t, err)
 
-	return ctrl, mockHouseService, mockTokenManager, router
+	return ctrl, mockHouseService, mockApartmentService, mockSubscriptionService, mockTokenManager, router
 }
@@ -6,9 +6,28 @@ var (
 	APIUrl         = "/api/v1"
 	HouseUrl       = "/house"
 	CreateHouseUrl = fmt.Sprintf("%s/create", HouseUrl)
+
+	HouseID      = "house_id"
+	HouseByIDUrl = fmt.Sprintf("%s/{%s}", HouseUrl, HouseID)
+	SubscribeUrl = fmt.Sprintf("%s/subscribe", HouseByIDUrl)
 )
 
 var (
 	LimitQueryParams  = "limit"
 	OffsetQueryParams = "offset"
+	CursorQueryParam  = "cursor"
+
+	MinPriceQueryParam = "min_price"
+	MaxPriceQueryParam = "max_price"
+	RoomsQueryParam    = "rooms"
+	MinRoomsQueryParam = "min_rooms"
+	MaxRoomsQueryParam = "max_rooms"
+	StatusQueryParam   = "status"
+	SortQueryParam     = "sort"
+	OrderQueryParam    = "order"
+)
+
+var (
+	SortOrderAsc  = "asc"
+	SortOrderDesc = "desc"
 )
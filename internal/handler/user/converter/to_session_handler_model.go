@@ -0,0 +1,18 @@
+package userhandlerconverter
+
+import (
+	userhandlermodel "avito/internal/handler/user/model"
+	"avito/internal/model"
+)
+
+// ToSessionHandlerModel marks the session the request was made from as current.
+func ToSessionHandlerModel(session model.Session, currentSessionID int64) userhandlermodel.Session {
+	return userhandlermodel.Session{
+		SessionID:  session.SessionID,
+		UserAgent:  session.UserAgent,
+		IP:         session.IP,
+		CreatedAt:  session.CreatedAt,
+		LastUsedAt: session.LastUsedAt,
+		Current:    session.SessionID == currentSessionID,
+	}
+}
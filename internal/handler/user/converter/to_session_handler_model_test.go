@@ -0,0 +1,17 @@
+package userhandlerconverter
+
+import (
+	"avito/internal/model"
+	"testing"
+)
+
+func BenchmarkToSessionHandlerModel(b *testing.B) {
+	b.ReportAllocs()
+
+	session := model.Session{}
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		ToSessionHandlerModel(session, 0)
+	}
+}
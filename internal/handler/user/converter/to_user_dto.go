@@ -4,7 +4,6 @@ import (
 	userhandlermodel "avito/internal/handler/user/model"
 	"avito/internal/model"
 	"avito/pkg/hasher"
-	"github.com/google/uuid"
 )
 
 func ToUserDto(user userhandlermodel.User) (model.User, error) {
@@ -14,7 +13,6 @@ func ToUserDto(user userhandlermodel.User) (model.User, error) {
 	}
 
 	return model.User{
-		ID:           uuid.New().ID(),
 		Role:         user.Role,
 		Email:        user.Email,
 		HashPassword: hash,
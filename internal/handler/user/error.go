@@ -9,4 +9,10 @@ var (
 	ErrInvalidURLParams    = errors.New("invalid url params")
 	ErrEmailAlreadyTaken   = errors.New("email already taken")
 	ErrNoSession           = errors.New("no session by refresh token. login again")
+	ErrRefreshTokenReused  = errors.New("refresh token already used, the session is revoked. login again")
+	ErrInvalidUserType     = errors.New("invalid user type. possible user types: client, moderator")
+	ErrSessionNotFound     = errors.New("session not found")
+	ErrInvalidSessionID    = errors.New("invalid session id")
+	ErrInvalidUserID       = errors.New("invalid user id")
+	ErrUserNotFound        = errors.New("user not found")
 )
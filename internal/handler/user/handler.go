@@ -5,4 +5,5 @@ import "net/http"
 type Handler interface {
 	Registration() http.HandlerFunc
 	Login() http.HandlerFunc
+	DummyLogin() http.HandlerFunc
 }
@@ -0,0 +1,5 @@
+package userhandlermodel
+
+type RoleUpdate struct {
+	Role string `json:"role" validate:"required,role"`
+}
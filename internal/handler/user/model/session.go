@@ -0,0 +1,12 @@
+package userhandlermodel
+
+import "time"
+
+type Session struct {
+	SessionID  int64     `json:"session_id"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	Current    bool      `json:"current"`
+}
@@ -5,17 +5,26 @@ import (
 	userhandlerconverter "avito/internal/handler/user/converter"
 	userhandlermodel "avito/internal/handler/user/model"
 	"avito/internal/middleware"
+	"avito/internal/model"
 	sessionservice "avito/internal/service/session"
 	userservice "avito/internal/service/user"
 	"avito/internal/validator"
 	"avito/pkg/logger"
+	"avito/pkg/revocation"
 	tokenmanager "avito/pkg/token_manager"
 	"encoding/json"
 	"errors"
 	"github.com/gorilla/mux"
 	"log/slog"
+	"net"
 	"net/http"
 	"net/url"
+	"slices"
+	"strconv"
+)
+
+const (
+	moderator = "moderator"
 )
 
 type handler struct {
@@ -24,6 +33,8 @@ type handler struct {
 	userService    userservice.Service
 	sessionService sessionservice.Service
 
+	tm tokenmanager.Manager
+
 	validator *validator.Validate
 
 	logger *slog.Logger
@@ -57,8 +68,9 @@ func (h *handler) Registration() http.HandlerFunc {
 			return
 		}
 
-		//SAVE USER
-		if err = h.userService.Save(r.Context(), userDto); err != nil {
+		//SAVE USER WITH SESSION
+		accessToken, refreshToken, err := h.userService.Register(r.Context(), userDto, device(r))
+		if err != nil {
 			switch {
 			case errors.Is(err, userservice.ErrEmailAlreadyTaken):
 				http.Error(w, userhandler.ErrEmailAlreadyTaken.Error(), http.StatusBadRequest)
@@ -69,13 +81,6 @@ func (h *handler) Registration() http.HandlerFunc {
 			}
 		}
 
-		//CREATE SESSION
-		accessToken, refreshToken, err := h.sessionService.Create(r.Context(), userDto.ID, userDto.Role)
-		if err != nil {
-			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
-			return
-		}
-
 		tokens := userhandlermodel.Tokens{
 			AccessToken:  accessToken,
 			RefreshToken: refreshToken,
@@ -104,7 +109,7 @@ func (h *handler) Login() http.HandlerFunc {
 			return
 		}
 
-		userID, err := h.userService.LogIn(r.Context(), user.Email, user.Password)
+		storedUser, err := h.userService.LogIn(r.Context(), user.Email, user.Password)
 		if err != nil {
 			switch {
 			case errors.Is(err, userservice.ErrCredentialsInvalid):
@@ -116,7 +121,8 @@ func (h *handler) Login() http.HandlerFunc {
 			}
 		}
 
-		accessToken, refreshToken, err := h.sessionService.ResetSession(r.Context(), userID, user.Role)
+		//THE ROLE FROM THE BODY IS IGNORED, ONLY THE STORED ONE IS TRUSTED
+		accessToken, refreshToken, err := h.sessionService.Create(r.Context(), storedUser.ID, storedUser.Role, device(r))
 		if err != nil {
 			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 			return
@@ -159,10 +165,14 @@ func (h *handler) UpdateTokens() http.HandlerFunc {
 		}
 
 		refreshToken := values.Get(userhandler.RefreshTokenQueryParam)
-		userID := r.Context().Value(middleware.UserIDCtxKey).(uint32)
-		role := r.Context().Value(middleware.RoleCtxKey).(string)
+		claims, ok := middleware.ClaimsFromContext(r.Context())
+		if !ok {
+			l.Error("Failed to get claims from context")
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
 
-		accessToken, refreshToken, err := h.sessionService.Update(r.Context(), userID, role, refreshToken)
+		accessToken, refreshToken, err := h.sessionService.Update(r.Context(), claims.UserID, claims.SessionID, refreshToken)
 		if err != nil {
 			switch {
 			case errors.Is(err, sessionservice.ErrNoSession):
@@ -171,6 +181,9 @@ func (h *handler) UpdateTokens() http.HandlerFunc {
 			case errors.Is(err, sessionservice.ErrInvalidRefreshToken):
 				http.Error(w, userhandler.ErrInvalidRefreshToken.Error(), http.StatusBadRequest)
 				return
+			case errors.Is(err, sessionservice.ErrRefreshTokenReused):
+				http.Error(w, userhandler.ErrRefreshTokenReused.Error(), http.StatusUnauthorized)
+				return
 			default:
 				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 				return
@@ -187,11 +200,209 @@ func (h *handler) UpdateTokens() http.HandlerFunc {
 	}
 }
 
-func Register(router *mux.Router, userService userservice.Service, sessionService sessionservice.Service, tm tokenmanager.Manager, logger *slog.Logger) error {
+func (h *handler) DummyLogin() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		l := logger.EndToEndLogging(r.Context(), h.logger)
+
+		//PARSE URL PARAMS
+		u, err := url.Parse(r.RequestURI)
+		if err != nil {
+			l.Error("Failed to parse request URI", slog.String("error", err.Error()))
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		values, err := url.ParseQuery(u.RawQuery)
+		if err != nil {
+			l.Error("Failed to parse query parameters", slog.String("error", err.Error()))
+			http.Error(w, userhandler.ErrInvalidURLParams.Error(), http.StatusBadRequest)
+			return
+		}
+
+		userType := values.Get(userhandler.UserTypeQueryParam)
+		if !slices.Contains(userhandlermodel.PossibleRoles, userType) {
+			l.Error("Invalid user type", slog.String("user_type", userType))
+			http.Error(w, userhandler.ErrInvalidUserType.Error(), http.StatusBadRequest)
+			return
+		}
+
+		accessToken, _, err := h.tm.GenerateDummyAccessToken(userType)
+		if err != nil {
+			l.Error("Failed to generate access token", "error", err.Error())
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		tokens := userhandlermodel.Tokens{
+			AccessToken: accessToken,
+		}
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(tokens)
+	}
+}
+
+func (h *handler) Sessions() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		l := logger.EndToEndLogging(r.Context(), h.logger)
+
+		claims, ok := middleware.ClaimsFromContext(r.Context())
+		if !ok {
+			l.Error("Failed to get claims from context")
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		sessions, err := h.sessionService.Sessions(r.Context(), claims.UserID)
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		sessionsHandlerModel := make([]userhandlermodel.Session, 0, len(sessions))
+		for _, session := range sessions {
+			sessionsHandlerModel = append(sessionsHandlerModel, userhandlerconverter.ToSessionHandlerModel(session, claims.SessionID))
+		}
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(sessionsHandlerModel)
+	}
+}
+
+func (h *handler) RevokeSession() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		l := logger.EndToEndLogging(r.Context(), h.logger)
+
+		sessionIDStr := mux.Vars(r)[userhandler.SessionID]
+		sessionID, err := strconv.ParseInt(sessionIDStr, 10, 64)
+		if err != nil {
+			l.Error("Invalid sessionID", "error", err.Error())
+			http.Error(w, userhandler.ErrInvalidSessionID.Error(), http.StatusBadRequest)
+			return
+		}
+
+		userID, ok := middleware.UserIDFromContext(r.Context())
+		if !ok {
+			l.Error("Failed to get user id from context")
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		if err = h.sessionService.Revoke(r.Context(), userID, sessionID); err != nil {
+			switch {
+			case errors.Is(err, sessionservice.ErrNoSession):
+				http.Error(w, userhandler.ErrSessionNotFound.Error(), http.StatusNotFound)
+				return
+			default:
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				return
+			}
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+func (h *handler) RevokeAllSessions() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		l := logger.EndToEndLogging(r.Context(), h.logger)
+
+		userID, ok := middleware.UserIDFromContext(r.Context())
+		if !ok {
+			l.Error("Failed to get user id from context")
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		if err := h.sessionService.RevokeAll(r.Context(), userID); err != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+func (h *handler) Logout() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		l := logger.EndToEndLogging(r.Context(), h.logger)
+
+		claims, ok := middleware.ClaimsFromContext(r.Context())
+		if !ok {
+			l.Error("Failed to get claims from context")
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		if err := h.sessionService.LogOut(r.Context(), claims); err != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+func (h *handler) ChangeRole() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		l := logger.EndToEndLogging(r.Context(), h.logger)
+
+		userIDStr := mux.Vars(r)[userhandler.UserID]
+		userID, err := strconv.ParseInt(userIDStr, 10, 64)
+		if err != nil {
+			l.Error("Invalid userID", "error", err.Error())
+			http.Error(w, userhandler.ErrInvalidUserID.Error(), http.StatusBadRequest)
+			return
+		}
+
+		roleUpdate := userhandlermodel.RoleUpdate{}
+		if err = json.NewDecoder(r.Body).Decode(&roleUpdate); err != nil {
+			l.Error("Failed to decode request body", "error", err.Error())
+			http.Error(w, userhandler.ErrDecodeBody.Error(), http.StatusBadRequest)
+			return
+		}
+
+		//VALIDATION
+		if err = h.validator.Validate(roleUpdate); err != nil {
+			l.Error("Invalid data", "error", err.Error())
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if _, err = h.userService.ChangeRole(r.Context(), userID, roleUpdate.Role); err != nil {
+			switch {
+			case errors.Is(err, userservice.ErrUserNotFound):
+				http.Error(w, userhandler.ErrUserNotFound.Error(), http.StatusNotFound)
+				return
+			default:
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				return
+			}
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+// device describes the client of the request, the ip is the peer address of the connection.
+func device(r *http.Request) model.Device {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+
+	return model.Device{
+		UserAgent: r.UserAgent(),
+		IP:        ip,
+	}
+}
+
+func Register(router *mux.Router, userService userservice.Service, sessionService sessionservice.Service, tm tokenmanager.Manager, revoked revocation.List, dummyLoginEnabled bool, logger *slog.Logger) error {
 	h := &handler{
 		router:         router,
 		userService:    userService,
 		sessionService: sessionService,
+		tm:             tm,
 		validator:      validator.New(),
 		logger:         logger,
 	}
@@ -215,9 +426,25 @@ func Register(router *mux.Router, userService userservice.Service, sessionServic
 	apiRouter.Path(userhandler.RegistrationUrl).Handler(h.Registration()).Methods(http.MethodPost)
 	apiRouter.Path(userhandler.LoginUrl).Handler(h.Login()).Methods(http.MethodPost)
 
+	if dummyLoginEnabled {
+		apiRouter.Path(userhandler.DummyLoginUrl).Handler(h.DummyLogin()).Methods(http.MethodGet)
+	}
+
 	moderationRouter := apiRouter.NewRoute().Subrouter()
 	moderationRouter.Use(middleware.ParseAuthToken(tm))
 	moderationRouter.Path(userhandler.UpdateTokensUrl).Handler(h.UpdateTokens()).Methods(http.MethodGet)
 
+	sessionsRouter := apiRouter.NewRoute().Subrouter()
+	sessionsRouter.Use(middleware.AuthOnly(tm, revoked), middleware.RegisteredOnly())
+	sessionsRouter.Path(userhandler.LogoutUrl).Handler(h.Logout()).Methods(http.MethodPost)
+	sessionsRouter.Path(userhandler.SessionsUrl).Handler(h.Sessions()).Methods(http.MethodGet)
+	sessionsRouter.Path(userhandler.SessionsUrl).Handler(h.RevokeAllSessions()).Methods(http.MethodDelete)
+	sessionsRouter.Path(userhandler.SessionUrl).Handler(h.RevokeSession()).Methods(http.MethodDelete)
+
+	//ONLY A REGISTERED MODERATOR CHANGES ROLES
+	rolesRouter := apiRouter.NewRoute().Subrouter()
+	rolesRouter.Use(middleware.CheckRole(tm, revoked, moderator), middleware.RegisteredOnly())
+	rolesRouter.Path(userhandler.UserRoleUrl).Handler(h.ChangeRole()).Methods(http.MethodPut)
+
 	return nil
 }
@@ -4,14 +4,16 @@ import (
 	userhandler "avito/internal/handler/user"
 	userhandlermodel "avito/internal/handler/user/model"
 	"avito/internal/middleware"
+	"avito/internal/model"
 	sessionservice "avito/internal/service/session"
 	userservice "avito/internal/service/user"
 	"avito/internal/validator"
+	"avito/pkg/revocation/memory"
 	stubwriter "avito/pkg/stub_writer"
 	tokenmanager "avito/pkg/token_manager"
-	tokenmanagerimpl "avito/pkg/token_manager/implementation"
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
@@ -27,7 +29,7 @@ import (
 )
 
 func TestRegistration(t *testing.T) {
-	ctrl, mockUserService, mockSessionService, _, router := testHandler(t)
+	ctrl, mockUserService, _, _, router := testHandler(t)
 	defer ctrl.Finish()
 
 	cases := []struct {
@@ -45,8 +47,7 @@ func TestRegistration(t *testing.T) {
 					Password: "123456",
 				}
 
-				mockUserService.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil)
-				mockSessionService.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Return("", "", nil)
+				mockUserService.EXPECT().Register(gomock.Any(), gomock.Any(), gomock.Any()).Return("", "", nil)
 				return user
 			},
 		},
@@ -70,7 +71,7 @@ func TestRegistration(t *testing.T) {
 }
 
 func TestRegistrationErr(t *testing.T) {
-	ctrl, mockUserService, mockSessionService, _, router := testHandler(t)
+	ctrl, mockUserService, _, _, router := testHandler(t)
 	defer ctrl.Finish()
 
 	cases := []struct {
@@ -132,7 +133,7 @@ func TestRegistrationErr(t *testing.T) {
 					Password: "123456",
 				}
 
-				mockUserService.EXPECT().Save(gomock.Any(), gomock.Any()).Return(userservice.ErrEmailAlreadyTaken)
+				mockUserService.EXPECT().Register(gomock.Any(), gomock.Any(), gomock.Any()).Return("", "", userservice.ErrEmailAlreadyTaken)
 				return user
 			},
 		},
@@ -147,23 +148,7 @@ func TestRegistrationErr(t *testing.T) {
 					Password: "123456",
 				}
 
-				mockUserService.EXPECT().Save(gomock.Any(), gomock.Any()).Return(userservice.ErrInternal)
-				return user
-			},
-		},
-		{
-			name:           "SESSION SERVICE INTERNAL ERROR",
-			statusCode:     http.StatusInternalServerError,
-			expectedErrMsg: http.StatusText(http.StatusInternalServerError),
-			prepareFunc: func() userhandlermodel.User {
-				user := userhandlermodel.User{
-					Role:     "client",
-					Email:    "test@gmail.com",
-					Password: "123456",
-				}
-
-				mockUserService.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil)
-				mockSessionService.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any()).Return("", "", sessionservice.ErrInternal)
+				mockUserService.EXPECT().Register(gomock.Any(), gomock.Any(), gomock.Any()).Return("", "", userservice.ErrInternal)
 				return user
 			},
 		},
@@ -210,8 +195,53 @@ func TestLogin(t *testing.T) {
 
 				req := httptest.NewRequest(http.MethodPost, userhandler.APIUrl+userhandler.LoginUrl, bytes.NewReader(userBytes))
 
-				mockUserService.EXPECT().LogIn(gomock.Any(), gomock.Any(), gomock.Any()).Return(uint32(0), nil)
-				mockSessionService.EXPECT().ResetSession(gomock.Any(), gomock.Any(), gomock.Any()).Return("", "", nil)
+				mockUserService.EXPECT().LogIn(gomock.Any(), gomock.Any(), gomock.Any()).Return(model.User{}, nil)
+				mockSessionService.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return("", "", nil)
+
+				return req
+			},
+		},
+		{
+			//A CLIENT CAN'T LOG IN AS A MODERATOR BY SETTING THE ROLE IN THE BODY
+			name:       "ROLE FROM BODY IS IGNORED",
+			statusCode: http.StatusOK,
+			prepareFunc: func() *http.Request {
+				user := &userhandlermodel.User{
+					Role:     "moderator",
+					Email:    "test@gmail.com",
+					Password: "123456",
+				}
+
+				userBytes, err := json.Marshal(user)
+				assert.NoError(t, err)
+
+				req := httptest.NewRequest(http.MethodPost, userhandler.APIUrl+userhandler.LoginUrl, bytes.NewReader(userBytes))
+
+				mockUserService.EXPECT().LogIn(gomock.Any(), "test@gmail.com", "123456").Return(model.User{ID: 1, Role: "client", Email: "test@gmail.com"}, nil)
+				mockSessionService.EXPECT().Create(gomock.Any(), int64(1), "client", gomock.Any()).Return("", "", nil)
+
+				return req
+			},
+		},
+		{
+			//EVERY LOGIN STARTS A SESSION OF ITS OWN ON THE DEVICE
+			name:       "DEVICE IS RECORDED",
+			statusCode: http.StatusOK,
+			prepareFunc: func() *http.Request {
+				user := &userhandlermodel.User{
+					Email:    "test@gmail.com",
+					Password: "123456",
+				}
+
+				userBytes, err := json.Marshal(user)
+				assert.NoError(t, err)
+
+				req := httptest.NewRequest(http.MethodPost, userhandler.APIUrl+userhandler.LoginUrl, bytes.NewReader(userBytes))
+				req.Header.Set("User-Agent", "phone")
+				req.RemoteAddr = "10.0.0.1:5555"
+
+				mockUserService.EXPECT().LogIn(gomock.Any(), gomock.Any(), gomock.Any()).Return(model.User{ID: 1, Role: "client"}, nil)
+				mockSessionService.EXPECT().Create(gomock.Any(), int64(1), "client", model.Device{UserAgent: "phone", IP: "10.0.0.1"}).Return("", "", nil)
 
 				return req
 			},
@@ -255,7 +285,7 @@ func TestLoginErr(t *testing.T) {
 
 				req := httptest.NewRequest(http.MethodPost, userhandler.APIUrl+userhandler.LoginUrl, bytes.NewReader(userBytes))
 
-				mockUserService.EXPECT().LogIn(gomock.Any(), gomock.Any(), gomock.Any()).Return(uint32(0), userservice.ErrCredentialsInvalid)
+				mockUserService.EXPECT().LogIn(gomock.Any(), gomock.Any(), gomock.Any()).Return(model.User{}, userservice.ErrCredentialsInvalid)
 
 				return req
 			},
@@ -275,8 +305,8 @@ func TestLoginErr(t *testing.T) {
 
 				req := httptest.NewRequest(http.MethodPost, userhandler.APIUrl+userhandler.LoginUrl, bytes.NewReader(userBytes))
 
-				mockUserService.EXPECT().LogIn(gomock.Any(), gomock.Any(), gomock.Any()).Return(uint32(0), userservice.ErrInternal)
-				//mockSessionService.EXPECT().ResetSession(gomock.Any(), gomock.Any(), gomock.Any()).Return("", "", nil)
+				mockUserService.EXPECT().LogIn(gomock.Any(), gomock.Any(), gomock.Any()).Return(model.User{}, userservice.ErrInternal)
+				//mockSessionService.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return("", "", nil)
 
 				return req
 			},
@@ -296,8 +326,8 @@ func TestLoginErr(t *testing.T) {
 
 				req := httptest.NewRequest(http.MethodPost, userhandler.APIUrl+userhandler.LoginUrl, bytes.NewReader(userBytes))
 
-				mockUserService.EXPECT().LogIn(gomock.Any(), gomock.Any(), gomock.Any()).Return(uint32(0), nil)
-				mockSessionService.EXPECT().ResetSession(gomock.Any(), gomock.Any(), gomock.Any()).Return("", "", sessionservice.ErrInternal)
+				mockUserService.EXPECT().LogIn(gomock.Any(), gomock.Any(), gomock.Any()).Return(model.User{}, nil)
+				mockSessionService.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return("", "", sessionservice.ErrInternal)
 
 				return req
 			},
@@ -338,14 +368,53 @@ func TestUpdateTokens(t *testing.T) {
 				req := httptest.NewRequest(http.MethodGet, u, http.NoBody)
 				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
 
-				m := make(jwt.MapClaims)
-				m[tokenmanagerimpl.UserIDClaimsTag] = float64(uuid.New().ID())
-				m[tokenmanagerimpl.RoleClaimsTag] = "client"
-				m[tokenmanagerimpl.ExpClaimsTag] = time.Now().Add(5 * time.Minute)
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "client"}
 
-				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(m, nil)
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
 				mockSessionService.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return("", "", nil)
 
+				return req
+			},
+		},
+		{
+			//AN EXPIRED ACCESS TOKEN WITH A VALID SIGNATURE IS ENOUGH TO REFRESH
+			name:       "EXPIRED ACCESS TOKEN",
+			statusCode: http.StatusOK,
+			prepareFunc: func() *http.Request {
+				values := make(url.Values)
+				values.Set("refresh_token", "refresh_token")
+
+				u := fmt.Sprintf("%s%s?%s", userhandler.APIUrl, userhandler.UpdateTokensUrl, values.Encode())
+
+				req := httptest.NewRequest(http.MethodGet, u, http.NoBody)
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				claims := tokenmanager.Claims{UserID: 1, Role: "client", SessionID: 7}
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, tokenmanager.ErrTokenExpired)
+				mockSessionService.EXPECT().Update(gomock.Any(), int64(1), int64(7), "refresh_token").Return("", "", nil)
+
+				return req
+			},
+		},
+		{
+			//THE ROLE FROM THE OLD TOKEN ISN'T PASSED ON, THE SESSION SERVICE RESOLVES IT
+			name:       "ROLE FROM TOKEN IS IGNORED",
+			statusCode: http.StatusOK,
+			prepareFunc: func() *http.Request {
+				values := make(url.Values)
+				values.Set("refresh_token", "refresh_token")
+
+				u := fmt.Sprintf("%s%s?%s", userhandler.APIUrl, userhandler.UpdateTokensUrl, values.Encode())
+
+				req := httptest.NewRequest(http.MethodGet, u, http.NoBody)
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				claims := tokenmanager.Claims{UserID: 1, Role: "moderator", SessionID: 7}
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockSessionService.EXPECT().Update(gomock.Any(), int64(1), int64(7), "refresh_token").Return("", "", nil)
+
 				return req
 			},
 		},
@@ -386,12 +455,9 @@ func TestUpdateTokensErr(t *testing.T) {
 				req := httptest.NewRequest(http.MethodGet, u, http.NoBody)
 				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
 
-				m := make(jwt.MapClaims)
-				m[tokenmanagerimpl.UserIDClaimsTag] = float64(uuid.New().ID())
-				m[tokenmanagerimpl.RoleClaimsTag] = "client"
-				m[tokenmanagerimpl.ExpClaimsTag] = time.Now().Add(5 * time.Minute)
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "client"}
 
-				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(m, nil)
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
 				mockSessionService.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return("", "", sessionservice.ErrNoSession)
 
 				return req
@@ -410,17 +476,53 @@ func TestUpdateTokensErr(t *testing.T) {
 				req := httptest.NewRequest(http.MethodGet, u, http.NoBody)
 				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
 
-				m := make(jwt.MapClaims)
-				m[tokenmanagerimpl.UserIDClaimsTag] = float64(uuid.New().ID())
-				m[tokenmanagerimpl.RoleClaimsTag] = "client"
-				m[tokenmanagerimpl.ExpClaimsTag] = time.Now().Add(5 * time.Minute)
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "client"}
 
-				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(m, nil)
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
 				mockSessionService.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return("", "", sessionservice.ErrInvalidRefreshToken)
 
 				return req
 			},
 		},
+		{
+			name:           "REFRESH TOKEN REUSED",
+			statusCode:     http.StatusUnauthorized,
+			expectedErrMsg: userhandler.ErrRefreshTokenReused.Error(),
+			prepareFunc: func() *http.Request {
+				values := make(url.Values)
+				values.Set("refresh_token", "refresh_token")
+
+				u := fmt.Sprintf("%s%s?%s", userhandler.APIUrl, userhandler.UpdateTokensUrl, values.Encode())
+
+				req := httptest.NewRequest(http.MethodGet, u, http.NoBody)
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "client"}
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockSessionService.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return("", "", sessionservice.ErrRefreshTokenReused)
+
+				return req
+			},
+		},
+		{
+			name:           "FORGED ACCESS TOKEN",
+			statusCode:     http.StatusUnauthorized,
+			expectedErrMsg: middleware.ErrInvalidAuthHeader.Error(),
+			prepareFunc: func() *http.Request {
+				values := make(url.Values)
+				values.Set("refresh_token", "refresh_token")
+
+				u := fmt.Sprintf("%s%s?%s", userhandler.APIUrl, userhandler.UpdateTokensUrl, values.Encode())
+
+				req := httptest.NewRequest(http.MethodGet, u, http.NoBody)
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(tokenmanager.Claims{}, tokenmanager.ErrInvalidToken)
+
+				return req
+			},
+		},
 		{
 			name:           "ERR INTERNAL",
 			statusCode:     http.StatusInternalServerError,
@@ -434,12 +536,9 @@ func TestUpdateTokensErr(t *testing.T) {
 				req := httptest.NewRequest(http.MethodGet, u, http.NoBody)
 				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
 
-				m := make(jwt.MapClaims)
-				m[tokenmanagerimpl.UserIDClaimsTag] = float64(uuid.New().ID())
-				m[tokenmanagerimpl.RoleClaimsTag] = "client"
-				m[tokenmanagerimpl.ExpClaimsTag] = time.Now().Add(5 * time.Minute)
+				claims := tokenmanager.Claims{UserID: int64(uuid.New().ID()), Role: "client"}
 
-				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(m, nil)
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
 				mockSessionService.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return("", "", sessionservice.ErrInternal)
 
 				return req
@@ -460,6 +559,557 @@ func TestUpdateTokensErr(t *testing.T) {
 	}
 }
 
+func TestDummyLogin(t *testing.T) {
+	ctrl, _, _, mockTokenManager, router := testHandler(t)
+	defer ctrl.Finish()
+
+	cases := []struct {
+		name        string
+		statusCode  int
+		prepareFunc func() *http.Request
+	}{
+		{
+			name:       "OK CLIENT",
+			statusCode: http.StatusOK,
+			prepareFunc: func() *http.Request {
+				values := make(url.Values)
+				values.Set(userhandler.UserTypeQueryParam, "client")
+
+				u := fmt.Sprintf("%s%s?%s", userhandler.APIUrl, userhandler.DummyLoginUrl, values.Encode())
+				req := httptest.NewRequest(http.MethodGet, u, http.NoBody)
+
+				mockTokenManager.EXPECT().GenerateDummyAccessToken("client").Return("access-token", tokenmanager.Claims{}, nil)
+
+				return req
+			},
+		},
+		{
+			name:       "OK MODERATOR",
+			statusCode: http.StatusOK,
+			prepareFunc: func() *http.Request {
+				values := make(url.Values)
+				values.Set(userhandler.UserTypeQueryParam, "moderator")
+
+				u := fmt.Sprintf("%s%s?%s", userhandler.APIUrl, userhandler.DummyLoginUrl, values.Encode())
+				req := httptest.NewRequest(http.MethodGet, u, http.NoBody)
+
+				mockTokenManager.EXPECT().GenerateDummyAccessToken("moderator").Return("access-token", tokenmanager.Claims{}, nil)
+
+				return req
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := c.prepareFunc()
+			recorder := httptest.NewRecorder()
+
+			router.ServeHTTP(recorder, req)
+
+			assert.Equal(t, c.statusCode, recorder.Code)
+
+			tokens := userhandlermodel.Tokens{}
+			assert.NoError(t, json.NewDecoder(recorder.Body).Decode(&tokens))
+			assert.Equal(t, "access-token", tokens.AccessToken)
+		})
+	}
+}
+
+func TestDummyLoginErr(t *testing.T) {
+	ctrl, _, _, mockTokenManager, router := testHandler(t)
+	defer ctrl.Finish()
+
+	cases := []struct {
+		name           string
+		statusCode     int
+		expectedErrMsg string
+		prepareFunc    func() *http.Request
+	}{
+		{
+			name:           "INVALID USER TYPE",
+			statusCode:     http.StatusBadRequest,
+			expectedErrMsg: userhandler.ErrInvalidUserType.Error(),
+			prepareFunc: func() *http.Request {
+				values := make(url.Values)
+				values.Set(userhandler.UserTypeQueryParam, "admin")
+
+				u := fmt.Sprintf("%s%s?%s", userhandler.APIUrl, userhandler.DummyLoginUrl, values.Encode())
+				return httptest.NewRequest(http.MethodGet, u, http.NoBody)
+			},
+		},
+		{
+			name:           "NO USER TYPE",
+			statusCode:     http.StatusBadRequest,
+			expectedErrMsg: userhandler.ErrInvalidUserType.Error(),
+			prepareFunc: func() *http.Request {
+				return httptest.NewRequest(http.MethodGet, userhandler.APIUrl+userhandler.DummyLoginUrl, http.NoBody)
+			},
+		},
+		{
+			name:           "ERR INTERNAL",
+			statusCode:     http.StatusInternalServerError,
+			expectedErrMsg: http.StatusText(http.StatusInternalServerError),
+			prepareFunc: func() *http.Request {
+				values := make(url.Values)
+				values.Set(userhandler.UserTypeQueryParam, "client")
+
+				u := fmt.Sprintf("%s%s?%s", userhandler.APIUrl, userhandler.DummyLoginUrl, values.Encode())
+				req := httptest.NewRequest(http.MethodGet, u, http.NoBody)
+
+				mockTokenManager.EXPECT().GenerateDummyAccessToken(gomock.Any()).Return("", tokenmanager.Claims{}, errors.New("sign error"))
+
+				return req
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := c.prepareFunc()
+			recorder := httptest.NewRecorder()
+
+			router.ServeHTTP(recorder, req)
+
+			assert.Equal(t, c.statusCode, recorder.Code)
+			assert.Contains(t, recorder.Body.String(), c.expectedErrMsg)
+		})
+	}
+}
+
+func TestDummyLoginDisabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	router := mux.NewRouter()
+	logger := slog.New(slog.NewTextHandler(&stubwriter.Writer{}, nil))
+
+	err := Register(router, userservice.NewMockService(ctrl), sessionservice.NewMockService(ctrl), tokenmanager.NewMockManager(ctrl), memory.New(0), false, logger)
+	assert.NoError(t, err)
+
+	values := make(url.Values)
+	values.Set(userhandler.UserTypeQueryParam, "moderator")
+
+	u := fmt.Sprintf("%s%s?%s", userhandler.APIUrl, userhandler.DummyLoginUrl, values.Encode())
+	req := httptest.NewRequest(http.MethodGet, u, http.NoBody)
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
+
+func TestSessions(t *testing.T) {
+	ctrl, _, mockSessionService, mockTokenManager, router := testHandler(t)
+	defer ctrl.Finish()
+
+	req := httptest.NewRequest(http.MethodGet, userhandler.APIUrl+userhandler.SessionsUrl, http.NoBody)
+	req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+	claims := tokenmanager.Claims{UserID: 1, Role: "client", SessionID: 2}
+
+	mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+	mockSessionService.EXPECT().Sessions(gomock.Any(), int64(1)).Return([]model.Session{
+		{SessionID: 1, UserID: 1, UserAgent: "laptop"},
+		{SessionID: 2, UserID: 1, UserAgent: "phone"},
+	}, nil)
+
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	var sessions []userhandlermodel.Session
+	assert.NoError(t, json.NewDecoder(recorder.Body).Decode(&sessions))
+	assert.Len(t, sessions, 2)
+	//THE SESSION OF THE ACCESS TOKEN IS MARKED
+	assert.False(t, sessions[0].Current)
+	assert.True(t, sessions[1].Current)
+}
+
+func TestSessionsErr(t *testing.T) {
+	ctrl, _, mockSessionService, mockTokenManager, router := testHandler(t)
+	defer ctrl.Finish()
+
+	cases := []struct {
+		name        string
+		statusCode  int
+		prepareFunc func() *http.Request
+	}{
+		{
+			name:       "NO ACCESS TOKEN",
+			statusCode: http.StatusUnauthorized,
+			prepareFunc: func() *http.Request {
+				return httptest.NewRequest(http.MethodGet, userhandler.APIUrl+userhandler.SessionsUrl, http.NoBody)
+			},
+		},
+		{
+			name:       "EXPIRED ACCESS TOKEN",
+			statusCode: http.StatusUnauthorized,
+			prepareFunc: func() *http.Request {
+				req := httptest.NewRequest(http.MethodGet, userhandler.APIUrl+userhandler.SessionsUrl, http.NoBody)
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(tokenmanager.Claims{UserID: 1, Role: "client"}, tokenmanager.ErrTokenExpired)
+
+				return req
+			},
+		},
+		{
+			name:       "ERR INTERNAL",
+			statusCode: http.StatusInternalServerError,
+			prepareFunc: func() *http.Request {
+				req := httptest.NewRequest(http.MethodGet, userhandler.APIUrl+userhandler.SessionsUrl, http.NoBody)
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(tokenmanager.Claims{UserID: 1, Role: "client"}, nil)
+				mockSessionService.EXPECT().Sessions(gomock.Any(), int64(1)).Return(nil, sessionservice.ErrInternal)
+
+				return req
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := c.prepareFunc()
+			recorder := httptest.NewRecorder()
+
+			router.ServeHTTP(recorder, req)
+
+			assert.Equal(t, c.statusCode, recorder.Code)
+		})
+	}
+}
+
+func TestRevokeSession(t *testing.T) {
+	ctrl, _, mockSessionService, mockTokenManager, router := testHandler(t)
+	defer ctrl.Finish()
+
+	cases := []struct {
+		name           string
+		statusCode     int
+		expectedErrMsg string
+		prepareFunc    func() *http.Request
+	}{
+		{
+			name:       "OK",
+			statusCode: http.StatusOK,
+			prepareFunc: func() *http.Request {
+				req := httptest.NewRequest(http.MethodDelete, userhandler.APIUrl+userhandler.SessionsUrl+"/2", http.NoBody)
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(tokenmanager.Claims{UserID: 1, Role: "client", SessionID: 1}, nil)
+				mockSessionService.EXPECT().Revoke(gomock.Any(), int64(1), int64(2)).Return(nil)
+
+				return req
+			},
+		},
+		{
+			//A SESSION OF ANOTHER USER LOOKS THE SAME AS A MISSING ONE
+			name:           "SESSION NOT FOUND",
+			statusCode:     http.StatusNotFound,
+			expectedErrMsg: userhandler.ErrSessionNotFound.Error(),
+			prepareFunc: func() *http.Request {
+				req := httptest.NewRequest(http.MethodDelete, userhandler.APIUrl+userhandler.SessionsUrl+"/2", http.NoBody)
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(tokenmanager.Claims{UserID: 1, Role: "client", SessionID: 1}, nil)
+				mockSessionService.EXPECT().Revoke(gomock.Any(), int64(1), int64(2)).Return(sessionservice.ErrNoSession)
+
+				return req
+			},
+		},
+		{
+			name:           "INVALID SESSION ID",
+			statusCode:     http.StatusBadRequest,
+			expectedErrMsg: userhandler.ErrInvalidSessionID.Error(),
+			prepareFunc: func() *http.Request {
+				req := httptest.NewRequest(http.MethodDelete, userhandler.APIUrl+userhandler.SessionsUrl+"/99999999999999999999", http.NoBody)
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(tokenmanager.Claims{UserID: 1, Role: "client", SessionID: 1}, nil)
+
+				return req
+			},
+		},
+		{
+			name:           "ERR INTERNAL",
+			statusCode:     http.StatusInternalServerError,
+			expectedErrMsg: http.StatusText(http.StatusInternalServerError),
+			prepareFunc: func() *http.Request {
+				req := httptest.NewRequest(http.MethodDelete, userhandler.APIUrl+userhandler.SessionsUrl+"/2", http.NoBody)
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(tokenmanager.Claims{UserID: 1, Role: "client", SessionID: 1}, nil)
+				mockSessionService.EXPECT().Revoke(gomock.Any(), gomock.Any(), gomock.Any()).Return(sessionservice.ErrInternal)
+
+				return req
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := c.prepareFunc()
+			recorder := httptest.NewRecorder()
+
+			router.ServeHTTP(recorder, req)
+
+			assert.Equal(t, c.statusCode, recorder.Code)
+			assert.Contains(t, recorder.Body.String(), c.expectedErrMsg)
+		})
+	}
+}
+
+func TestRevokeAllSessions(t *testing.T) {
+	ctrl, _, mockSessionService, mockTokenManager, router := testHandler(t)
+	defer ctrl.Finish()
+
+	cases := []struct {
+		name        string
+		statusCode  int
+		prepareFunc func()
+	}{
+		{
+			name:       "OK",
+			statusCode: http.StatusOK,
+			prepareFunc: func() {
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(tokenmanager.Claims{UserID: 1, Role: "client", SessionID: 1}, nil)
+				mockSessionService.EXPECT().RevokeAll(gomock.Any(), int64(1)).Return(nil)
+			},
+		},
+		{
+			name:       "ERR INTERNAL",
+			statusCode: http.StatusInternalServerError,
+			prepareFunc: func() {
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(tokenmanager.Claims{UserID: 1, Role: "client", SessionID: 1}, nil)
+				mockSessionService.EXPECT().RevokeAll(gomock.Any(), int64(1)).Return(sessionservice.ErrInternal)
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.prepareFunc()
+
+			req := httptest.NewRequest(http.MethodDelete, userhandler.APIUrl+userhandler.SessionsUrl, http.NoBody)
+			req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+			recorder := httptest.NewRecorder()
+
+			router.ServeHTTP(recorder, req)
+
+			assert.Equal(t, c.statusCode, recorder.Code)
+		})
+	}
+}
+
+func TestLogout(t *testing.T) {
+	ctrl, _, mockSessionService, mockTokenManager, router := testHandler(t)
+	defer ctrl.Finish()
+
+	claims := tokenmanager.Claims{UserID: 1, Role: "client", SessionID: 1, RegisteredClaims: jwt.RegisteredClaims{ID: "token-id"}}
+
+	cases := []struct {
+		name        string
+		statusCode  int
+		prepareFunc func()
+	}{
+		{
+			name:       "OK",
+			statusCode: http.StatusOK,
+			prepareFunc: func() {
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockSessionService.EXPECT().LogOut(gomock.Any(), claims).Return(nil)
+			},
+		},
+		{
+			name:       "ERR INTERNAL",
+			statusCode: http.StatusInternalServerError,
+			prepareFunc: func() {
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(claims, nil)
+				mockSessionService.EXPECT().LogOut(gomock.Any(), claims).Return(sessionservice.ErrInternal)
+			},
+		},
+		{
+			name:       "ERR INVALID TOKEN",
+			statusCode: http.StatusUnauthorized,
+			prepareFunc: func() {
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(tokenmanager.Claims{}, tokenmanager.ErrInvalidToken)
+			},
+		},
+		{
+			//THE SESSION SERVICE IS NOT REACHED WITH A DUMMY TOKEN
+			name:       "ERR DUMMY TOKEN",
+			statusCode: http.StatusForbidden,
+			prepareFunc: func() {
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(tokenmanager.Claims{UserID: -1, Role: "client", Dummy: true, RegisteredClaims: jwt.RegisteredClaims{ID: "token-id"}}, nil)
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.prepareFunc()
+
+			req := httptest.NewRequest(http.MethodPost, userhandler.APIUrl+userhandler.LogoutUrl, http.NoBody)
+			req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+			recorder := httptest.NewRecorder()
+
+			router.ServeHTTP(recorder, req)
+
+			assert.Equal(t, c.statusCode, recorder.Code)
+		})
+	}
+}
+
+func TestLogoutRevoked(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokenManager := tokenmanager.NewMockManager(ctrl)
+	mockTokenManager.EXPECT().Parse(gomock.Any()).Return(tokenmanager.Claims{UserID: 1, Role: "client", SessionID: 1, RegisteredClaims: jwt.RegisteredClaims{ID: "token-id"}}, nil)
+
+	revoked := memory.New(0)
+	revoked.Revoke("token-id", time.Now().Add(time.Minute))
+
+	router := mux.NewRouter()
+	logger := slog.New(slog.NewTextHandler(&stubwriter.Writer{}, nil))
+
+	//THE SESSION SERVICE IS NOT REACHED WITH A REVOKED TOKEN
+	err := Register(router, userservice.NewMockService(ctrl), sessionservice.NewMockService(ctrl), mockTokenManager, revoked, false, logger)
+	assert.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, userhandler.APIUrl+userhandler.LogoutUrl, http.NoBody)
+	req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), middleware.ErrTokenRevoked.Error())
+}
+
+func TestChangeRole(t *testing.T) {
+	ctrl, mockUserService, _, mockTokenManager, router := testHandler(t)
+	defer ctrl.Finish()
+
+	moderatorClaims := tokenmanager.Claims{UserID: 1, Role: "moderator", SessionID: 1}
+	roleUrl := userhandler.APIUrl + "/user/2/role"
+
+	cases := []struct {
+		name           string
+		statusCode     int
+		expectedErrMsg string
+		prepareFunc    func() *http.Request
+	}{
+		{
+			name:       "OK",
+			statusCode: http.StatusOK,
+			prepareFunc: func() *http.Request {
+				req := httptest.NewRequest(http.MethodPut, roleUrl, bytes.NewBufferString(`{"role":"moderator"}`))
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(moderatorClaims, nil)
+				mockUserService.EXPECT().ChangeRole(gomock.Any(), int64(2), "moderator").Return(model.User{ID: 2, Role: "moderator"}, nil)
+
+				return req
+			},
+		},
+		{
+			name:           "USER NOT FOUND",
+			statusCode:     http.StatusNotFound,
+			expectedErrMsg: userhandler.ErrUserNotFound.Error(),
+			prepareFunc: func() *http.Request {
+				req := httptest.NewRequest(http.MethodPut, roleUrl, bytes.NewBufferString(`{"role":"client"}`))
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(moderatorClaims, nil)
+				mockUserService.EXPECT().ChangeRole(gomock.Any(), int64(2), "client").Return(model.User{}, userservice.ErrUserNotFound)
+
+				return req
+			},
+		},
+		{
+			name:       "INVALID ROLE",
+			statusCode: http.StatusBadRequest,
+			prepareFunc: func() *http.Request {
+				req := httptest.NewRequest(http.MethodPut, roleUrl, bytes.NewBufferString(`{"role":"admin"}`))
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(moderatorClaims, nil)
+
+				return req
+			},
+		},
+		{
+			name:           "INVALID USER ID",
+			statusCode:     http.StatusBadRequest,
+			expectedErrMsg: userhandler.ErrInvalidUserID.Error(),
+			prepareFunc: func() *http.Request {
+				req := httptest.NewRequest(http.MethodPut, userhandler.APIUrl+"/user/99999999999999999999/role", bytes.NewBufferString(`{"role":"client"}`))
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(moderatorClaims, nil)
+
+				return req
+			},
+		},
+		{
+			name:       "FORBIDDEN CLIENT",
+			statusCode: http.StatusForbidden,
+			prepareFunc: func() *http.Request {
+				req := httptest.NewRequest(http.MethodPut, roleUrl, bytes.NewBufferString(`{"role":"moderator"}`))
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(tokenmanager.Claims{UserID: 2, Role: "client", SessionID: 2}, nil)
+
+				return req
+			},
+		},
+		{
+			name:           "FORBIDDEN DUMMY MODERATOR",
+			statusCode:     http.StatusForbidden,
+			expectedErrMsg: middleware.ErrDummyToken.Error(),
+			prepareFunc: func() *http.Request {
+				req := httptest.NewRequest(http.MethodPut, roleUrl, bytes.NewBufferString(`{"role":"moderator"}`))
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(tokenmanager.Claims{UserID: -1, Role: "moderator", Dummy: true}, nil)
+
+				return req
+			},
+		},
+		{
+			name:           "ERR INTERNAL",
+			statusCode:     http.StatusInternalServerError,
+			expectedErrMsg: http.StatusText(http.StatusInternalServerError),
+			prepareFunc: func() *http.Request {
+				req := httptest.NewRequest(http.MethodPut, roleUrl, bytes.NewBufferString(`{"role":"client"}`))
+				req.Header.Set(middleware.AuthorizationHeader, "Bearer access-token")
+
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(moderatorClaims, nil)
+				mockUserService.EXPECT().ChangeRole(gomock.Any(), gomock.Any(), gomock.Any()).Return(model.User{}, userservice.ErrInternal)
+
+				return req
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := c.prepareFunc()
+			recorder := httptest.NewRecorder()
+
+			router.ServeHTTP(recorder, req)
+
+			assert.Equal(t, c.statusCode, recorder.Code)
+			assert.Contains(t, recorder.Body.String(), c.expectedErrMsg)
+		})
+	}
+}
+
 func testHandler(t *testing.T) (ctrl *gomock.Controller, mockUserService *userservice.MockService, mockSessionService *sessionservice.MockService, mockTokenManager *tokenmanager.MockManager, router *mux.Router) {
 	ctrl = gomock.NewController(t)
 
@@ -471,7 +1121,7 @@ func testHandler(t *testing.T) (ctrl *gomock.Controller, mockUserService *userse
 
 	logger := slog.New(slog.NewTextHandler(&stubwriter.Writer{}, nil))
 
-	err := Register(router, mockUserService, mockSessionService, mockTokenManager, logger)
+	err := Register(router, mockUserService, mockSessionService, mockTokenManager, memory.New(0), true, logger)
 	assert.NoError(t, err)
 
 	return ctrl, mockUserService, mockSessionService, mockTokenManager, router
@@ -1,12 +1,24 @@
 package userhandler
 
+import "fmt"
+
 var (
 	APIUrl          = "/api/v1"
 	RegistrationUrl = "/register"
 	LoginUrl        = "/login"
 	UpdateTokensUrl = "/update-tokens"
+	DummyLoginUrl   = "/dummyLogin"
+	LogoutUrl       = "/logout"
+
+	SessionID   = "session_id"
+	SessionsUrl = "/sessions"
+	SessionUrl  = fmt.Sprintf("%s/{%s:[0-9]+}", SessionsUrl, SessionID)
+
+	UserID      = "user_id"
+	UserRoleUrl = fmt.Sprintf("/user/{%s:[0-9]+}/role", UserID)
 )
 
 var (
 	RefreshTokenQueryParam = "refresh_token"
+	UserTypeQueryParam     = "user_type"
 )
@@ -1,16 +1,13 @@
 package middleware
 
 import (
+	"avito/pkg/revocation"
 	tokenmanager "avito/pkg/token_manager"
-	tokenmanagerimpl "avito/pkg/token_manager/implementation"
-	"context"
 	"errors"
 	"net/http"
-	"reflect"
-	"time"
 )
 
-func AuthOnly(tm tokenmanager.Manager) func(http.Handler) http.Handler {
+func AuthOnly(tm tokenmanager.Manager, revoked revocation.List) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			claims, err := parseAuthHeader(tm, r.Header.Get(AuthorizationHeader))
@@ -28,23 +25,12 @@ func AuthOnly(tm tokenmanager.Manager) func(http.Handler) http.Handler {
 				}
 			}
 
-			expAt, err := claims.GetExpirationTime()
-			if err != nil {
-				w.WriteHeader(http.StatusUnauthorized)
-				return
-			}
-
-			if time.Now().After(expAt.Time) {
-				w.WriteHeader(http.StatusUnauthorized)
+			if revoked.Revoked(claims.ID) {
+				http.Error(w, ErrTokenRevoked.Error(), http.StatusUnauthorized)
 				return
 			}
 
-			userID := uint32(reflect.ValueOf(claims[tokenmanagerimpl.UserIDClaimsTag]).Float())
-			ctx := context.WithValue(r.Context(), UserIDCtxKey, userID)
-
-			ctx = context.WithValue(ctx, RoleCtxKey, claims[tokenmanagerimpl.RoleClaimsTag])
-
-			r = r.WithContext(ctx)
+			r = r.WithContext(WithClaims(r.Context(), claims))
 
 			next.ServeHTTP(w, r)
 		})
@@ -0,0 +1,124 @@
+package middleware
+
+import (
+	"avito/pkg/revocation/memory"
+	tokenmanager "avito/pkg/token_manager"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAuthOnly(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokenManager := tokenmanager.NewMockManager(ctrl)
+	claims := tokenmanager.Claims{UserID: 1, Role: "client", RegisteredClaims: jwt.RegisteredClaims{ID: "token-id"}}
+	mockTokenManager.EXPECT().Parse("access-token").Return(claims, nil)
+
+	var got tokenmanager.Claims
+	h := AuthOnly(mockTokenManager, memory.New(0))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var ok bool
+		got, ok = ClaimsFromContext(r.Context())
+		assert.True(t, ok)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	req.Header.Set(AuthorizationHeader, "Bearer access-token")
+	recorder := httptest.NewRecorder()
+
+	h.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, claims, got)
+}
+
+func TestAuthOnlyErr(t *testing.T) {
+	cases := []struct {
+		name        string
+		header      string
+		prepareFunc func(mockTokenManager *tokenmanager.MockManager)
+	}{
+		{
+			name:        "NO HEADER",
+			header:      "",
+			prepareFunc: func(mockTokenManager *tokenmanager.MockManager) {},
+		},
+		{
+			name:        "NO TOKEN",
+			header:      "Bearer",
+			prepareFunc: func(mockTokenManager *tokenmanager.MockManager) {},
+		},
+		{
+			name:        "NOT BEARER",
+			header:      "Basic access-token",
+			prepareFunc: func(mockTokenManager *tokenmanager.MockManager) {},
+		},
+		{
+			name:   "INVALID TOKEN",
+			header: "Bearer access-token",
+			prepareFunc: func(mockTokenManager *tokenmanager.MockManager) {
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(tokenmanager.Claims{}, tokenmanager.ErrInvalidToken)
+			},
+		},
+		{
+			name:   "EXPIRED TOKEN",
+			header: "Bearer access-token",
+			prepareFunc: func(mockTokenManager *tokenmanager.MockManager) {
+				mockTokenManager.EXPECT().Parse(gomock.Any()).Return(tokenmanager.Claims{UserID: 1, Role: "client"}, tokenmanager.ErrTokenExpired)
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockTokenManager := tokenmanager.NewMockManager(ctrl)
+			c.prepareFunc(mockTokenManager)
+
+			h := AuthOnly(mockTokenManager, memory.New(0))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				t.Fatal("next handler must not be called")
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+			req.Header.Set(AuthorizationHeader, c.header)
+			recorder := httptest.NewRecorder()
+
+			h.ServeHTTP(recorder, req)
+
+			assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+		})
+	}
+}
+
+func TestAuthOnlyRevoked(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTokenManager := tokenmanager.NewMockManager(ctrl)
+	claims := tokenmanager.Claims{UserID: 1, Role: "moderator", RegisteredClaims: jwt.RegisteredClaims{ID: "token-id"}}
+	mockTokenManager.EXPECT().Parse("access-token").Return(claims, nil).Times(2)
+
+	revoked := memory.New(0)
+	revoked.Revoke("token-id", time.Now().Add(time.Minute))
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("next handler must not be called")
+	})
+
+	for _, h := range []http.Handler{AuthOnly(mockTokenManager, revoked)(next), CheckRole(mockTokenManager, revoked, "moderator")(next)} {
+		req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+		req.Header.Set(AuthorizationHeader, "Bearer access-token")
+		recorder := httptest.NewRecorder()
+
+		h.ServeHTTP(recorder, req)
+
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+	}
+}
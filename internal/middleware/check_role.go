@@ -1,15 +1,14 @@
 package middleware
 
 import (
+	"avito/pkg/revocation"
 	tokenmanager "avito/pkg/token_manager"
-	tokenmanagerimpl "avito/pkg/token_manager/implementation"
 	"errors"
 	"net/http"
-	"reflect"
 	"slices"
 )
 
-func CheckRole(tm tokenmanager.Manager, allowedRoles ...string) func(http.Handler) http.Handler {
+func CheckRole(tm tokenmanager.Manager, revoked revocation.List, allowedRoles ...string) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			claims, err := parseAuthHeader(tm, r.Header.Get(AuthorizationHeader))
@@ -24,7 +23,14 @@ func CheckRole(tm tokenmanager.Manager, allowedRoles ...string) func(http.Handle
 				}
 			}
 
-			if slices.Contains(allowedRoles, reflect.ValueOf(claims[tokenmanagerimpl.RoleClaimsTag]).String()) {
+			if revoked.Revoked(claims.ID) {
+				http.Error(w, ErrTokenRevoked.Error(), http.StatusUnauthorized)
+				return
+			}
+
+			if slices.Contains(allowedRoles, claims.Role) {
+				r = r.WithContext(WithClaims(r.Context(), claims))
+
 				next.ServeHTTP(w, r)
 				return
 			}
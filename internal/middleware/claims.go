@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	tokenmanager "avito/pkg/token_manager"
+	"context"
+)
+
+type claimsCtxKey struct{}
+
+// WithClaims puts the claims of the access token into the context.
+func WithClaims(ctx context.Context, claims tokenmanager.Claims) context.Context {
+	return context.WithValue(ctx, claimsCtxKey{}, claims)
+}
+
+// ClaimsFromContext returns the claims put by AuthOnly, CheckRole or ParseAuthToken.
+func ClaimsFromContext(ctx context.Context) (tokenmanager.Claims, bool) {
+	claims, ok := ctx.Value(claimsCtxKey{}).(tokenmanager.Claims)
+	return claims, ok
+}
+
+func UserIDFromContext(ctx context.Context) (int64, bool) {
+	claims, ok := ClaimsFromContext(ctx)
+	return claims.UserID, ok
+}
+
+func RoleFromContext(ctx context.Context) (string, bool) {
+	claims, ok := ClaimsFromContext(ctx)
+	return claims.Role, ok
+}
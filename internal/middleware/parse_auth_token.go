@@ -2,12 +2,8 @@ package middleware
 
 import (
 	tokenmanager "avito/pkg/token_manager"
-	tokenmanagerimpl "avito/pkg/token_manager/implementation"
-	"context"
 	"errors"
-	"github.com/golang-jwt/jwt/v5"
 	"net/http"
-	"reflect"
 	"strings"
 )
 
@@ -16,20 +12,18 @@ const (
 	BearerToken         = "Bearer"
 )
 
-const (
-	RoleCtxKey   = "role"
-	UserIDCtxKey = "user_id"
-)
-
 var (
 	ErrInvalidAuthHeader = errors.New("invalid auth header")
 	ErrInvalidToken      = errors.New("invalid token. login again or update tokens")
+	ErrTokenRevoked      = errors.New("token revoked. login again")
 )
 
-func parseAuthHeader(tm tokenmanager.Manager, authorizationToken string) (jwt.MapClaims, error) {
+// parseAuthHeader returns the claims of an expired token along with ErrInvalidToken,
+// it is up to the caller to let them through.
+func parseAuthHeader(tm tokenmanager.Manager, authorizationToken string) (tokenmanager.Claims, error) {
 	authorizationTokenParts := strings.Split(authorizationToken, " ")
-	if len(authorizationTokenParts) != 2 && authorizationTokenParts[0] != BearerToken {
-		return map[string]interface{}{}, ErrInvalidAuthHeader
+	if len(authorizationTokenParts) != 2 || authorizationTokenParts[0] != BearerToken {
+		return tokenmanager.Claims{}, ErrInvalidAuthHeader
 	}
 
 	accessToken := authorizationTokenParts[1]
@@ -37,10 +31,10 @@ func parseAuthHeader(tm tokenmanager.Manager, authorizationToken string) (jwt.Ma
 	claims, err := tm.Parse(accessToken)
 	if err != nil {
 		switch {
-		case errors.Is(err, jwt.ErrTokenExpired):
-			return map[string]interface{}{}, ErrInvalidToken
+		case errors.Is(err, tokenmanager.ErrTokenExpired):
+			return claims, ErrInvalidToken
 		default:
-			return map[string]interface{}{}, ErrInvalidAuthHeader
+			return tokenmanager.Claims{}, ErrInvalidAuthHeader
 		}
 	}
 
@@ -56,12 +50,7 @@ func ParseAuthToken(tm tokenmanager.Manager) func(http.Handler) http.Handler {
 				return
 			}
 
-			userID := uint32(reflect.ValueOf(claims[tokenmanagerimpl.UserIDClaimsTag]).Float())
-			ctx := context.WithValue(r.Context(), UserIDCtxKey, userID)
-
-			ctx = context.WithValue(ctx, RoleCtxKey, claims[tokenmanagerimpl.RoleClaimsTag])
-
-			r = r.WithContext(ctx)
+			r = r.WithContext(WithClaims(r.Context(), claims))
 
 			next.ServeHTTP(w, r)
 		})
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+)
+
+var ErrDummyToken = errors.New("dummy token is not bound to a user. register or log in")
+
+// RegisteredOnly goes after AuthOnly or CheckRole and lets through only the tokens
+// of registered users, the dummy ones are refused.
+func RegisteredOnly() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, ok := ClaimsFromContext(r.Context())
+			if !ok {
+				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+				return
+			}
+
+			if claims.Dummy {
+				http.Error(w, ErrDummyToken.Error(), http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -1,10 +1,15 @@
 package model
 
 type Apartment struct {
-	ID               uint32
+	ID               int64
 	ApartmentNumber  int
-	HouseID          uint32
+	HouseID          int64
 	Price            uint32
 	NumberOfRooms    uint32
 	ModerationStatus string
+	OwnerID          int64
+	ModeratorID      int64
+	DeclineReason    string
+	ModeratorComment string
+	RemovalReason    string
 }
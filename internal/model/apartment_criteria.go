@@ -0,0 +1,19 @@
+package model
+
+const (
+	SortByPrice = "price"
+	SortByRooms = "rooms"
+)
+
+// ApartmentCriteria filters and orders the apartments of a house.
+// Zero values mean no constraint, an empty SortBy keeps the insertion order.
+type ApartmentCriteria struct {
+	MinPrice         uint32
+	MaxPrice         uint32
+	MinRooms         uint32
+	MaxRooms         uint32
+	ModerationStatus string
+
+	SortBy     string
+	Descending bool
+}
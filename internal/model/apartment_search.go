@@ -0,0 +1,24 @@
+package model
+
+// ApartmentSearchCriteria narrows the cross-house search over approved apartments.
+// Zero values mean no constraint.
+type ApartmentSearchCriteria struct {
+	MinPrice  uint32
+	MaxPrice  uint32
+	MinRooms  uint32
+	MaxRooms  uint32
+	MinYear   int
+	MaxYear   int
+	Developer string
+
+	Offset int
+	Limit  int
+
+	//COUNTING EVERY MATCH IS EXPENSIVE WITH LOOSE FILTERS, SO THE TOTAL IS OPT-IN
+	WithTotal bool
+}
+
+type ApartmentWithHouse struct {
+	Apartment Apartment
+	House     House
+}
@@ -3,7 +3,7 @@ package model
 import "time"
 
 type House struct {
-	HouseId              uint32
+	HouseId              int64
 	Address              string
 	Year                 int
 	Developer            string
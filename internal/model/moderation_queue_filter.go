@@ -0,0 +1,9 @@
+package model
+
+// ModerationQueueFilter narrows the apartments handed out by the moderation queue.
+// Zero values mean no constraint.
+type ModerationQueueFilter struct {
+	HouseID  int64
+	MinPrice uint32
+	MaxPrice uint32
+}
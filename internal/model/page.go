@@ -0,0 +1,18 @@
+package model
+
+// Cursor points at the last item of a page. Lists ordered by a column other than the id
+// also carry the sort value, so the next page resumes right after the (value, id) pair.
+type Cursor struct {
+	SortBy     string
+	Descending bool
+	SortValue  int64
+	ID         int64
+}
+
+// Page selects a slice of a list either by offset or, when After is set, by keyset
+// right after the cursor. Keyset pages stay fast on deep pages and don't shift on inserts.
+type Page struct {
+	Offset int
+	Limit  int
+	After  *Cursor
+}
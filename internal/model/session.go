@@ -3,8 +3,21 @@ package model
 import "time"
 
 type Session struct {
-	SessionID        uint32
-	UserID           uint32
+	SessionID        int64
+	UserID           int64
 	HashRefreshToken string
 	ExpiresAt        time.Time
+	UserAgent        string
+	IP               string
+	CreatedAt        time.Time
+	LastUsedAt       time.Time
+
+	AccessTokenID        string
+	AccessTokenExpiresAt time.Time
+}
+
+// Device describes where a session was started from.
+type Device struct {
+	UserAgent string
+	IP        string
 }
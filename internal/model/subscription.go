@@ -0,0 +1,10 @@
+package model
+
+import "time"
+
+type Subscription struct {
+	SubscriptionID int64
+	HouseID        int64
+	Email          string
+	CreatedAt      time.Time
+}
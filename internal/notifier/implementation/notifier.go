@@ -0,0 +1,165 @@
+package notifierimpl
+
+import (
+	"avito/internal/model"
+	"avito/internal/notifier"
+	subscriptionservice "avito/internal/service/subscription"
+	"avito/pkg/logger"
+	"avito/pkg/sender"
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+const (
+	sendAttempts = 3
+	sendTimeout  = 5 * time.Second
+	retryDelay   = time.Second
+)
+
+type delivery struct {
+	recipient string
+	message   string
+}
+
+// emailNotifier resolves subscribers of a house in a single dispatcher goroutine and
+// hands the emails over to a pool of workers, so a slow sender never blocks the caller.
+type emailNotifier struct {
+	subscriptionService subscriptionservice.Service
+	sender              sender.Sender
+
+	apartments chan model.Apartment
+	deliveries chan delivery
+	workers    int
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	logger *slog.Logger
+}
+
+func (n *emailNotifier) Notify(apartment model.Apartment) {
+	select {
+	case n.apartments <- apartment:
+	default:
+		n.logger.Error("Notification queue is full, notification dropped", slog.Any("apartment_id", apartment.ID))
+	}
+}
+
+func (n *emailNotifier) Start() {
+	n.wg.Add(1)
+	go n.dispatch()
+
+	for i := 0; i < n.workers; i++ {
+		n.wg.Add(1)
+		go n.work()
+	}
+}
+
+func (n *emailNotifier) Stop(ctx context.Context) error {
+	n.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		n.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (n *emailNotifier) dispatch() {
+	defer n.wg.Done()
+
+	for {
+		select {
+		case <-n.ctx.Done():
+			return
+		case apartment := <-n.apartments:
+			ctx := context.WithValue(n.ctx, logger.LogIDContextKey, uuid.New().ID())
+
+			subscriptions, err := n.subscriptionService.Subscriptions(ctx, apartment.HouseID)
+			if err != nil {
+				n.logger.Error("Failed to get house subscriptions", slog.Any("house_id", apartment.HouseID), slog.String("error", err.Error()))
+				continue
+			}
+
+			message := newApartmentMessage(apartment)
+			for _, subscription := range subscriptions {
+				select {
+				case <-n.ctx.Done():
+					return
+				case n.deliveries <- delivery{recipient: subscription.Email, message: message}:
+				}
+			}
+		}
+	}
+}
+
+func (n *emailNotifier) work() {
+	defer n.wg.Done()
+
+	for {
+		select {
+		case <-n.ctx.Done():
+			return
+		case d := <-n.deliveries:
+			n.send(d)
+		}
+	}
+}
+
+func (n *emailNotifier) send(d delivery) {
+	for attempt := 1; attempt <= sendAttempts; attempt++ {
+		ctx, cancel := context.WithTimeout(n.ctx, sendTimeout)
+		err := n.sender.SendEmail(ctx, d.recipient, d.message)
+		cancel()
+		if err == nil {
+			return
+		}
+
+		n.logger.Warn("Failed to send email", slog.String("recipient", d.recipient), slog.Int("attempt", attempt), slog.String("error", err.Error()))
+
+		select {
+		case <-n.ctx.Done():
+			return
+		case <-time.After(retryDelay * time.Duration(attempt)):
+		}
+	}
+
+	n.logger.Error("Email was not delivered", slog.String("recipient", d.recipient))
+}
+
+func newApartmentMessage(apartment model.Apartment) string {
+	return fmt.Sprintf("New apartment №%d in house %d: %d rooms, price %d",
+		apartment.ApartmentNumber,
+		apartment.HouseID,
+		apartment.NumberOfRooms,
+		apartment.Price)
+}
+
+func New(subscriptionService subscriptionservice.Service, sender sender.Sender, workers int, queueSize int, logger *slog.Logger) notifier.Notifier {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	n := &emailNotifier{
+		subscriptionService: subscriptionService,
+		sender:              sender,
+		apartments:          make(chan model.Apartment, queueSize),
+		deliveries:          make(chan delivery, workers),
+		workers:             workers,
+		ctx:                 ctx,
+		cancel:              cancel,
+		logger:              logger,
+	}
+
+	return n
+}
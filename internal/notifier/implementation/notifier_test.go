@@ -0,0 +1,100 @@
+package notifierimpl
+
+import (
+	"avito/internal/model"
+	subscriptionservice "avito/internal/service/subscription"
+	"avito/pkg/sender"
+	stubwriter "avito/pkg/stub_writer"
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNotify(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubscriptionService := subscriptionservice.NewMockService(ctrl)
+	mockSender := sender.NewMockSender(ctrl)
+	logger := slog.New(slog.NewTextHandler(&stubwriter.Writer{}, nil))
+
+	subscriptions := []model.Subscription{
+		{HouseID: 1, Email: "first@gmail.com"},
+		{HouseID: 1, Email: "second@gmail.com"},
+	}
+
+	wg := sync.WaitGroup{}
+	wg.Add(len(subscriptions))
+
+	release := make(chan struct{})
+
+	mockSubscriptionService.EXPECT().Subscriptions(gomock.Any(), int64(1)).Return(subscriptions, nil)
+	mockSender.EXPECT().SendEmail(gomock.Any(), "first@gmail.com", gomock.Any()).DoAndReturn(func(context.Context, string, string) error {
+		<-release
+		wg.Done()
+		return nil
+	})
+	mockSender.EXPECT().SendEmail(gomock.Any(), "second@gmail.com", gomock.Any()).DoAndReturn(func(context.Context, string, string) error {
+		<-release
+		wg.Done()
+		return nil
+	})
+
+	n := New(mockSubscriptionService, mockSender, 2, 10, logger)
+	n.Start()
+
+	//NOTIFY MUST RETURN WHILE THE SENDER IS STILL BUSY
+	notified := make(chan struct{})
+	go func() {
+		n.Notify(model.Apartment{ID: 1, HouseID: 1})
+		close(notified)
+	}()
+
+	select {
+	case <-notified:
+	case <-time.After(time.Second):
+		t.Fatal("Notify blocked on email delivery")
+	}
+
+	close(release)
+	wg.Wait()
+
+	assert.NoError(t, n.Stop(context.Background()))
+}
+
+func TestNotifyRetry(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubscriptionService := subscriptionservice.NewMockService(ctrl)
+	mockSender := sender.NewMockSender(ctrl)
+	logger := slog.New(slog.NewTextHandler(&stubwriter.Writer{}, nil))
+
+	delivered := make(chan struct{})
+
+	mockSubscriptionService.EXPECT().Subscriptions(gomock.Any(), int64(1)).Return([]model.Subscription{{HouseID: 1, Email: "test@gmail.com"}}, nil)
+	gomock.InOrder(
+		mockSender.EXPECT().SendEmail(gomock.Any(), "test@gmail.com", gomock.Any()).Return(errors.New("internal error")),
+		mockSender.EXPECT().SendEmail(gomock.Any(), "test@gmail.com", gomock.Any()).DoAndReturn(func(context.Context, string, string) error {
+			close(delivered)
+			return nil
+		}),
+	)
+
+	n := New(mockSubscriptionService, mockSender, 1, 10, logger)
+	n.Start()
+	n.Notify(model.Apartment{ID: 1, HouseID: 1})
+
+	select {
+	case <-delivered:
+	case <-time.After(5 * time.Second):
+		t.Fatal("email was not retried")
+	}
+
+	assert.NoError(t, n.Stop(context.Background()))
+}
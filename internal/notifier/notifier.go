@@ -0,0 +1,12 @@
+package notifier
+
+import (
+	"avito/internal/model"
+	"context"
+)
+
+type Notifier interface {
+	Notify(apartment model.Apartment)
+	Start()
+	Stop(ctx context.Context) error
+}
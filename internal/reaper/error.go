@@ -0,0 +1,8 @@
+package reaper
+
+import "errors"
+
+var (
+	ErrInvalidTimeout  = errors.New("moderation claim timeout must be positive")
+	ErrInvalidInterval = errors.New("reaper interval must be positive")
+)
@@ -0,0 +1,127 @@
+package reaperimpl
+
+import (
+	"avito/internal/reaper"
+	apartmentservice "avito/internal/service/apartment"
+	sessionservice "avito/internal/service/session"
+	"avito/pkg/logger"
+	"context"
+	"github.com/google/uuid"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// claimReaper periodically returns apartments whose moderation claim outlived the timeout
+// back to the queue, so a moderator who went offline doesn't block them forever. On the same
+// tick it deletes the expired sessions, nothing else would ever remove them.
+type claimReaper struct {
+	apartmentService apartmentservice.Service
+	sessionService   sessionservice.Service
+
+	timeout  time.Duration
+	interval time.Duration
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	logger *slog.Logger
+}
+
+func (r *claimReaper) Start() {
+	r.wg.Add(1)
+	go r.run()
+}
+
+func (r *claimReaper) Stop(ctx context.Context) error {
+	r.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (r *claimReaper) run() {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.ctx.Done():
+			return
+		case <-ticker.C:
+			r.reap()
+		}
+	}
+}
+
+func (r *claimReaper) reap() {
+	ctx := context.WithValue(r.ctx, logger.LogIDContextKey, uuid.New().ID())
+
+	r.releaseStaleClaims(ctx)
+	r.deleteExpiredSessions(ctx)
+}
+
+func (r *claimReaper) releaseStaleClaims(ctx context.Context) {
+	apartments, err := r.apartmentService.ReleaseStaleClaims(ctx, r.timeout)
+	if err != nil {
+		r.logger.Error("Failed to release stale moderation claims", slog.String("error", err.Error()))
+		return
+	}
+
+	for _, apartment := range apartments {
+		r.logger.Info("Stale moderation claim released",
+			slog.Any("apartment_id", apartment.ID),
+			slog.Any("moderator_id", apartment.ModeratorID),
+			slog.Duration("timeout", r.timeout))
+	}
+}
+
+func (r *claimReaper) deleteExpiredSessions(ctx context.Context) {
+	deleted, err := r.sessionService.DeleteExpired(ctx)
+	if err != nil {
+		r.logger.Error("Failed to delete expired sessions", slog.String("error", err.Error()), slog.Int("deleted", deleted))
+		return
+	}
+
+	if deleted > 0 {
+		r.logger.Info("Expired sessions deleted", slog.Int("deleted", deleted))
+	}
+}
+
+// New refuses a non-positive timeout, it would release every claim right after it is taken,
+// and a non-positive interval, the ticker can't run with it.
+func New(apartmentService apartmentservice.Service, sessionService sessionservice.Service, timeout time.Duration, interval time.Duration, logger *slog.Logger) (reaper.Reaper, error) {
+	switch {
+	case timeout <= 0:
+		return nil, reaper.ErrInvalidTimeout
+	case interval <= 0:
+		return nil, reaper.ErrInvalidInterval
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	r := &claimReaper{
+		apartmentService: apartmentService,
+		sessionService:   sessionService,
+		timeout:          timeout,
+		interval:         interval,
+		ctx:              ctx,
+		cancel:           cancel,
+		logger:           logger,
+	}
+
+	return r, nil
+}
@@ -0,0 +1,180 @@
+package reaperimpl
+
+import (
+	"avito/internal/model"
+	"avito/internal/reaper"
+	apartmentservice "avito/internal/service/apartment"
+	sessionservice "avito/internal/service/session"
+	stubwriter "avito/pkg/stub_writer"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestReap(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockApartmentService := apartmentservice.NewMockService(ctrl)
+	mockSessionService := sessionservice.NewMockService(ctrl)
+	mockSessionService.EXPECT().DeleteExpired(gomock.Any()).Return(0, nil).AnyTimes()
+	logger := slog.New(slog.NewTextHandler(&stubwriter.Writer{}, nil))
+
+	timeout := 30 * time.Minute
+	released := make(chan struct{})
+
+	mockApartmentService.EXPECT().ReleaseStaleClaims(gomock.Any(), timeout).DoAndReturn(func(context.Context, time.Duration) ([]model.Apartment, error) {
+		close(released)
+		return []model.Apartment{{ID: 1, ModeratorID: 2}}, nil
+	})
+	mockApartmentService.EXPECT().ReleaseStaleClaims(gomock.Any(), timeout).Return(nil, nil).AnyTimes()
+
+	r, err := New(mockApartmentService, mockSessionService, timeout, 10*time.Millisecond, logger)
+	assert.NoError(t, err)
+	r.Start()
+
+	select {
+	case <-released:
+	case <-time.After(time.Second):
+		t.Fatal("stale claims were not released")
+	}
+
+	assert.NoError(t, r.Stop(context.Background()))
+}
+
+func TestReapErr(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockApartmentService := apartmentservice.NewMockService(ctrl)
+	mockSessionService := sessionservice.NewMockService(ctrl)
+	mockSessionService.EXPECT().DeleteExpired(gomock.Any()).Return(0, nil).AnyTimes()
+	logger := slog.New(slog.NewTextHandler(&stubwriter.Writer{}, nil))
+
+	attempted := make(chan struct{}, 2)
+
+	//THE REAPER KEEPS RUNNING AFTER A FAILED PASS
+	mockApartmentService.EXPECT().ReleaseStaleClaims(gomock.Any(), gomock.Any()).DoAndReturn(func(context.Context, time.Duration) ([]model.Apartment, error) {
+		select {
+		case attempted <- struct{}{}:
+		default:
+		}
+		return nil, apartmentservice.ErrInternal
+	}).MinTimes(2)
+
+	r, err := New(mockApartmentService, mockSessionService, time.Minute, 10*time.Millisecond, logger)
+	assert.NoError(t, err)
+	r.Start()
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-attempted:
+		case <-time.After(time.Second):
+			t.Fatal("reaper stopped after a failed pass")
+		}
+	}
+
+	assert.NoError(t, r.Stop(context.Background()))
+}
+
+func TestReapSessions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockApartmentService := apartmentservice.NewMockService(ctrl)
+	mockSessionService := sessionservice.NewMockService(ctrl)
+	logger := slog.New(slog.NewTextHandler(&stubwriter.Writer{}, nil))
+
+	deleted := make(chan struct{})
+
+	//A FAILED CLAIMS PASS DOESN'T KEEP THE EXPIRED SESSIONS
+	mockApartmentService.EXPECT().ReleaseStaleClaims(gomock.Any(), gomock.Any()).Return(nil, apartmentservice.ErrInternal).AnyTimes()
+	mockSessionService.EXPECT().DeleteExpired(gomock.Any()).DoAndReturn(func(context.Context) (int, error) {
+		close(deleted)
+		return 3, nil
+	})
+	mockSessionService.EXPECT().DeleteExpired(gomock.Any()).Return(0, nil).AnyTimes()
+
+	r, err := New(mockApartmentService, mockSessionService, time.Minute, 10*time.Millisecond, logger)
+	assert.NoError(t, err)
+	r.Start()
+
+	select {
+	case <-deleted:
+	case <-time.After(time.Second):
+		t.Fatal("expired sessions were not deleted")
+	}
+
+	assert.NoError(t, r.Stop(context.Background()))
+}
+
+func TestStop(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockApartmentService := apartmentservice.NewMockService(ctrl)
+	mockSessionService := sessionservice.NewMockService(ctrl)
+	mockSessionService.EXPECT().DeleteExpired(gomock.Any()).Return(0, nil).AnyTimes()
+	logger := slog.New(slog.NewTextHandler(&stubwriter.Writer{}, nil))
+
+	r, err := New(mockApartmentService, mockSessionService, time.Minute, time.Hour, logger)
+	assert.NoError(t, err)
+	r.Start()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	assert.NoError(t, r.Stop(ctx))
+}
+
+func TestNewErr(t *testing.T) {
+	cases := []struct {
+		name        string
+		timeout     time.Duration
+		interval    time.Duration
+		expectedErr error
+	}{
+		{
+			//EVERY CLAIM WOULD BE RELEASED RIGHT AFTER IT IS TAKEN
+			name:        "ZERO TIMEOUT",
+			timeout:     0,
+			interval:    time.Minute,
+			expectedErr: reaper.ErrInvalidTimeout,
+		},
+		{
+			name:        "NEGATIVE TIMEOUT",
+			timeout:     -time.Minute,
+			interval:    time.Minute,
+			expectedErr: reaper.ErrInvalidTimeout,
+		},
+		{
+			//THE TICKER PANICS ON A NON-POSITIVE INTERVAL
+			name:        "ZERO INTERVAL",
+			timeout:     time.Minute,
+			interval:    0,
+			expectedErr: reaper.ErrInvalidInterval,
+		},
+		{
+			name:        "NEGATIVE INTERVAL",
+			timeout:     time.Minute,
+			interval:    -time.Second,
+			expectedErr: reaper.ErrInvalidInterval,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			logger := slog.New(slog.NewTextHandler(&stubwriter.Writer{}, nil))
+
+			r, err := New(apartmentservice.NewMockService(ctrl), sessionservice.NewMockService(ctrl), c.timeout, c.interval, logger)
+			assert.ErrorIs(t, err, c.expectedErr)
+			assert.Nil(t, r)
+		})
+	}
+}
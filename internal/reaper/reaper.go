@@ -0,0 +1,8 @@
+package reaper
+
+import "context"
+
+type Reaper interface {
+	Start()
+	Stop(ctx context.Context) error
+}
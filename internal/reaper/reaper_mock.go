@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/reaper/reaper.go
+//
+// Generated by this command:
+//
+//	mockgen -source internal/reaper/reaper.go -destination internal/reaper/reaper_mock.go
+//
+
+// Package mock_reaper is a generated GoMock package.
+package reaper
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockReaper is a mock of Reaper interface.
+type MockReaper struct {
+	ctrl     *gomock.Controller
+	recorder *MockReaperMockRecorder
+}
+
+// MockReaperMockRecorder is the mock recorder for MockReaper.
+type MockReaperMockRecorder struct {
+	mock *MockReaper
+}
+
+// NewMockReaper creates a new mock instance.
+func NewMockReaper(ctrl *gomock.Controller) *MockReaper {
+	mock := &MockReaper{ctrl: ctrl}
+	mock.recorder = &MockReaperMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReaper) EXPECT() *MockReaperMockRecorder {
+	return m.recorder
+}
+
+// Start mocks base method.
+func (m *MockReaper) Start() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Start")
+}
+
+// Start indicates an expected call of Start.
+func (mr *MockReaperMockRecorder) Start() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockReaper)(nil).Start))
+}
+
+// Stop mocks base method.
+func (m *MockReaper) Stop(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stop", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Stop indicates an expected call of Stop.
+func (mr *MockReaperMockRecorder) Stop(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stop", reflect.TypeOf((*MockReaper)(nil).Stop), ctx)
+}
@@ -13,5 +13,10 @@ func ToApartmentDTO(apartment apartmentrepositorymodel.Apartment) model.Apartmen
 		Price:            apartment.Price,
 		NumberOfRooms:    apartment.NumberOfRooms,
 		ModerationStatus: apartment.ModerationStatus,
+		OwnerID:          apartment.OwnerID.Int64,
+		ModeratorID:      apartment.ModeratorID.Int64,
+		DeclineReason:    apartment.DeclineReason.String,
+		ModeratorComment: apartment.ModeratorComment.String,
+		RemovalReason:    apartment.RemovalReason.String,
 	}
 }
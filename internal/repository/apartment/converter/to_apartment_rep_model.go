@@ -3,6 +3,7 @@ package apartmentrepositoryconverter
 import (
 	"avito/internal/model"
 	apartmentrepositorymodel "avito/internal/repository/apartment/model"
+	"database/sql"
 )
 
 func ToApartmentRepModel(apartment model.Apartment) apartmentrepositorymodel.Apartment {
@@ -13,5 +14,25 @@ func ToApartmentRepModel(apartment model.Apartment) apartmentrepositorymodel.Apa
 		Price:            apartment.Price,
 		NumberOfRooms:    apartment.NumberOfRooms,
 		ModerationStatus: apartment.ModerationStatus,
+		OwnerID: sql.NullInt64{
+			Int64: apartment.OwnerID,
+			Valid: apartment.OwnerID != 0,
+		},
+		ModeratorID: sql.NullInt64{
+			Int64: apartment.ModeratorID,
+			Valid: apartment.ModeratorID != 0,
+		},
+		DeclineReason: sql.NullString{
+			String: apartment.DeclineReason,
+			Valid:  apartment.DeclineReason != "",
+		},
+		ModeratorComment: sql.NullString{
+			String: apartment.ModeratorComment,
+			Valid:  apartment.ModeratorComment != "",
+		},
+		RemovalReason: sql.NullString{
+			String: apartment.RemovalReason,
+			Valid:  apartment.RemovalReason != "",
+		},
 	}
 }
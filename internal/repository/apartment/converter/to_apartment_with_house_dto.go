@@ -0,0 +1,18 @@
+package apartmentrepositoryconverter
+
+import (
+	"avito/internal/model"
+	apartmentrepositorymodel "avito/internal/repository/apartment/model"
+)
+
+func ToApartmentWithHouseDTO(apartment apartmentrepositorymodel.Apartment, house apartmentrepositorymodel.SearchHouse) model.ApartmentWithHouse {
+	return model.ApartmentWithHouse{
+		Apartment: ToApartmentDTO(apartment),
+		House: model.House{
+			HouseId:   apartment.HouseID,
+			Address:   house.Address,
+			Year:      house.Year,
+			Developer: house.Developer.String,
+		},
+	}
+}
@@ -0,0 +1,18 @@
+package apartmentrepositoryconverter
+
+import (
+	apartmentrepositorymodel "avito/internal/repository/apartment/model"
+	"testing"
+)
+
+func BenchmarkToApartmentWithHouseDTO(b *testing.B) {
+	b.ReportAllocs()
+
+	apartment := apartmentrepositorymodel.Apartment{}
+	house := apartmentrepositorymodel.SearchHouse{}
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		ToApartmentWithHouseDTO(apartment, house)
+	}
+}
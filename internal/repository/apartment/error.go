@@ -5,4 +5,9 @@ import "errors"
 var (
 	ErrInternal       = errors.New("internal server error")
 	ErrInvalidHouseID = errors.New("invalid house id")
+
+	ErrApartmentNotFound      = errors.New("apartment not found")
+	ErrApartmentAlreadyExists = errors.New("apartment with this number already exists in the house")
+	ErrModerationConflict     = errors.New("apartment is claimed by another moderator or has a different moderation status")
+	ErrModerationQueueEmpty   = errors.New("no apartments awaiting moderation")
 )
@@ -1,10 +1,17 @@
 package apartmentrepositorymodel
 
+import "database/sql"
+
 type Apartment struct {
-	ID               uint32
+	ID               int64
 	ApartmentNumber  int
-	HouseID          uint32
+	HouseID          int64
 	Price            uint32
 	NumberOfRooms    uint32
 	ModerationStatus string
+	OwnerID          sql.NullInt64
+	ModeratorID      sql.NullInt64
+	DeclineReason    sql.NullString
+	ModeratorComment sql.NullString
+	RemovalReason    sql.NullString
 }
@@ -0,0 +1,9 @@
+package apartmentrepositorymodel
+
+import "database/sql"
+
+type SearchHouse struct {
+	Address   string
+	Year      int
+	Developer sql.NullString
+}
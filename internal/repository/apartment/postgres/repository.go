@@ -5,132 +5,557 @@ import (
 	apartmentrepository "avito/internal/repository/apartment"
 	apartmentrepositoryconverter "avito/internal/repository/apartment/converter"
 	apartmentrepositorymodel "avito/internal/repository/apartment/model"
+	"avito/pkg/database"
 	"avito/pkg/logger"
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"github.com/jackc/pgerrcode"
 	"github.com/lib/pq"
 	"log/slog"
+	"time"
 )
 
 const (
-	postgresDriverName = "postgres"
+	houseApartmentNumberConstraint = "apartments_house_id_apartment_number_key"
 )
 
+const (
+	apartmentColumns = "apartment_id, apartment_number, house_id, price, number_of_rooms, moderation_status, owner_id, moderator_id, decline_reason, moderator_comment, removal_reason"
+)
+
+var sortColumns = map[string]string{
+	model.SortByPrice: "price",
+	model.SortByRooms: "number_of_rooms",
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// scanApartment reads a row selected with apartmentColumns, extra holds the columns selected after them.
+func scanApartment(row scanner, extra ...any) (apartmentrepositorymodel.Apartment, error) {
+	apartment := apartmentrepositorymodel.Apartment{}
+	dest := []any{
+		&apartment.ID,
+		&apartment.ApartmentNumber,
+		&apartment.HouseID,
+		&apartment.Price,
+		&apartment.NumberOfRooms,
+		&apartment.ModerationStatus,
+		&apartment.OwnerID,
+		&apartment.ModeratorID,
+		&apartment.DeclineReason,
+		&apartment.ModeratorComment,
+		&apartment.RemovalReason,
+	}
+	err := row.Scan(append(dest, extra...)...)
+
+	return apartment, err
+}
+
+func scanApartments(rows *sql.Rows) ([]model.Apartment, error) {
+	defer rows.Close()
+
+	apartments := make([]model.Apartment, 0)
+	for rows.Next() {
+		apartment, err := scanApartment(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		apartments = append(apartments, apartmentrepositoryconverter.ToApartmentDTO(apartment))
+	}
+
+	return apartments, rows.Err()
+}
+
 type repository struct {
-	db     *sql.DB
+	db     *database.DB
 	logger *slog.Logger
 }
 
-func (r *repository) Create(ctx context.Context, apartment model.Apartment) error {
+func (r *repository) Create(ctx context.Context, apartment model.Apartment) (model.Apartment, error) {
 	apartmentRepModel := apartmentrepositoryconverter.ToApartmentRepModel(apartment)
 
 	l := logger.EndToEndLogging(ctx, r.logger)
 
-	q := "INSERT INTO apartments(apartment_id, apartment_number, house_id, price, number_of_rooms, moderation_status) VALUES ($1, $2, $3, $4, $5, $6)"
-	stmt, err := r.db.Prepare(q)
+	q := "INSERT INTO apartments(apartment_number, house_id, price, number_of_rooms, moderation_status, owner_id) VALUES ($1, $2, $3, $4, $5, $6) RETURNING " + apartmentColumns
+	stmt, release, err := r.db.Statement(ctx, q)
 	if err != nil {
 		l.Error("Failed to prepare statement for create apartment", "error", err.Error())
-		return apartmentrepository.ErrInternal
+		return model.Apartment{}, apartmentrepository.ErrInternal
 	}
-	defer stmt.Close()
+	defer release()
 
-	if _, err = stmt.ExecContext(ctx,
-		apartmentRepModel.ID,
+	created, err := scanApartment(stmt.QueryRowContext(ctx,
 		apartmentRepModel.ApartmentNumber,
 		apartmentRepModel.HouseID,
 		apartmentRepModel.Price,
 		apartmentRepModel.NumberOfRooms,
-		apartmentRepModel.ModerationStatus); err != nil {
+		apartmentRepModel.ModerationStatus,
+		apartmentRepModel.OwnerID))
+	if err != nil {
 		l.Error("Failed to create apartment", "error", err.Error())
 
 		var pgerr *pq.Error
 		if errors.As(err, &pgerr) {
 			switch {
 			case pgerr.Code == pgerrcode.ForeignKeyViolation:
-				return apartmentrepository.ErrInvalidHouseID
+				return model.Apartment{}, apartmentrepository.ErrInvalidHouseID
+			case pgerr.Code == pgerrcode.UniqueViolation && pgerr.Constraint == houseApartmentNumberConstraint:
+				return model.Apartment{}, apartmentrepository.ErrApartmentAlreadyExists
 			}
 		}
 
-		return apartmentrepository.ErrInternal
+		return model.Apartment{}, apartmentrepository.ErrInternal
 	}
 
-	return nil
+	return apartmentrepositoryconverter.ToApartmentDTO(created), nil
 }
 
-func (r *repository) Update(ctx context.Context, apartment model.Apartment) error {
+func (r *repository) Apartment(ctx context.Context, apartmentID int64) (model.Apartment, error) {
+	l := logger.EndToEndLogging(ctx, r.logger)
+
+	q := "SELECT " + apartmentColumns + " FROM apartments WHERE apartment_id = $1 AND deleted_at IS NULL"
+	stmt, release, err := r.db.Statement(ctx, q)
+	if err != nil {
+		l.Error("Failed to prepare statement for get apartment", "error", err.Error())
+		return model.Apartment{}, apartmentrepository.ErrInternal
+	}
+	defer release()
+
+	apartment, err := scanApartment(stmt.QueryRowContext(ctx, apartmentID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Apartment{}, apartmentrepository.ErrApartmentNotFound
+		}
+
+		l.Error("Failed to get apartment", "error", err.Error())
+		return model.Apartment{}, apartmentrepository.ErrInternal
+	}
+
+	return apartmentrepositoryconverter.ToApartmentDTO(apartment), nil
+}
+
+// Update changes the moderation state of the apartment only if it is still in expectedStatus and isn't held by
+// a moderator other than moderatorID, so concurrent moderators can't take over each other's claims.
+// The first approval is stamped in the same update, so exactly one caller learns about it.
+func (r *repository) Update(ctx context.Context, apartment model.Apartment, expectedStatus string, moderatorID int64) (model.Apartment, bool, error) {
 	apartmentRepModel := apartmentrepositoryconverter.ToApartmentRepModel(apartment)
 
 	l := logger.EndToEndLogging(ctx, r.logger)
 
+	//PREVIOUS HOLDS THE ROW AS IT WAS BEFORE THE UPDATE, RETURNING ONLY SEES THE NEW ONE
 	q := `UPDATE apartments SET 
-					  apartment_number = $1,
-					  house_id = $2,
-					  price = $3,
-					  number_of_rooms = $4,
-					  moderation_status = $5 WHERE apartment_id = $6`
-	stmt, err := r.db.Prepare(q)
+					  moderation_status = $1,
+					  moderator_id = $2,
+					  claimed_at = CASE WHEN $1 = 'on moderation' THEN NOW() END,
+					  decline_reason = $3,
+					  moderator_comment = $4,
+					  first_approved_at = CASE WHEN $1 = 'approved' THEN COALESCE(previous.previous_first_approved_at, NOW()) ELSE previous.previous_first_approved_at END
+					  FROM (SELECT apartment_id AS previous_id, first_approved_at AS previous_first_approved_at
+					  	FROM apartments WHERE apartment_id = $5 FOR UPDATE) previous
+					  WHERE apartment_id = previous.previous_id
+					  AND deleted_at IS NULL
+					  AND moderation_status = $6
+					  AND (moderator_id IS NULL OR moderator_id = $7)
+					  RETURNING ` + apartmentColumns + `, moderation_status = 'approved' AND previous.previous_first_approved_at IS NULL`
+	stmt, release, err := r.db.Statement(ctx, q)
 	if err != nil {
 		l.Error("Failed to prepare statement for update apartment", "error", err.Error())
-		return apartmentrepository.ErrInternal
+		return model.Apartment{}, false, apartmentrepository.ErrInternal
 	}
+	defer release()
 
-	defer stmt.Close()
-
-	if _, err = stmt.ExecContext(ctx,
-		apartmentRepModel.ApartmentNumber,
-		apartmentRepModel.HouseID,
-		apartmentRepModel.Price,
-		apartmentRepModel.NumberOfRooms,
+	var firstApproval bool
+	updated, err := scanApartment(stmt.QueryRowContext(ctx,
 		apartmentRepModel.ModerationStatus,
-		apartmentRepModel.ID); err != nil {
-		l.Info("Failed to update apartment", "error", err.Error())
+		apartmentRepModel.ModeratorID,
+		apartmentRepModel.DeclineReason,
+		apartmentRepModel.ModeratorComment,
+		apartmentRepModel.ID,
+		expectedStatus,
+		moderatorID), &firstApproval)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Apartment{}, false, r.updateConflict(ctx, apartmentRepModel.ID, l)
+		}
+
+		l.Error("Failed to update apartment", "error", err.Error())
 
+		//ONLY THE STATUS IS UPDATED, THE NUMBER CAN'T CLASH TODAY BUT THE CONSTRAINT IS REPORTED AS ON CREATE
 		var pgerr *pq.Error
-		if errors.As(err, &pgerr) {
-			switch {
-			case pgerr.Code == pgerrcode.ForeignKeyViolation:
-				return apartmentrepository.ErrInvalidHouseID
-			}
+		if errors.As(err, &pgerr) && pgerr.Code == pgerrcode.UniqueViolation && pgerr.Constraint == houseApartmentNumberConstraint {
+			return model.Apartment{}, false, apartmentrepository.ErrApartmentAlreadyExists
 		}
 
+		return model.Apartment{}, false, apartmentrepository.ErrInternal
+	}
+
+	return apartmentrepositoryconverter.ToApartmentDTO(updated), firstApproval, nil
+}
+
+// Edit applies the owner's changes and sends the apartment back to the moderation queue.
+// Like Update it only succeeds while the apartment is still in expectedStatus.
+func (r *repository) Edit(ctx context.Context, apartment model.Apartment, expectedStatus string) (model.Apartment, error) {
+	apartmentRepModel := apartmentrepositoryconverter.ToApartmentRepModel(apartment)
+
+	l := logger.EndToEndLogging(ctx, r.logger)
+
+	q := `UPDATE apartments SET 
+					  price = $1,
+					  number_of_rooms = $2,
+					  moderation_status = 'created',
+					  moderator_id = NULL,
+					  claimed_at = NULL,
+					  decline_reason = NULL,
+					  moderator_comment = NULL WHERE apartment_id = $3
+					  AND deleted_at IS NULL
+					  AND owner_id = $4
+					  AND moderation_status = $5
+					  RETURNING ` + apartmentColumns
+	stmt, release, err := r.db.Statement(ctx, q)
+	if err != nil {
+		l.Error("Failed to prepare statement for edit apartment", "error", err.Error())
+		return model.Apartment{}, apartmentrepository.ErrInternal
+	}
+	defer release()
+
+	edited, err := scanApartment(stmt.QueryRowContext(ctx,
+		apartmentRepModel.Price,
+		apartmentRepModel.NumberOfRooms,
+		apartmentRepModel.ID,
+		apartmentRepModel.OwnerID,
+		expectedStatus))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Apartment{}, r.updateConflict(ctx, apartmentRepModel.ID, l)
+		}
+
+		l.Error("Failed to edit apartment", "error", err.Error())
+		return model.Apartment{}, apartmentrepository.ErrInternal
+	}
+
+	return apartmentrepositoryconverter.ToApartmentDTO(edited), nil
+}
+
+// updateConflict tells a missing apartment apart from one that lost the compare-and-set.
+func (r *repository) updateConflict(ctx context.Context, apartmentID int64, l *slog.Logger) error {
+	q := "SELECT EXISTS(SELECT 1 FROM apartments WHERE apartment_id = $1 AND deleted_at IS NULL)"
+	stmt, release, err := r.db.Statement(ctx, q)
+	if err != nil {
+		l.Error("Failed to prepare statement for check apartment existence", "error", err.Error())
+		return apartmentrepository.ErrInternal
+	}
+	defer release()
+
+	exists := false
+	if err = stmt.QueryRowContext(ctx, apartmentID).Scan(&exists); err != nil {
+		l.Error("Failed to check apartment existence", "error", err.Error())
 		return apartmentrepository.ErrInternal
 	}
 
-	return nil
+	if !exists {
+		return apartmentrepository.ErrApartmentNotFound
+	}
+
+	return apartmentrepository.ErrModerationConflict
 }
 
-func (r *repository) Apartments(ctx context.Context, houseID uint32, offset int, limit int, moderationStatusConstraint bool) ([]model.Apartment, error) {
-	apartments := make([]model.Apartment, 0, limit)
+// NextForModeration claims the oldest created apartment for moderatorID in a single statement.
+// SKIP LOCKED lets concurrent moderators pass over rows someone else is claiming right now.
+func (r *repository) NextForModeration(ctx context.Context, moderatorID int64, filter model.ModerationQueueFilter) (model.Apartment, error) {
+	l := logger.EndToEndLogging(ctx, r.logger)
+
+	args := []any{moderatorID}
+	constraint := " WHERE moderation_status = 'created' AND deleted_at IS NULL"
 
+	if filter.HouseID != 0 {
+		args = append(args, filter.HouseID)
+		constraint += fmt.Sprintf(" AND house_id = $%d", len(args))
+	}
+
+	if filter.MinPrice != 0 {
+		args = append(args, filter.MinPrice)
+		constraint += fmt.Sprintf(" AND price >= $%d", len(args))
+	}
+
+	if filter.MaxPrice != 0 {
+		args = append(args, filter.MaxPrice)
+		constraint += fmt.Sprintf(" AND price <= $%d", len(args))
+	}
+
+	q := `UPDATE apartments SET moderation_status = 'on moderation', moderator_id = $1, claimed_at = NOW()
+			WHERE apartment_id = (
+				SELECT apartment_id FROM apartments` + constraint + `
+				ORDER BY created_at, apartment_id
+				LIMIT 1
+				FOR UPDATE SKIP LOCKED)
+			RETURNING ` + apartmentColumns
+
+	stmt, release, err := r.db.Statement(ctx, q)
+	if err != nil {
+		l.Error("Failed to prepare statement for claim next apartment", "error", err.Error())
+		return model.Apartment{}, apartmentrepository.ErrInternal
+	}
+	defer release()
+
+	apartment, err := scanApartment(stmt.QueryRowContext(ctx, args...))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Apartment{}, apartmentrepository.ErrModerationQueueEmpty
+		}
+
+		l.Error("Failed to claim next apartment", "error", err.Error())
+		return model.Apartment{}, apartmentrepository.ErrInternal
+	}
+
+	return apartmentrepositoryconverter.ToApartmentDTO(apartment), nil
+}
+
+// ReleaseStaleClaims returns apartments claimed longer than timeout ago back to the moderation queue.
+// The returned apartments carry the moderator who held the claim, so the release can be audited.
+func (r *repository) ReleaseStaleClaims(ctx context.Context, timeout time.Duration) ([]model.Apartment, error) {
 	l := logger.EndToEndLogging(ctx, r.logger)
 
-	constraint := " WHERE house_id = $1"
+	q := `UPDATE apartments a SET moderation_status = 'created', moderator_id = NULL, claimed_at = NULL
+			FROM (
+				SELECT apartment_id, moderator_id FROM apartments
+				WHERE moderation_status = 'on moderation'
+				AND deleted_at IS NULL
+				AND claimed_at < NOW() - $1 * INTERVAL '1 second'
+				FOR UPDATE SKIP LOCKED) stale
+			WHERE a.apartment_id = stale.apartment_id
+			RETURNING a.apartment_id, a.apartment_number, a.house_id, a.price, a.number_of_rooms, a.moderation_status,
+				a.owner_id, stale.moderator_id, a.decline_reason, a.moderator_comment, a.removal_reason`
+
+	stmt, release, err := r.db.Statement(ctx, q)
+	if err != nil {
+		l.Error("Failed to prepare statement for release stale claims", "error", err.Error())
+		return nil, apartmentrepository.ErrInternal
+	}
+	defer release()
 
-	if moderationStatusConstraint {
-		constraint += " AND moderation_status = 'approved'"
+	rows, err := stmt.QueryContext(ctx, timeout.Seconds())
+	if err != nil {
+		l.Error("Failed to release stale claims", "error", err.Error())
+		return nil, apartmentrepository.ErrInternal
+	}
+
+	apartments, err := scanApartments(rows)
+	if err != nil {
+		l.Error("Failed to release stale claims", "error", err.Error())
+		return nil, apartmentrepository.ErrInternal
+	}
+
+	return apartments, nil
+}
+
+// Delete soft-deletes the apartment: it stays in the table with the given terminal status,
+// but every query of this repository ignores it from now on.
+func (r *repository) Delete(ctx context.Context, apartmentID int64, status string, reason string) (model.Apartment, error) {
+	l := logger.EndToEndLogging(ctx, r.logger)
+
+	q := `UPDATE apartments SET 
+					  moderation_status = $1,
+					  removal_reason = $2,
+					  deleted_at = NOW(),
+					  moderator_id = NULL,
+					  claimed_at = NULL WHERE apartment_id = $3
+					  AND deleted_at IS NULL
+					  RETURNING ` + apartmentColumns
+	stmt, release, err := r.db.Statement(ctx, q)
+	if err != nil {
+		l.Error("Failed to prepare statement for delete apartment", "error", err.Error())
+		return model.Apartment{}, apartmentrepository.ErrInternal
 	}
+	defer release()
 
-	q := "SELECT apartment_id, apartment_number, house_id, price, number_of_rooms, moderation_status FROM apartments" + constraint + " OFFSET $2 LIMIT $3"
+	deleted, err := scanApartment(stmt.QueryRowContext(ctx, status, sql.NullString{String: reason, Valid: reason != ""}, apartmentID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Apartment{}, apartmentrepository.ErrApartmentNotFound
+		}
+
+		l.Error("Failed to delete apartment", "error", err.Error())
+		return model.Apartment{}, apartmentrepository.ErrInternal
+	}
 
-	stmt, err := r.db.Prepare(q)
+	return apartmentrepositoryconverter.ToApartmentDTO(deleted), nil
+}
+
+func (r *repository) Apartments(ctx context.Context, houseID int64, page model.Page, moderationStatusConstraint bool, criteria model.ApartmentCriteria) ([]model.Apartment, error) {
+	l := logger.EndToEndLogging(ctx, r.logger)
+
+	q, args := apartmentsQuery(houseID, page, moderationStatusConstraint, criteria)
+
+	stmt, release, err := r.db.Statement(ctx, q)
 	if err != nil {
 		l.Error("Failed to prepare statement for get apartments", "error", err.Error())
 		return nil, apartmentrepository.ErrInternal
 	}
-	defer stmt.Close()
+	defer release()
+
+	rows, err := stmt.QueryContext(ctx, args...)
+	if err != nil {
+		l.Error("Failed to get apartments", "error", err.Error())
+		return nil, apartmentrepository.ErrInternal
+	}
 
-	rows, err := stmt.QueryContext(ctx, houseID, offset, limit)
+	apartments, err := scanApartments(rows)
 	if err != nil {
 		l.Error("Failed to get apartments", "error", err.Error())
 		return nil, apartmentrepository.ErrInternal
 	}
 
+	return apartments, nil
+}
+
+func apartmentsQuery(houseID int64, page model.Page, moderationStatusConstraint bool, criteria model.ApartmentCriteria) (string, []any) {
+	args := []any{houseID}
+	constraint := " WHERE house_id = $1 AND deleted_at IS NULL"
+
+	if moderationStatusConstraint {
+		constraint += " AND moderation_status = 'approved'"
+	}
+
+	criteriaConstraint, args := apartmentCriteriaConstraint(criteria, args)
+	constraint += criteriaConstraint
+
+	//A CURSOR REPLACES THE OFFSET, THE ROWS BEFORE IT ARE NEVER SCANNED
+	offset := page.Offset
+	if page.After != nil {
+		var pageConstraint string
+		pageConstraint, args = apartmentPageConstraint(criteria, *page.After, args)
+		constraint += pageConstraint
+		offset = 0
+	}
+
+	args = append(args, offset, page.Limit)
+	q := "SELECT " + apartmentColumns + " FROM apartments" + constraint + apartmentCriteriaOrder(criteria) +
+		fmt.Sprintf(" OFFSET $%d LIMIT $%d", len(args)-1, len(args))
+
+	return q, args
+}
+
+// apartmentCriteriaConstraint appends the criteria values to args and references them by
+// placeholder only, so user input never ends up in the query text.
+func apartmentCriteriaConstraint(criteria model.ApartmentCriteria, args []any) (string, []any) {
+	constraint := ""
+
+	conditions := []struct {
+		value  any
+		set    bool
+		clause string
+	}{
+		{value: criteria.MinPrice, set: criteria.MinPrice != 0, clause: " AND price >= $%d"},
+		{value: criteria.MaxPrice, set: criteria.MaxPrice != 0, clause: " AND price <= $%d"},
+		{value: criteria.MinRooms, set: criteria.MinRooms != 0, clause: " AND number_of_rooms >= $%d"},
+		{value: criteria.MaxRooms, set: criteria.MaxRooms != 0, clause: " AND number_of_rooms <= $%d"},
+		{value: criteria.ModerationStatus, set: criteria.ModerationStatus != "", clause: " AND moderation_status = $%d"},
+	}
+
+	for _, c := range conditions {
+		if !c.set {
+			continue
+		}
+
+		args = append(args, c.value)
+		constraint += fmt.Sprintf(c.clause, len(args))
+	}
+
+	return constraint, args
+}
+
+// apartmentCriteriaOrder maps the sort key onto a fixed column list. Unknown keys fall back
+// to the default order, apartment_id keeps pages stable among equal values.
+func apartmentCriteriaOrder(criteria model.ApartmentCriteria) string {
+	column, ok := sortColumns[criteria.SortBy]
+	if !ok {
+		return " ORDER BY apartment_id"
+	}
+
+	direction := " ASC"
+	if criteria.Descending {
+		direction = " DESC"
+	}
+
+	return " ORDER BY " + column + direction + ", apartment_id" + direction
+}
+
+// apartmentPageConstraint resumes the order of apartmentCriteriaOrder right after the cursor.
+// Both order columns share the direction, so a row comparison matches the order exactly.
+func apartmentPageConstraint(criteria model.ApartmentCriteria, after model.Cursor, args []any) (string, []any) {
+	column, ok := sortColumns[criteria.SortBy]
+	if !ok {
+		args = append(args, after.ID)
+		return fmt.Sprintf(" AND apartment_id > $%d", len(args)), args
+	}
+
+	comparison := ">"
+	if criteria.Descending {
+		comparison = "<"
+	}
+
+	args = append(args, after.SortValue, after.ID)
+	return fmt.Sprintf(" AND (%s, apartment_id) %s ($%d, $%d)", column, comparison, len(args)-1, len(args)), args
+}
+
+// Search pages through approved apartments of all houses. The total is counted only when
+// criteria.WithTotal is set, it shares the constraint of the page, so it always matches its filters.
+func (r *repository) Search(ctx context.Context, criteria model.ApartmentSearchCriteria) ([]model.ApartmentWithHouse, int, error) {
+	l := logger.EndToEndLogging(ctx, r.logger)
+
+	constraint, args := apartmentSearchConstraint(criteria)
+
+	total := 0
+	if criteria.WithTotal {
+		countQ := "SELECT COUNT(*) FROM apartments a JOIN houses h ON h.house_id = a.house_id" + constraint
+		countStmt, release, err := r.db.Statement(ctx, countQ)
+		if err != nil {
+			l.Error("Failed to prepare statement for count search results", "error", err.Error())
+			return nil, 0, apartmentrepository.ErrInternal
+		}
+		defer release()
+
+		if err = countStmt.QueryRowContext(ctx, args...).Scan(&total); err != nil {
+			l.Error("Failed to count search results", "error", err.Error())
+			return nil, 0, apartmentrepository.ErrInternal
+		}
+
+		if total == 0 {
+			return []model.ApartmentWithHouse{}, 0, nil
+		}
+	}
+
+	args = append(args, criteria.Offset, criteria.Limit)
+	q := `SELECT a.apartment_id, a.apartment_number, a.house_id, a.price, a.number_of_rooms, a.moderation_status,
+				a.owner_id, a.moderator_id, a.decline_reason, a.moderator_comment, a.removal_reason,
+				h.address, h.year, h.developer
+			FROM apartments a JOIN houses h ON h.house_id = a.house_id` + constraint + `
+			ORDER BY a.price, a.apartment_id` +
+		fmt.Sprintf(" OFFSET $%d LIMIT $%d", len(args)-1, len(args))
+
+	stmt, release, err := r.db.Statement(ctx, q)
+	if err != nil {
+		l.Error("Failed to prepare statement for search apartments", "error", err.Error())
+		return nil, 0, apartmentrepository.ErrInternal
+	}
+	defer release()
+
+	rows, err := stmt.QueryContext(ctx, args...)
+	if err != nil {
+		l.Error("Failed to search apartments", "error", err.Error())
+		return nil, 0, apartmentrepository.ErrInternal
+	}
 	defer rows.Close()
 
+	results := make([]model.ApartmentWithHouse, 0, criteria.Limit)
 	for rows.Next() {
 		apartment := apartmentrepositorymodel.Apartment{}
+		house := apartmentrepositorymodel.SearchHouse{}
 
 		if err = rows.Scan(
 			&apartment.ID,
@@ -138,38 +563,90 @@ func (r *repository) Apartments(ctx context.Context, houseID uint32, offset int,
 			&apartment.HouseID,
 			&apartment.Price,
 			&apartment.NumberOfRooms,
-			&apartment.ModerationStatus); err != nil {
-			l.Error("Failed to get apartments", "error", err.Error())
-			return nil, apartmentrepository.ErrInternal
+			&apartment.ModerationStatus,
+			&apartment.OwnerID,
+			&apartment.ModeratorID,
+			&apartment.DeclineReason,
+			&apartment.ModeratorComment,
+			&apartment.RemovalReason,
+			&house.Address,
+			&house.Year,
+			&house.Developer); err != nil {
+			l.Error("Failed to search apartments", "error", err.Error())
+			return nil, 0, apartmentrepository.ErrInternal
 		}
 
-		apartments = append(apartments, apartmentrepositoryconverter.ToApartmentDTO(apartment))
+		results = append(results, apartmentrepositoryconverter.ToApartmentWithHouseDTO(apartment, house))
 	}
 
-	return apartments, nil
+	if err = rows.Err(); err != nil {
+		l.Error("Failed to search apartments", "error", err.Error())
+		return nil, 0, apartmentrepository.ErrInternal
+	}
+
+	return results, total, nil
 }
 
-func (r *repository) CloseConnection() error {
-	return r.db.Close()
+func apartmentSearchConstraint(criteria model.ApartmentSearchCriteria) (string, []any) {
+	args := make([]any, 0)
+	constraint := " WHERE a.moderation_status = 'approved' AND a.deleted_at IS NULL"
+
+	conditions := []struct {
+		value  any
+		set    bool
+		clause string
+	}{
+		{value: criteria.MinPrice, set: criteria.MinPrice != 0, clause: " AND a.price >= $%d"},
+		{value: criteria.MaxPrice, set: criteria.MaxPrice != 0, clause: " AND a.price <= $%d"},
+		{value: criteria.MinRooms, set: criteria.MinRooms != 0, clause: " AND a.number_of_rooms >= $%d"},
+		{value: criteria.MaxRooms, set: criteria.MaxRooms != 0, clause: " AND a.number_of_rooms <= $%d"},
+		{value: criteria.MinYear, set: criteria.MinYear != 0, clause: " AND h.year >= $%d"},
+		{value: criteria.MaxYear, set: criteria.MaxYear != 0, clause: " AND h.year <= $%d"},
+		{value: criteria.Developer, set: criteria.Developer != "", clause: " AND h.developer = $%d"},
+	}
+
+	for _, c := range conditions {
+		if !c.set {
+			continue
+		}
+
+		args = append(args, c.value)
+		constraint += fmt.Sprintf(c.clause, len(args))
+	}
+
+	return constraint, args
 }
 
-func New(dataSourceName string, logger *slog.Logger) (apartmentrepository.Repository, error) {
-	r := &repository{
-		logger: logger,
+func (r *repository) OwnerApartments(ctx context.Context, ownerID int64) ([]model.Apartment, error) {
+	l := logger.EndToEndLogging(ctx, r.logger)
+
+	q := "SELECT " + apartmentColumns + " FROM apartments WHERE owner_id = $1 AND deleted_at IS NULL ORDER BY created_at DESC, apartment_id"
+
+	stmt, release, err := r.db.Statement(ctx, q)
+	if err != nil {
+		l.Error("Failed to prepare statement for get owner apartments", "error", err.Error())
+		return nil, apartmentrepository.ErrInternal
 	}
+	defer release()
 
-	db, err := sql.Open(postgresDriverName, dataSourceName)
+	rows, err := stmt.QueryContext(ctx, ownerID)
 	if err != nil {
-		logger.Error("failed to open postgres database connection", "error", err.Error())
-		return nil, err
+		l.Error("Failed to get owner apartments", "error", err.Error())
+		return nil, apartmentrepository.ErrInternal
 	}
 
-	if err = db.Ping(); err != nil {
-		logger.Error("failed to ping postgres database connection", "error", err.Error())
-		return nil, err
+	apartments, err := scanApartments(rows)
+	if err != nil {
+		l.Error("Failed to get owner apartments", "error", err.Error())
+		return nil, apartmentrepository.ErrInternal
 	}
 
-	r.db = db
+	return apartments, nil
+}
 
-	return r, err
+func New(db *database.DB, logger *slog.Logger) apartmentrepository.Repository {
+	return &repository{
+		db:     db,
+		logger: logger,
+	}
 }
@@ -0,0 +1,94 @@
+package apartmentrepositorypostgres
+
+import (
+	"avito/internal/model"
+	"avito/pkg/database"
+	stubwriter "avito/pkg/stub_writer"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+)
+
+// BenchmarkApartments compares the flats list with a statement prepared on every call
+// against the cached one. It seeds a house with seededApartments approved flats and removes
+// it afterwards, so it only needs a migrated database in TEST_DATABASE_URL:
+//
+//	TEST_DATABASE_URL=postgres://... go test -run=^$ -bench=Apartments ./internal/repository/apartment/postgres
+func BenchmarkApartments(b *testing.B) {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		b.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	logger := slog.New(slog.NewTextHandler(&stubwriter.Writer{}, nil))
+
+	db, err := database.New(dsn, database.Config{MaxOpenConns: 4, MaxIdleConns: 4}, logger)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	criteria := model.ApartmentCriteria{SortBy: model.SortByPrice}
+	page := model.Page{Limit: 20}
+
+	houseID := seedHouse(ctx, b, db)
+
+	b.Run("PreparePerCall", func(b *testing.B) {
+		q, args := apartmentsQuery(houseID, page, true, criteria)
+
+		for range b.N {
+			stmt, err := db.PrepareContext(ctx, q)
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			rows, err := stmt.QueryContext(ctx, args...)
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			if _, err = scanApartments(rows); err != nil {
+				b.Fatal(err)
+			}
+			stmt.Close()
+		}
+	})
+
+	b.Run("CachedStatement", func(b *testing.B) {
+		r := New(db, logger)
+
+		for range b.N {
+			if _, err := r.Apartments(ctx, houseID, page, true, criteria); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+const seededApartments = 1000
+
+func seedHouse(ctx context.Context, b *testing.B, db *database.DB) int64 {
+	var houseID int64
+	err := db.QueryRowContext(ctx, "INSERT INTO houses (address, year) VALUES ($1, 2000) RETURNING house_id",
+		fmt.Sprintf("benchmark %d", time.Now().UnixNano())).Scan(&houseID)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.Cleanup(func() {
+		db.ExecContext(ctx, "DELETE FROM apartments WHERE house_id = $1", houseID)
+		db.ExecContext(ctx, "DELETE FROM houses WHERE house_id = $1", houseID)
+	})
+
+	_, err = db.ExecContext(ctx, `INSERT INTO apartments (apartment_number, house_id, price, number_of_rooms, moderation_status)
+			SELECT n, $1, 1000 + n, 1 + n % 4, 'approved' FROM generate_series(1, $2::int) n`, houseID, seededApartments)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	return houseID
+}
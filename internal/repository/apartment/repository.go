@@ -3,11 +3,19 @@ package apartmentrepository
 import (
 	"avito/internal/model"
 	"context"
+	"time"
 )
 
 type Repository interface {
-	Create(ctx context.Context, apartment model.Apartment) error
-	Update(ctx context.Context, apartment model.Apartment) error
-	Apartments(ctx context.Context, houseID uint32, offset int, limit int, moderationStatusConstraint bool) ([]model.Apartment, error)
-	CloseConnection() error
+	Create(ctx context.Context, apartment model.Apartment) (model.Apartment, error)
+	Apartment(ctx context.Context, apartmentID int64) (model.Apartment, error)
+	// Update reports firstApproval when it approves the apartment for the first time.
+	Update(ctx context.Context, apartment model.Apartment, expectedStatus string, moderatorID int64) (updated model.Apartment, firstApproval bool, err error)
+	Edit(ctx context.Context, apartment model.Apartment, expectedStatus string) (model.Apartment, error)
+	NextForModeration(ctx context.Context, moderatorID int64, filter model.ModerationQueueFilter) (model.Apartment, error)
+	Delete(ctx context.Context, apartmentID int64, status string, reason string) (model.Apartment, error)
+	ReleaseStaleClaims(ctx context.Context, timeout time.Duration) ([]model.Apartment, error)
+	Apartments(ctx context.Context, houseID int64, page model.Page, moderationStatusConstraint bool, criteria model.ApartmentCriteria) ([]model.Apartment, error)
+	Search(ctx context.Context, criteria model.ApartmentSearchCriteria) ([]model.ApartmentWithHouse, int, error)
+	OwnerApartments(ctx context.Context, ownerID int64) ([]model.Apartment, error)
 }
@@ -13,6 +13,7 @@ import (
 	model "avito/internal/model"
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "go.uber.org/mock/gomock"
 )
@@ -40,41 +41,43 @@ func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
 	return m.recorder
 }
 
-// Apartments mocks base method.
-func (m *MockRepository) Apartments(ctx context.Context, houseID uint32, offset, limit int, moderationStatusConstraint bool) ([]model.Apartment, error) {
+// Apartment mocks base method.
+func (m *MockRepository) Apartment(ctx context.Context, apartmentID int64) (model.Apartment, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Apartments", ctx, houseID, offset, limit, moderationStatusConstraint)
-	ret0, _ := ret[0].([]model.Apartment)
+	ret := m.ctrl.Call(m, "Apartment", ctx, apartmentID)
+	ret0, _ := ret[0].(model.Apartment)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// Apartments indicates an expected call of Apartments.
-func (mr *MockRepositoryMockRecorder) Apartments(ctx, houseID, offset, limit, moderationStatusConstraint any) *gomock.Call {
+// Apartment indicates an expected call of Apartment.
+func (mr *MockRepositoryMockRecorder) Apartment(ctx, apartmentID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Apartments", reflect.TypeOf((*MockRepository)(nil).Apartments), ctx, houseID, offset, limit, moderationStatusConstraint)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Apartment", reflect.TypeOf((*MockRepository)(nil).Apartment), ctx, apartmentID)
 }
 
-// CloseConnection mocks base method.
-func (m *MockRepository) CloseConnection() error {
+// Apartments mocks base method.
+func (m *MockRepository) Apartments(ctx context.Context, houseID int64, page model.Page, moderationStatusConstraint bool, criteria model.ApartmentCriteria) ([]model.Apartment, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CloseConnection")
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "Apartments", ctx, houseID, page, moderationStatusConstraint, criteria)
+	ret0, _ := ret[0].([]model.Apartment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// CloseConnection indicates an expected call of CloseConnection.
-func (mr *MockRepositoryMockRecorder) CloseConnection() *gomock.Call {
+// Apartments indicates an expected call of Apartments.
+func (mr *MockRepositoryMockRecorder) Apartments(ctx, houseID, page, moderationStatusConstraint, criteria any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseConnection", reflect.TypeOf((*MockRepository)(nil).CloseConnection))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Apartments", reflect.TypeOf((*MockRepository)(nil).Apartments), ctx, houseID, page, moderationStatusConstraint, criteria)
 }
 
 // Create mocks base method.
-func (m *MockRepository) Create(ctx context.Context, apartment model.Apartment) error {
+func (m *MockRepository) Create(ctx context.Context, apartment model.Apartment) (model.Apartment, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Create", ctx, apartment)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(model.Apartment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // Create indicates an expected call of Create.
@@ -83,16 +86,109 @@ func (mr *MockRepositoryMockRecorder) Create(ctx, apartment any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRepository)(nil).Create), ctx, apartment)
 }
 
+// Delete mocks base method.
+func (m *MockRepository) Delete(ctx context.Context, apartmentID int64, status, reason string) (model.Apartment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, apartmentID, status, reason)
+	ret0, _ := ret[0].(model.Apartment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockRepositoryMockRecorder) Delete(ctx, apartmentID, status, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRepository)(nil).Delete), ctx, apartmentID, status, reason)
+}
+
+// Edit mocks base method.
+func (m *MockRepository) Edit(ctx context.Context, apartment model.Apartment, expectedStatus string) (model.Apartment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Edit", ctx, apartment, expectedStatus)
+	ret0, _ := ret[0].(model.Apartment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Edit indicates an expected call of Edit.
+func (mr *MockRepositoryMockRecorder) Edit(ctx, apartment, expectedStatus any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Edit", reflect.TypeOf((*MockRepository)(nil).Edit), ctx, apartment, expectedStatus)
+}
+
+// NextForModeration mocks base method.
+func (m *MockRepository) NextForModeration(ctx context.Context, moderatorID int64, filter model.ModerationQueueFilter) (model.Apartment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NextForModeration", ctx, moderatorID, filter)
+	ret0, _ := ret[0].(model.Apartment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NextForModeration indicates an expected call of NextForModeration.
+func (mr *MockRepositoryMockRecorder) NextForModeration(ctx, moderatorID, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NextForModeration", reflect.TypeOf((*MockRepository)(nil).NextForModeration), ctx, moderatorID, filter)
+}
+
+// OwnerApartments mocks base method.
+func (m *MockRepository) OwnerApartments(ctx context.Context, ownerID int64) ([]model.Apartment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OwnerApartments", ctx, ownerID)
+	ret0, _ := ret[0].([]model.Apartment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OwnerApartments indicates an expected call of OwnerApartments.
+func (mr *MockRepositoryMockRecorder) OwnerApartments(ctx, ownerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OwnerApartments", reflect.TypeOf((*MockRepository)(nil).OwnerApartments), ctx, ownerID)
+}
+
+// ReleaseStaleClaims mocks base method.
+func (m *MockRepository) ReleaseStaleClaims(ctx context.Context, timeout time.Duration) ([]model.Apartment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseStaleClaims", ctx, timeout)
+	ret0, _ := ret[0].([]model.Apartment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReleaseStaleClaims indicates an expected call of ReleaseStaleClaims.
+func (mr *MockRepositoryMockRecorder) ReleaseStaleClaims(ctx, timeout any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseStaleClaims", reflect.TypeOf((*MockRepository)(nil).ReleaseStaleClaims), ctx, timeout)
+}
+
+// Search mocks base method.
+func (m *MockRepository) Search(ctx context.Context, criteria model.ApartmentSearchCriteria) ([]model.ApartmentWithHouse, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, criteria)
+	ret0, _ := ret[0].([]model.ApartmentWithHouse)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockRepositoryMockRecorder) Search(ctx, criteria any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockRepository)(nil).Search), ctx, criteria)
+}
+
 // Update mocks base method.
-func (m *MockRepository) Update(ctx context.Context, apartment model.Apartment) error {
+func (m *MockRepository) Update(ctx context.Context, apartment model.Apartment, expectedStatus string, moderatorID int64) (model.Apartment, bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", ctx, apartment)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "Update", ctx, apartment, expectedStatus, moderatorID)
+	ret0, _ := ret[0].(model.Apartment)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockRepositoryMockRecorder) Update(ctx, apartment any) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) Update(ctx, apartment, expectedStatus, moderatorID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRepository)(nil).Update), ctx, apartment)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRepository)(nil).Update), ctx, apartment, expectedStatus, moderatorID)
 }
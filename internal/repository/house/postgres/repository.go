@@ -5,6 +5,7 @@ import (
 	houserepository "avito/internal/repository/house"
 	houserepositoryconverter "avito/internal/repository/house/converter"
 	houserepositorymodel "avito/internal/repository/house/model"
+	"avito/pkg/database"
 	"avito/pkg/logger"
 	"context"
 	"database/sql"
@@ -14,68 +15,69 @@ import (
 	"log/slog"
 )
 
-const (
-	postgresDriverName = "postgres"
-)
-
 type repository struct {
-	db     *sql.DB
+	db     *database.DB
 	logger *slog.Logger
 }
 
-func (r *repository) Create(ctx context.Context, house model.House) error {
+func (r *repository) Create(ctx context.Context, house model.House) (model.House, error) {
 	houseRepoModel := houserepositoryconverter.ToHouseRepModel(house)
 
 	l := logger.EndToEndLogging(ctx, r.logger)
 
 	q := `INSERT INTO houses (
-                    house_id,
                     address,
                     year,
-                    developer) VALUES ($1, $2, $3, $4)`
-	stmt, err := r.db.Prepare(q)
+                    developer) VALUES ($1, $2, $3)
+                    RETURNING house_id, created_at`
+	stmt, release, err := r.db.Statement(ctx, q)
 	if err != nil {
 		l.Error("Failed to prepare statement for create house", "error", err.Error())
-		return houserepository.ErrInternal
+		return model.House{}, houserepository.ErrInternal
 	}
-	defer stmt.Close()
+	defer release()
 
-	if _, err = stmt.ExecContext(ctx,
-		houseRepoModel.HouseId,
+	if err = stmt.QueryRowContext(ctx,
 		houseRepoModel.Address,
 		houseRepoModel.Year,
-		houseRepoModel.Developer); err != nil {
+		houseRepoModel.Developer).Scan(&houseRepoModel.HouseId, &houseRepoModel.CreatedAt); err != nil {
 		l.Error("Failed to create house", "error", err.Error())
 
 		var pgerr *pq.Error
 		if errors.As(err, &pgerr) {
 			switch {
 			case pgerr.Code == pgerrcode.UniqueViolation:
-				return houserepository.ErrHouseAlreadyExists
+				return model.House{}, houserepository.ErrHouseAlreadyExists
 			}
 		}
 
-		return houserepository.ErrInternal
+		return model.House{}, houserepository.ErrInternal
 	}
 
-	return nil
+	return houserepositoryconverter.ToHouseDto(houseRepoModel), nil
 }
 
-func (r *repository) Houses(ctx context.Context, offset int, limit int) ([]model.House, error) {
-	houses := make([]model.House, 0, limit)
+func (r *repository) Houses(ctx context.Context, page model.Page) ([]model.House, error) {
+	houses := make([]model.House, 0, page.Limit)
 
 	l := logger.EndToEndLogging(ctx, r.logger)
 
-	q := "SELECT house_id, address, year, developer, created_at, last_apartment_added_at FROM houses OFFSET $1 LIMIT $2"
+	//A CURSOR REPLACES THE OFFSET, THE ROWS BEFORE IT ARE NEVER SCANNED
+	afterID, offset := int64(0), page.Offset
+	if page.After != nil {
+		afterID, offset = page.After.ID, 0
+	}
+
+	q := "SELECT house_id, address, year, developer, created_at, last_apartment_added_at FROM houses WHERE house_id > $1 ORDER BY house_id OFFSET $2 LIMIT $3"
 
-	stmt, err := r.db.Prepare(q)
+	stmt, release, err := r.db.Statement(ctx, q)
 	if err != nil {
 		l.Error("Failed to prepare statement for get houses list", "error", err.Error())
 		return nil, houserepository.ErrInternal
 	}
-	defer stmt.Close()
+	defer release()
 
-	rows, err := stmt.QueryContext(ctx, offset, limit)
+	rows, err := stmt.QueryContext(ctx, afterID, offset, page.Limit)
 	if err != nil {
 		l.Error("Failed to get houses list", "error", err.Error())
 		return nil, houserepository.ErrInternal
@@ -104,27 +106,39 @@ func (r *repository) Houses(ctx context.Context, offset int, limit int) ([]model
 	return houses, nil
 }
 
-func (r *repository) CloseConnection() error {
-	return r.db.Close()
-}
+func (r *repository) House(ctx context.Context, houseID int64) (model.House, error) {
+	l := logger.EndToEndLogging(ctx, r.logger)
 
-func New(dataSourceName string, logger *slog.Logger) (houserepository.Repository, error) {
-	r := &repository{
-		logger: logger,
-	}
+	q := "SELECT house_id, address, year, developer, created_at, last_apartment_added_at FROM houses WHERE house_id = $1"
 
-	db, err := sql.Open(postgresDriverName, dataSourceName)
+	stmt, release, err := r.db.Statement(ctx, q)
 	if err != nil {
-		logger.Error("failed to open postgres database connection", "error", err.Error())
-		return nil, err
+		l.Error("Failed to prepare statement for get house", "error", err.Error())
+		return model.House{}, houserepository.ErrInternal
 	}
+	defer release()
+
+	house := houserepositorymodel.House{}
+	if err = stmt.QueryRowContext(ctx, houseID).Scan(&house.HouseId,
+		&house.Address,
+		&house.Year,
+		&house.Developer,
+		&house.CreatedAt,
+		&house.LastApartmentAddedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.House{}, houserepository.ErrHouseNotFound
+		}
 
-	if err = db.Ping(); err != nil {
-		logger.Error("failed to ping postgres database connection", "error", err.Error())
-		return nil, err
+		l.Error("Failed to get house", "error", err.Error())
+		return model.House{}, houserepository.ErrInternal
 	}
 
-	r.db = db
+	return houserepositoryconverter.ToHouseDto(house), nil
+}
 
-	return r, nil
+func New(db *database.DB, logger *slog.Logger) houserepository.Repository {
+	return &repository{
+		db:     db,
+		logger: logger,
+	}
 }
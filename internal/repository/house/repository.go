@@ -6,7 +6,7 @@ import (
 )
 
 type Repository interface {
-	Create(ctx context.Context, house model.House) error
-	Houses(ctx context.Context, offset int, limit int) ([]model.House, error)
-	CloseConnection() error
+	Create(ctx context.Context, house model.House) (model.House, error)
+	Houses(ctx context.Context, page model.Page) ([]model.House, error)
+	House(ctx context.Context, houseID int64) (model.House, error)
 }
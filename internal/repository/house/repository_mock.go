@@ -40,45 +40,47 @@ func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
 	return m.recorder
 }
 
-// CloseConnection mocks base method.
-func (m *MockRepository) CloseConnection() error {
+// Create mocks base method.
+func (m *MockRepository) Create(ctx context.Context, house model.House) (model.House, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CloseConnection")
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "Create", ctx, house)
+	ret0, _ := ret[0].(model.House)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// CloseConnection indicates an expected call of CloseConnection.
-func (mr *MockRepositoryMockRecorder) CloseConnection() *gomock.Call {
+// Create indicates an expected call of Create.
+func (mr *MockRepositoryMockRecorder) Create(ctx, house any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseConnection", reflect.TypeOf((*MockRepository)(nil).CloseConnection))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRepository)(nil).Create), ctx, house)
 }
 
-// Create mocks base method.
-func (m *MockRepository) Create(ctx context.Context, house model.House) error {
+// House mocks base method.
+func (m *MockRepository) House(ctx context.Context, houseID int64) (model.House, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", ctx, house)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "House", ctx, houseID)
+	ret0, _ := ret[0].(model.House)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// Create indicates an expected call of Create.
-func (mr *MockRepositoryMockRecorder) Create(ctx, house any) *gomock.Call {
+// House indicates an expected call of House.
+func (mr *MockRepositoryMockRecorder) House(ctx, houseID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRepository)(nil).Create), ctx, house)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "House", reflect.TypeOf((*MockRepository)(nil).House), ctx, houseID)
 }
 
 // Houses mocks base method.
-func (m *MockRepository) Houses(ctx context.Context, offset, limit int) ([]model.House, error) {
+func (m *MockRepository) Houses(ctx context.Context, page model.Page) ([]model.House, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Houses", ctx, offset, limit)
+	ret := m.ctrl.Call(m, "Houses", ctx, page)
 	ret0, _ := ret[0].([]model.House)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Houses indicates an expected call of Houses.
-func (mr *MockRepositoryMockRecorder) Houses(ctx, offset, limit any) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) Houses(ctx, page any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Houses", reflect.TypeOf((*MockRepository)(nil).Houses), ctx, offset, limit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Houses", reflect.TypeOf((*MockRepository)(nil).Houses), ctx, page)
 }
@@ -11,5 +11,12 @@ func ToSessionRepModel(session model.Session) sessionrepositorymodel.Session {
 		UserID:           session.UserID,
 		HashRefreshToken: session.HashRefreshToken,
 		ExpiresAt:        session.ExpiresAt,
+		UserAgent:        session.UserAgent,
+		IP:               session.IP,
+		CreatedAt:        session.CreatedAt,
+		LastUsedAt:       session.LastUsedAt,
+
+		AccessTokenID:        session.AccessTokenID,
+		AccessTokenExpiresAt: session.AccessTokenExpiresAt,
 	}
 }
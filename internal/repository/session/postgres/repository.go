@@ -5,142 +5,249 @@ import (
 	sessionrepository "avito/internal/repository/session"
 	sessionrepositoryconverter "avito/internal/repository/session/converter"
 	sessionrepositorymodel "avito/internal/repository/session/model"
+	"avito/pkg/database"
 	"avito/pkg/logger"
 	"context"
 	"database/sql"
 	"errors"
-	_ "github.com/lib/pq"
 	"log/slog"
+	"time"
 )
 
 const (
-	postgresDriverName = "postgres"
+	sessionColumns = "session_id, user_id, hash_refresh_token, expires_at, user_agent, ip, created_at, last_used_at, access_token_id, access_token_expires_at"
 )
 
-type repository struct {
-	db *sql.DB
+type scanner interface {
+	Scan(dest ...any) error
+}
 
-	logger *slog.Logger
+// scanSession reads a row selected with sessionColumns.
+func scanSession(row scanner) (sessionrepositorymodel.Session, error) {
+	session := sessionrepositorymodel.Session{}
+	err := row.Scan(
+		&session.SessionID,
+		&session.UserID,
+		&session.HashRefreshToken,
+		&session.ExpiresAt,
+		&session.UserAgent,
+		&session.IP,
+		&session.CreatedAt,
+		&session.LastUsedAt,
+		&session.AccessTokenID,
+		&session.AccessTokenExpiresAt)
+
+	return session, err
 }
 
-func (r *repository) CheckSessionByUserId(ctx context.Context, userID uint32) (exists bool, err error) {
-	l := logger.EndToEndLogging(ctx, r.logger)
+func scanSessions(rows *sql.Rows) ([]model.Session, error) {
+	defer rows.Close()
 
-	q := "SELECT EXISTS(SELECT 1 FROM sessions WHERE user_id = $1)"
-	stmt, err := r.db.Prepare(q)
-	if err != nil {
-		l.Error("Failed to check for session existence", "error", err.Error())
-		return false, sessionrepository.ErrInternal
-	}
-	defer stmt.Close()
+	sessions := make([]model.Session, 0)
+	for rows.Next() {
+		session, err := scanSession(rows)
+		if err != nil {
+			return nil, err
+		}
 
-	if err = stmt.QueryRowContext(ctx, userID).Scan(&exists); err != nil {
-		l.Error("Failed to check for session existence", "error", err.Error())
-		return false, sessionrepository.ErrInternal
+		sessions = append(sessions, sessionrepositoryconverter.ToSessionDTO(session))
 	}
 
-	return exists, nil
+	return sessions, rows.Err()
 }
 
-func (r *repository) SessionByUserId(ctx context.Context, userID uint32) (session model.Session, err error) {
+type repository struct {
+	db *database.DB
+
+	logger *slog.Logger
+}
+
+func (r *repository) session(ctx context.Context, q string, args ...any) (model.Session, error) {
 	l := logger.EndToEndLogging(ctx, r.logger)
 
-	q := "SELECT session_id, user_id, hash_refresh_token, expires_at FROM sessions WHERE user_id = $1"
-	stmt, err := r.db.Prepare(q)
+	stmt, release, err := r.db.Statement(ctx, q)
 	if err != nil {
-		l.Error("Failed to check for session existence", "error", err.Error())
+		l.Error("Failed to prepare statement for session", "error", err.Error())
 		return model.Session{}, sessionrepository.ErrInternal
 	}
-	defer stmt.Close()
-
-	sessionRepModel := sessionrepositorymodel.Session{}
+	defer release()
 
-	if err = stmt.QueryRowContext(ctx, userID).Scan(
-		&sessionRepModel.SessionID,
-		&sessionRepModel.UserID,
-		&sessionRepModel.HashRefreshToken,
-		&sessionRepModel.ExpiresAt); err != nil {
-		l.Error("Failed to check for session existence", "error", err.Error())
+	sessionRepModel, err := scanSession(stmt.QueryRowContext(ctx, args...))
+	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return model.Session{}, sessionrepository.ErrNoSession
 		}
 
+		l.Error("Failed to get session", "error", err.Error())
 		return model.Session{}, sessionrepository.ErrInternal
 	}
 
 	return sessionrepositoryconverter.ToSessionDTO(sessionRepModel), nil
 }
 
-func (r *repository) Create(ctx context.Context, session model.Session) error {
+func (r *repository) SessionByRefreshToken(ctx context.Context, hashRefreshToken string) (model.Session, error) {
+	q := "SELECT " + sessionColumns + " FROM sessions WHERE hash_refresh_token = $1"
+	return r.session(ctx, q, hashRefreshToken)
+}
+
+// SessionByUsedRefreshToken returns the session the token was rotated out of.
+func (r *repository) SessionByUsedRefreshToken(ctx context.Context, hashRefreshToken string) (model.Session, error) {
+	q := `SELECT ` + sessionColumns + ` FROM sessions
+		WHERE session_id = (SELECT session_id FROM used_refresh_tokens WHERE hash_refresh_token = $1)`
+	return r.session(ctx, q, hashRefreshToken)
+}
+
+func (r *repository) sessions(ctx context.Context, q string, args ...any) ([]model.Session, error) {
+	l := logger.EndToEndLogging(ctx, r.logger)
+
+	stmt, release, err := r.db.Statement(ctx, q)
+	if err != nil {
+		l.Error("Failed to prepare statement for sessions", "error", err.Error())
+		return nil, sessionrepository.ErrInternal
+	}
+	defer release()
+
+	rows, err := stmt.QueryContext(ctx, args...)
+	if err != nil {
+		l.Error("Failed to get sessions", "error", err.Error())
+		return nil, sessionrepository.ErrInternal
+	}
+
+	sessions, err := scanSessions(rows)
+	if err != nil {
+		l.Error("Failed to get sessions", "error", err.Error())
+		return nil, sessionrepository.ErrInternal
+	}
+
+	return sessions, nil
+}
+
+func (r *repository) SessionsByUserID(ctx context.Context, userID int64) ([]model.Session, error) {
+	q := "SELECT " + sessionColumns + " FROM sessions WHERE user_id = $1 ORDER BY created_at, session_id"
+	return r.sessions(ctx, q, userID)
+}
+
+func (r *repository) Create(ctx context.Context, session model.Session) (model.Session, error) {
 	sessionRepModel := sessionrepositoryconverter.ToSessionRepModel(session)
 
 	l := logger.EndToEndLogging(ctx, r.logger)
 
-	q := "INSERT INTO sessions (session_id, user_id, hash_refresh_token, expires_at) VALUES ($1, $2, $3, $4)"
-	stmt, err := r.db.Prepare(q)
+	q := "INSERT INTO sessions (user_id, hash_refresh_token, expires_at, user_agent, ip) VALUES ($1, $2, $3, $4, $5) RETURNING " + sessionColumns
+	stmt, release, err := r.db.Statement(ctx, q)
 	if err != nil {
 		l.Error("Failed to prepare statement for save session", "error", err.Error())
-		return sessionrepository.ErrInternal
+		return model.Session{}, sessionrepository.ErrInternal
 	}
-	defer stmt.Close()
+	defer release()
 
-	_, err = stmt.ExecContext(ctx,
-		sessionRepModel.SessionID,
+	sessionRepModel, err = scanSession(stmt.QueryRowContext(ctx,
 		sessionRepModel.UserID,
 		sessionRepModel.HashRefreshToken,
-		sessionRepModel.ExpiresAt)
+		sessionRepModel.ExpiresAt,
+		sessionRepModel.UserAgent,
+		sessionRepModel.IP))
 	if err != nil {
 		l.Error("Failed to save session", "error", err.Error())
+		return model.Session{}, sessionrepository.ErrInternal
+	}
+
+	return sessionrepositoryconverter.ToSessionDTO(sessionRepModel), nil
+}
+
+// BindAccessToken remembers the access token issued for the session, so it can be revoked with it.
+func (r *repository) BindAccessToken(ctx context.Context, sessionID int64, accessTokenID string, accessTokenExpiresAt time.Time) error {
+	l := logger.EndToEndLogging(ctx, r.logger)
+
+	q := "UPDATE sessions SET access_token_id = $1, access_token_expires_at = $2 WHERE session_id = $3"
+	stmt, release, err := r.db.Statement(ctx, q)
+	if err != nil {
+		l.Error("Failed to prepare statement for bind access token", "error", err.Error())
+		return sessionrepository.ErrInternal
+	}
+	defer release()
+
+	if _, err = stmt.ExecContext(ctx, accessTokenID, accessTokenExpiresAt, sessionID); err != nil {
+		l.Error("Failed to bind access token", "error", err.Error())
 		return sessionrepository.ErrInternal
 	}
 
 	return nil
 }
 
-func (r *repository) ResetSession(ctx context.Context, session model.Session) error {
+// Rotate replaces the refresh token of the session and remembers the previous one as used.
+// Only one of concurrent rotations of the same token succeeds, the others get ErrNoSession.
+func (r *repository) Rotate(ctx context.Context, session model.Session, usedHashRefreshToken string) error {
 	sessionRepModel := sessionrepositoryconverter.ToSessionRepModel(session)
 
 	l := logger.EndToEndLogging(ctx, r.logger)
 
-	q := "UPDATE sessions SET hash_refresh_token = $1, expires_at = $2 WHERE user_id = $3"
-	stmt, err := r.db.Prepare(q)
+	q := `WITH rotated AS (
+			UPDATE sessions SET hash_refresh_token = $1, expires_at = $2, last_used_at = NOW(),
+				access_token_id = $5, access_token_expires_at = $6
+			WHERE session_id = $3 AND hash_refresh_token = $4
+			RETURNING session_id
+		)
+		INSERT INTO used_refresh_tokens (hash_refresh_token, session_id) SELECT $4, session_id FROM rotated`
+	stmt, release, err := r.db.Statement(ctx, q)
 	if err != nil {
-		l.Error("Failed to prepare statement for session reset", "error", err.Error())
+		l.Error("Failed to prepare statement for session rotation", "error", err.Error())
 		return sessionrepository.ErrInternal
 	}
-	defer stmt.Close()
+	defer release()
 
-	if _, err = stmt.ExecContext(ctx,
+	res, err := stmt.ExecContext(ctx,
 		sessionRepModel.HashRefreshToken,
 		sessionRepModel.ExpiresAt,
-		sessionRepModel.UserID); err != nil {
-		l.Error("Failed to reset session", "error", err.Error())
+		sessionRepModel.SessionID,
+		usedHashRefreshToken,
+		sessionRepModel.AccessTokenID,
+		sessionRepModel.AccessTokenExpiresAt)
+	if err != nil {
+		l.Error("Failed to rotate session", "error", err.Error())
 		return sessionrepository.ErrInternal
 	}
 
+	rows, err := res.RowsAffected()
+	if err != nil {
+		l.Error("Failed to get affected rows", "error", err.Error())
+		return sessionrepository.ErrInternal
+	}
+
+	if rows == 0 {
+		return sessionrepository.ErrNoSession
+	}
+
 	return nil
 }
 
-func (r *repository) CloseConnection() error {
-	return r.db.Close()
+// Delete removes the session of the user, the used tokens of its family go with it.
+// The removed session is returned for its access token to be revoked.
+func (r *repository) Delete(ctx context.Context, userID, sessionID int64) (model.Session, error) {
+	q := "DELETE FROM sessions WHERE session_id = $1 AND user_id = $2 RETURNING " + sessionColumns
+	return r.session(ctx, q, sessionID, userID)
 }
 
-func New(logger *slog.Logger, dataSourceName string) (sessionrepository.Repository, error) {
-	r := &repository{
-		logger: logger,
-	}
-	db, err := sql.Open(postgresDriverName, dataSourceName)
-	if err != nil {
-		logger.Error("failed to open postgres database connection", "error", err.Error())
-		return nil, err
-	}
-
-	if err = db.Ping(); err != nil {
-		logger.Error("failed to ping postgres database connection", "error", err.Error())
-		return nil, err
-	}
+func (r *repository) DeleteByUserID(ctx context.Context, userID int64) ([]model.Session, error) {
+	q := "DELETE FROM sessions WHERE user_id = $1 RETURNING " + sessionColumns
+	return r.sessions(ctx, q, userID)
+}
 
-	r.db = db
+// DeleteExpired skips the rows locked by a concurrent refresh or by the reaper of another instance.
+func (r *repository) DeleteExpired(ctx context.Context, before time.Time, limit int) ([]model.Session, error) {
+	q := `DELETE FROM sessions s USING (
+				SELECT session_id AS expired_id FROM sessions
+				WHERE expires_at < $1
+				ORDER BY expires_at
+				LIMIT $2
+				FOR UPDATE SKIP LOCKED) expired
+			WHERE s.session_id = expired.expired_id
+			RETURNING ` + sessionColumns
+	return r.sessions(ctx, q, before, limit)
+}
 
-	return r, nil
+func New(db *database.DB, logger *slog.Logger) sessionrepository.Repository {
+	return &repository{
+		db:     db,
+		logger: logger,
+	}
 }
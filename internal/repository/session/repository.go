@@ -3,12 +3,19 @@ package sessionrepository
 import (
 	"avito/internal/model"
 	"context"
+	"time"
 )
 
 type Repository interface {
-	Create(ctx context.Context, session model.Session) error
-	SessionByUserId(ctx context.Context, userID uint32) (session model.Session, err error)
-	CheckSessionByUserId(ctx context.Context, userID uint32) (bool, error)
-	ResetSession(ctx context.Context, session model.Session) error
-	CloseConnection() error
+	Create(ctx context.Context, session model.Session) (model.Session, error)
+	SessionByRefreshToken(ctx context.Context, hashRefreshToken string) (model.Session, error)
+	SessionByUsedRefreshToken(ctx context.Context, hashRefreshToken string) (model.Session, error)
+	SessionsByUserID(ctx context.Context, userID int64) ([]model.Session, error)
+	BindAccessToken(ctx context.Context, sessionID int64, accessTokenID string, accessTokenExpiresAt time.Time) error
+	Rotate(ctx context.Context, session model.Session, usedHashRefreshToken string) error
+	Delete(ctx context.Context, userID, sessionID int64) (model.Session, error)
+	DeleteByUserID(ctx context.Context, userID int64) ([]model.Session, error)
+	// DeleteExpired deletes up to limit sessions that expired before the given time,
+	// their used refresh tokens are deleted with them.
+	DeleteExpired(ctx context.Context, before time.Time, limit int) ([]model.Session, error)
 }
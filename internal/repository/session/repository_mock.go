@@ -13,6 +13,7 @@ import (
 	model "avito/internal/model"
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "go.uber.org/mock/gomock"
 )
@@ -40,74 +41,135 @@ func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
 	return m.recorder
 }
 
-// CheckSessionByUserId mocks base method.
-func (m *MockRepository) CheckSessionByUserId(ctx context.Context, userID uint32) (bool, error) {
+// BindAccessToken mocks base method.
+func (m *MockRepository) BindAccessToken(ctx context.Context, sessionID int64, accessTokenID string, accessTokenExpiresAt time.Time) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CheckSessionByUserId", ctx, userID)
-	ret0, _ := ret[0].(bool)
+	ret := m.ctrl.Call(m, "BindAccessToken", ctx, sessionID, accessTokenID, accessTokenExpiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BindAccessToken indicates an expected call of BindAccessToken.
+func (mr *MockRepositoryMockRecorder) BindAccessToken(ctx, sessionID, accessTokenID, accessTokenExpiresAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BindAccessToken", reflect.TypeOf((*MockRepository)(nil).BindAccessToken), ctx, sessionID, accessTokenID, accessTokenExpiresAt)
+}
+
+// Create mocks base method.
+func (m *MockRepository) Create(ctx context.Context, session model.Session) (model.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, session)
+	ret0, _ := ret[0].(model.Session)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CheckSessionByUserId indicates an expected call of CheckSessionByUserId.
-func (mr *MockRepositoryMockRecorder) CheckSessionByUserId(ctx, userID any) *gomock.Call {
+// Create indicates an expected call of Create.
+func (mr *MockRepositoryMockRecorder) Create(ctx, session any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckSessionByUserId", reflect.TypeOf((*MockRepository)(nil).CheckSessionByUserId), ctx, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRepository)(nil).Create), ctx, session)
 }
 
-// CloseConnection mocks base method.
-func (m *MockRepository) CloseConnection() error {
+// Delete mocks base method.
+func (m *MockRepository) Delete(ctx context.Context, userID, sessionID int64) (model.Session, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CloseConnection")
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "Delete", ctx, userID, sessionID)
+	ret0, _ := ret[0].(model.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// CloseConnection indicates an expected call of CloseConnection.
-func (mr *MockRepositoryMockRecorder) CloseConnection() *gomock.Call {
+// Delete indicates an expected call of Delete.
+func (mr *MockRepositoryMockRecorder) Delete(ctx, userID, sessionID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseConnection", reflect.TypeOf((*MockRepository)(nil).CloseConnection))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRepository)(nil).Delete), ctx, userID, sessionID)
 }
 
-// Create mocks base method.
-func (m *MockRepository) Create(ctx context.Context, session model.Session) error {
+// DeleteByUserID mocks base method.
+func (m *MockRepository) DeleteByUserID(ctx context.Context, userID int64) ([]model.Session, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", ctx, session)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "DeleteByUserID", ctx, userID)
+	ret0, _ := ret[0].([]model.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// Create indicates an expected call of Create.
-func (mr *MockRepositoryMockRecorder) Create(ctx, session any) *gomock.Call {
+// DeleteByUserID indicates an expected call of DeleteByUserID.
+func (mr *MockRepositoryMockRecorder) DeleteByUserID(ctx, userID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRepository)(nil).Create), ctx, session)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByUserID", reflect.TypeOf((*MockRepository)(nil).DeleteByUserID), ctx, userID)
 }
 
-// ResetSession mocks base method.
-func (m *MockRepository) ResetSession(ctx context.Context, session model.Session) error {
+// DeleteExpired mocks base method.
+func (m *MockRepository) DeleteExpired(ctx context.Context, before time.Time, limit int) ([]model.Session, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ResetSession", ctx, session)
+	ret := m.ctrl.Call(m, "DeleteExpired", ctx, before, limit)
+	ret0, _ := ret[0].([]model.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpired indicates an expected call of DeleteExpired.
+func (mr *MockRepositoryMockRecorder) DeleteExpired(ctx, before, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpired", reflect.TypeOf((*MockRepository)(nil).DeleteExpired), ctx, before, limit)
+}
+
+// Rotate mocks base method.
+func (m *MockRepository) Rotate(ctx context.Context, session model.Session, usedHashRefreshToken string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rotate", ctx, session, usedHashRefreshToken)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// ResetSession indicates an expected call of ResetSession.
-func (mr *MockRepositoryMockRecorder) ResetSession(ctx, session any) *gomock.Call {
+// Rotate indicates an expected call of Rotate.
+func (mr *MockRepositoryMockRecorder) Rotate(ctx, session, usedHashRefreshToken any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rotate", reflect.TypeOf((*MockRepository)(nil).Rotate), ctx, session, usedHashRefreshToken)
+}
+
+// SessionByRefreshToken mocks base method.
+func (m *MockRepository) SessionByRefreshToken(ctx context.Context, hashRefreshToken string) (model.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SessionByRefreshToken", ctx, hashRefreshToken)
+	ret0, _ := ret[0].(model.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SessionByRefreshToken indicates an expected call of SessionByRefreshToken.
+func (mr *MockRepositoryMockRecorder) SessionByRefreshToken(ctx, hashRefreshToken any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetSession", reflect.TypeOf((*MockRepository)(nil).ResetSession), ctx, session)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SessionByRefreshToken", reflect.TypeOf((*MockRepository)(nil).SessionByRefreshToken), ctx, hashRefreshToken)
 }
 
-// SessionByUserId mocks base method.
-func (m *MockRepository) SessionByUserId(ctx context.Context, userID uint32) (model.Session, error) {
+// SessionByUsedRefreshToken mocks base method.
+func (m *MockRepository) SessionByUsedRefreshToken(ctx context.Context, hashRefreshToken string) (model.Session, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SessionByUserId", ctx, userID)
+	ret := m.ctrl.Call(m, "SessionByUsedRefreshToken", ctx, hashRefreshToken)
 	ret0, _ := ret[0].(model.Session)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// SessionByUserId indicates an expected call of SessionByUserId.
-func (mr *MockRepositoryMockRecorder) SessionByUserId(ctx, userID any) *gomock.Call {
+// SessionByUsedRefreshToken indicates an expected call of SessionByUsedRefreshToken.
+func (mr *MockRepositoryMockRecorder) SessionByUsedRefreshToken(ctx, hashRefreshToken any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SessionByUsedRefreshToken", reflect.TypeOf((*MockRepository)(nil).SessionByUsedRefreshToken), ctx, hashRefreshToken)
+}
+
+// SessionsByUserID mocks base method.
+func (m *MockRepository) SessionsByUserID(ctx context.Context, userID int64) ([]model.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SessionsByUserID", ctx, userID)
+	ret0, _ := ret[0].([]model.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SessionsByUserID indicates an expected call of SessionsByUserID.
+func (mr *MockRepositoryMockRecorder) SessionsByUserID(ctx, userID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SessionByUserId", reflect.TypeOf((*MockRepository)(nil).SessionByUserId), ctx, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SessionsByUserID", reflect.TypeOf((*MockRepository)(nil).SessionsByUserID), ctx, userID)
 }
@@ -0,0 +1,15 @@
+package subscriptionrepositoryconverter
+
+import (
+	"avito/internal/model"
+	subscriptionrepositorymodel "avito/internal/repository/subscription/model"
+)
+
+func ToSubscriptionDTO(subscription subscriptionrepositorymodel.Subscription) model.Subscription {
+	return model.Subscription{
+		SubscriptionID: subscription.SubscriptionID,
+		HouseID:        subscription.HouseID,
+		Email:          subscription.Email,
+		CreatedAt:      subscription.CreatedAt,
+	}
+}
@@ -0,0 +1,17 @@
+package subscriptionrepositoryconverter
+
+import (
+	subscriptionrepositorymodel "avito/internal/repository/subscription/model"
+	"testing"
+)
+
+func BenchmarkToSubscriptionDTO(b *testing.B) {
+	b.ReportAllocs()
+
+	subscription := subscriptionrepositorymodel.Subscription{}
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		ToSubscriptionDTO(subscription)
+	}
+}
@@ -0,0 +1,15 @@
+package subscriptionrepositoryconverter
+
+import (
+	"avito/internal/model"
+	subscriptionrepositorymodel "avito/internal/repository/subscription/model"
+)
+
+func ToSubscriptionRepModel(subscription model.Subscription) subscriptionrepositorymodel.Subscription {
+	return subscriptionrepositorymodel.Subscription{
+		SubscriptionID: subscription.SubscriptionID,
+		HouseID:        subscription.HouseID,
+		Email:          subscription.Email,
+		CreatedAt:      subscription.CreatedAt,
+	}
+}
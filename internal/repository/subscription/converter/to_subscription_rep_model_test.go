@@ -0,0 +1,17 @@
+package subscriptionrepositoryconverter
+
+import (
+	"avito/internal/model"
+	"testing"
+)
+
+func BenchmarkToSubscriptionRepModel(b *testing.B) {
+	b.ReportAllocs()
+
+	subscription := model.Subscription{}
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		ToSubscriptionRepModel(subscription)
+	}
+}
@@ -0,0 +1,8 @@
+package subscriptionrepository
+
+import "errors"
+
+var (
+	ErrInternal      = errors.New("internal error")
+	ErrHouseNotFound = errors.New("house not found")
+)
@@ -0,0 +1,106 @@
+package subscriptionrepositorypostgres
+
+import (
+	"avito/internal/model"
+	subscriptionrepository "avito/internal/repository/subscription"
+	subscriptionrepositoryconverter "avito/internal/repository/subscription/converter"
+	subscriptionrepositorymodel "avito/internal/repository/subscription/model"
+	"avito/pkg/database"
+	"avito/pkg/logger"
+	"context"
+	"errors"
+	"github.com/jackc/pgerrcode"
+	"github.com/lib/pq"
+	"log/slog"
+)
+
+type repository struct {
+	db     *database.DB
+	logger *slog.Logger
+}
+
+// Create is idempotent, subscribing twice returns the existing subscription.
+// The no-op update makes RETURNING yield the row on conflict too.
+func (r *repository) Create(ctx context.Context, subscription model.Subscription) (model.Subscription, error) {
+	subscriptionRepModel := subscriptionrepositoryconverter.ToSubscriptionRepModel(subscription)
+
+	l := logger.EndToEndLogging(ctx, r.logger)
+
+	q := `INSERT INTO subscriptions (house_id, email) VALUES ($1, $2)
+			ON CONFLICT (house_id, email) DO UPDATE SET email = EXCLUDED.email
+			RETURNING subscription_id, created_at`
+	stmt, release, err := r.db.Statement(ctx, q)
+	if err != nil {
+		l.Error("Failed to prepare statement for create subscription", "error", err.Error())
+		return model.Subscription{}, subscriptionrepository.ErrInternal
+	}
+	defer release()
+
+	if err = stmt.QueryRowContext(ctx,
+		subscriptionRepModel.HouseID,
+		subscriptionRepModel.Email).Scan(&subscriptionRepModel.SubscriptionID, &subscriptionRepModel.CreatedAt); err != nil {
+		l.Error("Failed to create subscription", "error", err.Error())
+
+		var pgerr *pq.Error
+		if errors.As(err, &pgerr) {
+			switch {
+			case pgerr.Code == pgerrcode.ForeignKeyViolation:
+				return model.Subscription{}, subscriptionrepository.ErrHouseNotFound
+			}
+		}
+
+		return model.Subscription{}, subscriptionrepository.ErrInternal
+	}
+
+	return subscriptionrepositoryconverter.ToSubscriptionDTO(subscriptionRepModel), nil
+}
+
+func (r *repository) SubscriptionsByHouseID(ctx context.Context, houseID int64) ([]model.Subscription, error) {
+	subscriptions := make([]model.Subscription, 0)
+
+	l := logger.EndToEndLogging(ctx, r.logger)
+
+	q := "SELECT subscription_id, house_id, email, created_at FROM subscriptions WHERE house_id = $1"
+	stmt, release, err := r.db.Statement(ctx, q)
+	if err != nil {
+		l.Error("Failed to prepare statement for get subscriptions", "error", err.Error())
+		return nil, subscriptionrepository.ErrInternal
+	}
+	defer release()
+
+	rows, err := stmt.QueryContext(ctx, houseID)
+	if err != nil {
+		l.Error("Failed to get subscriptions", "error", err.Error())
+		return nil, subscriptionrepository.ErrInternal
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		subscription := subscriptionrepositorymodel.Subscription{}
+		if err = rows.Scan(
+			&subscription.SubscriptionID,
+			&subscription.HouseID,
+			&subscription.Email,
+			&subscription.CreatedAt); err != nil {
+			l.Error("Failed to get subscriptions", "error", err.Error())
+			return nil, subscriptionrepository.ErrInternal
+		}
+
+		subscriptions = append(subscriptions, subscriptionrepositoryconverter.ToSubscriptionDTO(subscription))
+	}
+
+	//A BROKEN RESULT SET WOULD SILENTLY LEAVE SUBSCRIBERS WITHOUT THE NOTIFICATION
+	if err = rows.Err(); err != nil {
+		l.Error("Failed to get subscriptions", "error", err.Error())
+		return nil, subscriptionrepository.ErrInternal
+	}
+
+	return subscriptions, nil
+}
+
+func New(db *database.DB, logger *slog.Logger) subscriptionrepository.Repository {
+	return &repository{
+		db:     db,
+		logger: logger,
+	}
+}
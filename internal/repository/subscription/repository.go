@@ -0,0 +1,11 @@
+package subscriptionrepository
+
+import (
+	"avito/internal/model"
+	"context"
+)
+
+type Repository interface {
+	Create(ctx context.Context, subscription model.Subscription) (model.Subscription, error)
+	SubscriptionsByHouseID(ctx context.Context, houseID int64) ([]model.Subscription, error)
+}
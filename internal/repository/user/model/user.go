@@ -1,7 +1,7 @@
 package userrepositorymodel
 
 type User struct {
-	ID           uint32
+	ID           int64
 	Role         string
 	Email        string
 	HashPassword string
@@ -5,6 +5,7 @@ import (
 	userrepository "avito/internal/repository/user"
 	userrepositoryconverter "avito/internal/repository/user/converter"
 	userrepositorymodel "avito/internal/repository/user/model"
+	"avito/pkg/database"
 	"avito/pkg/logger"
 	"context"
 	"database/sql"
@@ -14,58 +15,53 @@ import (
 	"log/slog"
 )
 
-const (
-	postgresDriverName = "postgres"
-)
-
 type repository struct {
-	db *sql.DB
+	db *database.DB
 
 	logger *slog.Logger
 }
 
-func (r *repository) Save(ctx context.Context, user model.User) error {
+func (r *repository) Save(ctx context.Context, user model.User) (model.User, error) {
 	userRepModel := userrepositoryconverter.ToUserRepModel(user)
 	l := logger.EndToEndLogging(ctx, r.logger)
 
-	q := "INSERT INTO users(user_id, role, email, hash_password) VALUES ($1, $2, $3, $4)"
-	stmt, err := r.db.Prepare(q)
+	q := "INSERT INTO users(role, email, hash_password) VALUES ($1, $2, $3) RETURNING user_id"
+	stmt, release, err := r.db.Statement(ctx, q)
 	if err != nil {
 		l.Error("Failed to prepare statement for save user", "error", err.Error())
-		return userrepository.ErrInternal
+		return model.User{}, userrepository.ErrInternal
 	}
-	defer stmt.Close()
+	defer release()
 
-	if _, err = stmt.ExecContext(ctx,
-		userRepModel.ID,
+	if err = stmt.QueryRowContext(ctx,
 		userRepModel.Role,
 		userRepModel.Email,
-		userRepModel.HashPassword); err != nil {
+		userRepModel.HashPassword).Scan(&userRepModel.ID); err != nil {
 		l.Error("Failed to save user", "error", err.Error())
 
 		var pgerr *pq.Error
 		if errors.As(err, &pgerr) {
 			switch {
 			case pgerr.Code == pgerrcode.UniqueViolation:
-				return userrepository.ErrEmailAlreadyTaken
+				return model.User{}, userrepository.ErrEmailAlreadyTaken
 			}
 		}
-		return userrepository.ErrInternal
+		return model.User{}, userrepository.ErrInternal
 	}
 
-	return nil
+	return userrepositoryconverter.ToUserDTO(userRepModel), nil
 }
 
 func (r *repository) UserByEmail(ctx context.Context, email string) (model.User, error) {
 	l := logger.EndToEndLogging(ctx, r.logger)
 
 	q := "SELECT user_id, role, email, hash_password FROM users WHERE email = $1"
-	stmt, err := r.db.Prepare(q)
+	stmt, release, err := r.db.Statement(ctx, q)
 	if err != nil {
 		l.Error("Failed to prepare statement for save user", "error", err.Error())
 		return model.User{}, userrepository.ErrInternal
 	}
-	defer stmt.Close()
+	defer release()
 
 	user := userrepositorymodel.User{}
 
@@ -87,27 +83,71 @@ func (r *repository) UserByEmail(ctx context.Context, email string) (model.User,
 	return userrepositoryconverter.ToUserDTO(user), nil
 }
 
-func (r *repository) CloseConnection() error {
-	return r.db.Close()
-}
+func (r *repository) UserByID(ctx context.Context, userID int64) (model.User, error) {
+	l := logger.EndToEndLogging(ctx, r.logger)
 
-func New(dataSourceName string, logger *slog.Logger) (userrepository.Repository, error) {
-	r := &repository{
-		logger: logger,
+	q := "SELECT user_id, role, email, hash_password FROM users WHERE user_id = $1"
+	stmt, release, err := r.db.Statement(ctx, q)
+	if err != nil {
+		l.Error("Failed to prepare statement for user by id", "error", err.Error())
+		return model.User{}, userrepository.ErrInternal
+	}
+	defer release()
+
+	user := userrepositorymodel.User{}
+
+	if err = stmt.QueryRowContext(ctx, userID).Scan(
+		&user.ID,
+		&user.Role,
+		&user.Email,
+		&user.HashPassword); err != nil {
+		l.Error("Failed to get user by id", "error", err.Error())
+
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return model.User{}, userrepository.ErrUserNotFound
+		default:
+			return model.User{}, userrepository.ErrInternal
+		}
 	}
 
-	db, err := sql.Open(postgresDriverName, dataSourceName)
+	return userrepositoryconverter.ToUserDTO(user), nil
+}
+
+func (r *repository) UpdateRole(ctx context.Context, userID int64, role string) (model.User, error) {
+	l := logger.EndToEndLogging(ctx, r.logger)
+
+	q := "UPDATE users SET role = $1 WHERE user_id = $2 RETURNING user_id, role, email, hash_password"
+	stmt, release, err := r.db.Statement(ctx, q)
 	if err != nil {
-		logger.Error("failed to open postgres database connection", "error", err.Error())
-		return nil, err
+		l.Error("Failed to prepare statement for update role", "error", err.Error())
+		return model.User{}, userrepository.ErrInternal
 	}
+	defer release()
 
-	if err = db.Ping(); err != nil {
-		logger.Error("failed to ping postgres database connection", "error", err.Error())
-		return nil, err
+	user := userrepositorymodel.User{}
+
+	if err = stmt.QueryRowContext(ctx, role, userID).Scan(
+		&user.ID,
+		&user.Role,
+		&user.Email,
+		&user.HashPassword); err != nil {
+		l.Error("Failed to update role", "error", err.Error())
+
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return model.User{}, userrepository.ErrUserNotFound
+		default:
+			return model.User{}, userrepository.ErrInternal
+		}
 	}
 
-	r.db = db
+	return userrepositoryconverter.ToUserDTO(user), nil
+}
 
-	return r, nil
+func New(db *database.DB, logger *slog.Logger) userrepository.Repository {
+	return &repository{
+		db:     db,
+		logger: logger,
+	}
 }
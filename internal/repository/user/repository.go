@@ -6,7 +6,8 @@ import (
 )
 
 type Repository interface {
-	Save(ctx context.Context, user model.User) error
+	Save(ctx context.Context, user model.User) (model.User, error)
 	UserByEmail(ctx context.Context, email string) (model.User, error)
-	CloseConnection() error
+	UserByID(ctx context.Context, userID int64) (model.User, error)
+	UpdateRole(ctx context.Context, userID int64, role string) (model.User, error)
 }
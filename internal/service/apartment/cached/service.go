@@ -0,0 +1,201 @@
+package apartmentservicecached
+
+import (
+	"avito/internal/model"
+	apartmentservice "avito/internal/service/apartment"
+	"avito/pkg/cache"
+	"avito/pkg/logger"
+	"context"
+	"fmt"
+	"golang.org/x/sync/singleflight"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// maxListsPerHouse bounds the filter and page variants kept for one house,
+// the house entry starts over once it's full.
+const maxListsPerHouse = 64
+
+// service is a read-through cache for the approved flats list of a house. Moderators see
+// every status and always go to the wrapped service. Entries are keyed by house, so any
+// write that touches a house drops all of its cached pages at once.
+type service struct {
+	apartmentservice.Service
+
+	//MU MAKES LOOKING UP AND REPLACING A HOUSE ENTRY ONE STEP, SO CONCURRENT MISSES SHARE THE ENTRY
+	mu    sync.Mutex
+	cache cache.Cache[int64, *HouseLists]
+	ttl   time.Duration
+
+	group singleflight.Group
+	seq   atomic.Uint64
+
+	logger *slog.Logger
+}
+
+type listKey struct {
+	offset   int
+	limit    int
+	after    model.Cursor
+	hasAfter bool
+	criteria model.ApartmentCriteria
+}
+
+type list struct {
+	apartments []model.Apartment
+	next       *model.Cursor
+}
+
+// HouseLists holds the cached pages of one house. Invalidation removes it from the cache,
+// a load that was in flight meanwhile stores into the detached entry and is lost.
+type HouseLists struct {
+	id        uint64
+	expiresAt time.Time
+
+	mu    sync.Mutex
+	lists map[listKey]list
+}
+
+func (h *HouseLists) get(key listKey) (list, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	l, ok := h.lists[key]
+	return l, ok
+}
+
+func (h *HouseLists) add(key listKey, l list) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.lists) >= maxListsPerHouse {
+		clear(h.lists)
+	}
+	h.lists[key] = l
+}
+
+func (s *service) Apartments(ctx context.Context, houseID int64, page model.Page, role string, criteria model.ApartmentCriteria) ([]model.Apartment, *model.Cursor, error) {
+	if role == apartmentservice.RoleModerator {
+		return s.Service.Apartments(ctx, houseID, page, role, criteria)
+	}
+
+	key := listKey{offset: page.Offset, limit: page.Limit, criteria: criteria}
+	if page.After != nil {
+		key.after, key.hasAfter = *page.After, true
+	}
+
+	house := s.house(houseID)
+	if l, ok := house.get(key); ok {
+		return l.apartments, l.next, nil
+	}
+
+	//CONCURRENT MISSES OF THE SAME PAGE SHARE ONE QUERY, A CALLER WHO HUNG UP MUSTN'T FAIL THE OTHERS
+	v, err, _ := s.group.Do(fmt.Sprintf("%d:%+v", house.id, key), func() (any, error) {
+		apartments, next, err := s.Service.Apartments(context.WithoutCancel(ctx), houseID, page, role, criteria)
+		if err != nil {
+			return nil, err
+		}
+
+		l := list{apartments: apartments, next: next}
+		house.add(key, l)
+
+		return l, nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	l := v.(list)
+	return l.apartments, l.next, nil
+}
+
+// house returns the live entry of the house or creates it. Concurrent callers get the same entry,
+// otherwise one of them would replace the other's entry and the pages loaded into it would be lost.
+func (s *service) house(houseID int64) *HouseLists {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if house, ok := s.cache.Get(houseID); ok && time.Now().Before(house.expiresAt) {
+		return house
+	}
+
+	house := &HouseLists{
+		id:        s.seq.Add(1),
+		expiresAt: time.Now().Add(s.ttl),
+		lists:     make(map[listKey]list),
+	}
+	s.cache.Add(houseID, house)
+
+	return house
+}
+
+func (s *service) invalidate(ctx context.Context, houseID int64) {
+	s.mu.Lock()
+	s.cache.Remove(houseID)
+	s.mu.Unlock()
+
+	logger.EndToEndLogging(ctx, s.logger).Debug("Apartments cache invalidated", slog.Int64("house_id", houseID))
+}
+
+func (s *service) Create(ctx context.Context, apartment model.Apartment) (model.Apartment, error) {
+	created, err := s.Service.Create(ctx, apartment)
+	if err != nil {
+		return model.Apartment{}, err
+	}
+
+	s.invalidate(ctx, created.HouseID)
+	return created, nil
+}
+
+func (s *service) Update(ctx context.Context, apartment model.Apartment, moderatorID int64) (model.Apartment, error) {
+	updated, err := s.Service.Update(ctx, apartment, moderatorID)
+	if err != nil {
+		return model.Apartment{}, err
+	}
+
+	s.invalidate(ctx, updated.HouseID)
+	return updated, nil
+}
+
+func (s *service) Edit(ctx context.Context, apartment model.Apartment, ownerID int64) (model.Apartment, error) {
+	edited, err := s.Service.Edit(ctx, apartment, ownerID)
+	if err != nil {
+		return model.Apartment{}, err
+	}
+
+	s.invalidate(ctx, edited.HouseID)
+	return edited, nil
+}
+
+func (s *service) Withdraw(ctx context.Context, apartmentID int64, ownerID int64) (model.Apartment, error) {
+	withdrawn, err := s.Service.Withdraw(ctx, apartmentID, ownerID)
+	if err != nil {
+		return model.Apartment{}, err
+	}
+
+	s.invalidate(ctx, withdrawn.HouseID)
+	return withdrawn, nil
+}
+
+func (s *service) Remove(ctx context.Context, apartmentID int64, reason string) (model.Apartment, error) {
+	removed, err := s.Service.Remove(ctx, apartmentID, reason)
+	if err != nil {
+		return model.Apartment{}, err
+	}
+
+	s.invalidate(ctx, removed.HouseID)
+	return removed, nil
+}
+
+// New wraps the service with the cache. The ttl bounds staleness of writes made by
+// other instances, which this in-process cache never hears about.
+func New(apartmentService apartmentservice.Service, cache cache.Cache[int64, *HouseLists], ttl time.Duration, logger *slog.Logger) apartmentservice.Service {
+	return &service{
+		Service: apartmentService,
+		cache:   cache,
+		ttl:     ttl,
+		logger:  logger,
+	}
+}
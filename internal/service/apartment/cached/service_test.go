@@ -0,0 +1,198 @@
+package apartmentservicecached
+
+import (
+	"avito/internal/model"
+	apartmentservice "avito/internal/service/apartment"
+	"avito/pkg/cache"
+	"avito/pkg/cache/lru"
+	stubwriter "avito/pkg/stub_writer"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestApartments(t *testing.T) {
+	ctrl, mockApartmentService, s := testService(t)
+	defer ctrl.Finish()
+
+	page := model.Page{Limit: 20}
+	apartments := []model.Apartment{{ID: 1, HouseID: 1}}
+
+	//THE SECOND CALL IS SERVED FROM THE CACHE
+	mockApartmentService.EXPECT().Apartments(gomock.Any(), int64(1), page, "client", model.ApartmentCriteria{}).Return(apartments, nil, nil).Times(1)
+
+	for range 2 {
+		got, next, err := s.Apartments(context.Background(), 1, page, "client", model.ApartmentCriteria{})
+		assert.NoError(t, err)
+		assert.Nil(t, next)
+		assert.Equal(t, apartments, got)
+	}
+
+	//ANOTHER PAGE OF THE SAME HOUSE IS A MISS
+	mockApartmentService.EXPECT().Apartments(gomock.Any(), int64(1), model.Page{Limit: 20, After: &model.Cursor{ID: 1}}, "client", model.ApartmentCriteria{}).Return(nil, nil, nil).Times(1)
+
+	_, _, err := s.Apartments(context.Background(), 1, model.Page{Limit: 20, After: &model.Cursor{ID: 1}}, "client", model.ApartmentCriteria{})
+	assert.NoError(t, err)
+}
+
+func TestApartmentsModerator(t *testing.T) {
+	ctrl, mockApartmentService, s := testService(t)
+	defer ctrl.Finish()
+
+	mockApartmentService.EXPECT().Apartments(gomock.Any(), int64(1), gomock.Any(), apartmentservice.RoleModerator, gomock.Any()).Return(nil, nil, nil).Times(2)
+
+	for range 2 {
+		_, _, err := s.Apartments(context.Background(), 1, model.Page{Limit: 20}, apartmentservice.RoleModerator, model.ApartmentCriteria{})
+		assert.NoError(t, err)
+	}
+}
+
+func TestApartmentsErr(t *testing.T) {
+	ctrl, mockApartmentService, s := testService(t)
+	defer ctrl.Finish()
+
+	//FAILURES ARE NOT CACHED
+	mockApartmentService.EXPECT().Apartments(gomock.Any(), int64(1), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil, apartmentservice.ErrInternal).Times(2)
+
+	for range 2 {
+		_, _, err := s.Apartments(context.Background(), 1, model.Page{Limit: 20}, "client", model.ApartmentCriteria{})
+		assert.ErrorIs(t, err, apartmentservice.ErrInternal)
+	}
+}
+
+func TestApartmentsCoalescing(t *testing.T) {
+	ctrl, mockApartmentService, s := testService(t)
+	defer ctrl.Finish()
+
+	const callers = 10
+	release := make(chan struct{})
+
+	mockApartmentService.EXPECT().Apartments(gomock.Any(), int64(1), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(context.Context, int64, model.Page, string, model.ApartmentCriteria) ([]model.Apartment, *model.Cursor, error) {
+		<-release
+		return []model.Apartment{{ID: 1, HouseID: 1}}, nil, nil
+	}).Times(1)
+
+	var wg sync.WaitGroup
+	for range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			got, _, err := s.Apartments(context.Background(), 1, model.Page{Limit: 20}, "client", model.ApartmentCriteria{})
+			assert.NoError(t, err)
+			assert.Len(t, got, 1)
+		}()
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+}
+
+// slowCache widens the window between looking up a house entry and storing a new one.
+type slowCache struct {
+	cache.Cache[int64, *HouseLists]
+}
+
+func (c slowCache) Get(key int64) (*HouseLists, bool) {
+	house, ok := c.Cache.Get(key)
+	time.Sleep(time.Millisecond)
+	return house, ok
+}
+
+func TestConcurrentMisses(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockApartmentService := apartmentservice.NewMockService(ctrl)
+	logger := slog.New(slog.NewTextHandler(&stubwriter.Writer{}, nil))
+	s := New(mockApartmentService, slowCache{lru.New[int64, *HouseLists](10)}, time.Minute, logger)
+
+	const pages = 16
+	start := make(chan struct{})
+
+	//EVERY PAGE IS LOADED ONCE, A LOST HOUSE ENTRY WOULD LOAD ITS PAGES AGAIN
+	for offset := range pages {
+		mockApartmentService.EXPECT().Apartments(gomock.Any(), int64(1), model.Page{Offset: offset, Limit: 1}, "client", gomock.Any()).DoAndReturn(func(context.Context, int64, model.Page, string, model.ApartmentCriteria) ([]model.Apartment, *model.Cursor, error) {
+			time.Sleep(time.Millisecond)
+			return []model.Apartment{{ID: int64(offset + 1), HouseID: 1}}, nil, nil
+		}).Times(1)
+	}
+
+	var wg sync.WaitGroup
+	for offset := range pages {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			<-start
+			_, _, err := s.Apartments(context.Background(), 1, model.Page{Offset: offset, Limit: 1}, "client", model.ApartmentCriteria{})
+			assert.NoError(t, err)
+		}()
+	}
+
+	close(start)
+	wg.Wait()
+
+	for offset := range pages {
+		got, _, err := s.Apartments(context.Background(), 1, model.Page{Offset: offset, Limit: 1}, "client", model.ApartmentCriteria{})
+		assert.NoError(t, err)
+		assert.Equal(t, []model.Apartment{{ID: int64(offset + 1), HouseID: 1}}, got)
+	}
+}
+
+func TestInvalidation(t *testing.T) {
+	ctrl, mockApartmentService, s := testService(t)
+	defer ctrl.Finish()
+
+	page := model.Page{Limit: 20}
+
+	mockApartmentService.EXPECT().Apartments(gomock.Any(), int64(1), page, "client", gomock.Any()).Return(nil, nil, nil).Times(3)
+	mockApartmentService.EXPECT().Create(gomock.Any(), model.Apartment{HouseID: 1}).Return(model.Apartment{ID: 2, HouseID: 1}, nil)
+	mockApartmentService.EXPECT().Update(gomock.Any(), model.Apartment{ID: 1}, int64(2)).Return(model.Apartment{ID: 1, HouseID: 1}, nil)
+
+	_, _, err := s.Apartments(context.Background(), 1, page, "client", model.ApartmentCriteria{})
+	assert.NoError(t, err)
+
+	_, err = s.Create(context.Background(), model.Apartment{HouseID: 1})
+	assert.NoError(t, err)
+
+	_, _, err = s.Apartments(context.Background(), 1, page, "client", model.ApartmentCriteria{})
+	assert.NoError(t, err)
+
+	_, err = s.Update(context.Background(), model.Apartment{ID: 1}, 2)
+	assert.NoError(t, err)
+
+	_, _, err = s.Apartments(context.Background(), 1, page, "client", model.ApartmentCriteria{})
+	assert.NoError(t, err)
+}
+
+func TestExpiration(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockApartmentService := apartmentservice.NewMockService(ctrl)
+	logger := slog.New(slog.NewTextHandler(&stubwriter.Writer{}, nil))
+	s := New(mockApartmentService, lru.New[int64, *HouseLists](10), time.Nanosecond, logger)
+
+	mockApartmentService.EXPECT().Apartments(gomock.Any(), int64(1), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil, nil).Times(2)
+
+	for range 2 {
+		time.Sleep(time.Millisecond)
+		_, _, err := s.Apartments(context.Background(), 1, model.Page{Limit: 20}, "client", model.ApartmentCriteria{})
+		assert.NoError(t, err)
+	}
+}
+
+func testService(t *testing.T) (ctrl *gomock.Controller, mockApartmentService *apartmentservice.MockService, s apartmentservice.Service) {
+	ctrl = gomock.NewController(t)
+
+	mockApartmentService = apartmentservice.NewMockService(ctrl)
+	logger := slog.New(slog.NewTextHandler(&stubwriter.Writer{}, nil))
+
+	return ctrl, mockApartmentService, New(mockApartmentService, lru.New[int64, *HouseLists](10), time.Minute, logger)
+}
@@ -1,8 +1,48 @@
 package apartmentservice
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
 	ErrInternal       = errors.New("internal server error")
 	ErrInvalidHouseID = errors.New("invalid house id")
+
+	ErrApartmentNotFound      = errors.New("apartment not found")
+	ErrApartmentAlreadyExists = errors.New("apartment with this number already exists in the house")
+	ErrModerationConflict     = errors.New("apartment is claimed by another moderator or has a different moderation status")
+	ErrModerationQueueEmpty   = errors.New("no apartments awaiting moderation")
+
+	ErrDeclineReasonRequired = errors.New("decline reason and moderator comment are required to decline an apartment")
+	ErrNotApartmentOwner     = errors.New("apartment belongs to another user")
+	ErrRemovalReasonRequired = errors.New("removal reason is required to remove an apartment")
+
+	ErrStatusFilterForbidden = errors.New("only moderators can filter apartments by moderation status")
 )
+
+var ErrInvalidTransition = errors.New("invalid moderation status transition")
+
+// TransitionError reports a moderation status change that the transition table forbids,
+// together with the statuses the apartment can move to instead.
+type TransitionError struct {
+	From    string
+	To      string
+	Allowed []string
+}
+
+func NewTransitionError(from, to string) *TransitionError {
+	return &TransitionError{
+		From:    from,
+		To:      to,
+		Allowed: AllowedTransitions(from),
+	}
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("%s: %q -> %q", ErrInvalidTransition, e.From, e.To)
+}
+
+func (e *TransitionError) Unwrap() error {
+	return ErrInvalidTransition
+}
@@ -2,50 +2,262 @@ package apartmentserviceimpl
 
 import (
 	"avito/internal/model"
+	"avito/internal/notifier"
 	apartmentrepository "avito/internal/repository/apartment"
 	apartmentservice "avito/internal/service/apartment"
 	"context"
 	"errors"
 	"log/slog"
-)
-
-const (
-	moderator = "moderator"
+	"strings"
+	"time"
 )
 
 type service struct {
-	rep    apartmentrepository.Repository
-	logger *slog.Logger
+	rep      apartmentrepository.Repository
+	notifier notifier.Notifier
+	logger   *slog.Logger
 }
 
-func (s *service) Create(ctx context.Context, apartment model.Apartment) error {
-	if err := s.rep.Create(ctx, apartment); err != nil {
+func (s *service) Create(ctx context.Context, apartment model.Apartment) (model.Apartment, error) {
+	created, err := s.rep.Create(ctx, apartment)
+	if err != nil {
 		switch {
 		case errors.Is(err, apartmentrepository.ErrInvalidHouseID):
-			return apartmentservice.ErrInvalidHouseID
+			return model.Apartment{}, apartmentservice.ErrInvalidHouseID
+		case errors.Is(err, apartmentrepository.ErrApartmentAlreadyExists):
+			return model.Apartment{}, apartmentservice.ErrApartmentAlreadyExists
 		default:
-			return apartmentservice.ErrInternal
+			return model.Apartment{}, apartmentservice.ErrInternal
 		}
 	}
 
-	return nil
+	return created, nil
 }
 
-func (s *service) Update(ctx context.Context, apartment model.Apartment) error {
-	if err := s.rep.Update(ctx, apartment); err != nil {
+// Apartment hides apartments the user isn't allowed to see behind ErrApartmentNotFound,
+// so their existence doesn't leak.
+func (s *service) Apartment(ctx context.Context, apartmentID int64, userID int64, role string) (model.Apartment, error) {
+	apartment, err := s.rep.Apartment(ctx, apartmentID)
+	if err != nil {
 		switch {
-		case errors.Is(err, apartmentrepository.ErrInvalidHouseID):
-			return apartmentservice.ErrInvalidHouseID
+		case errors.Is(err, apartmentrepository.ErrApartmentNotFound):
+			return model.Apartment{}, apartmentservice.ErrApartmentNotFound
+		default:
+			return model.Apartment{}, apartmentservice.ErrInternal
+		}
+	}
+
+	if !apartmentservice.CanView(apartment, userID, role) {
+		return model.Apartment{}, apartmentservice.ErrApartmentNotFound
+	}
+
+	return apartment, nil
+}
+
+func (s *service) Update(ctx context.Context, apartment model.Apartment, moderatorID int64) (model.Apartment, error) {
+	current, err := s.rep.Apartment(ctx, apartment.ID)
+	if err != nil {
+		switch {
+		case errors.Is(err, apartmentrepository.ErrApartmentNotFound):
+			return model.Apartment{}, apartmentservice.ErrApartmentNotFound
+		default:
+			return model.Apartment{}, apartmentservice.ErrInternal
+		}
+	}
+
+	if current.ModerationStatus == apartmentservice.StatusOnModeration && current.ModeratorID != moderatorID {
+		return model.Apartment{}, apartmentservice.ErrModerationConflict
+	}
+
+	if !apartmentservice.CanTransition(current.ModerationStatus, apartment.ModerationStatus) {
+		return model.Apartment{}, apartmentservice.NewTransitionError(current.ModerationStatus, apartment.ModerationStatus)
+	}
+
+	//THE SELLER MUST LEARN WHY THE APARTMENT WAS DECLINED, OTHER STATUSES DROP THE OLD REASON
+	if apartment.ModerationStatus == apartmentservice.StatusDeclined {
+		if apartment.DeclineReason == "" || strings.TrimSpace(apartment.ModeratorComment) == "" {
+			return model.Apartment{}, apartmentservice.ErrDeclineReasonRequired
+		}
+	} else {
+		apartment.DeclineReason = ""
+		apartment.ModeratorComment = ""
+	}
+
+	//THE CLAIM IS HELD ONLY WHILE THE APARTMENT IS ON MODERATION
+	apartment.ModeratorID = 0
+	if apartment.ModerationStatus == apartmentservice.StatusOnModeration {
+		apartment.ModeratorID = moderatorID
+	}
+
+	updated, firstApproval, err := s.rep.Update(ctx, apartment, current.ModerationStatus, moderatorID)
+	if err != nil {
+		switch {
+		case errors.Is(err, apartmentrepository.ErrApartmentNotFound):
+			return model.Apartment{}, apartmentservice.ErrApartmentNotFound
+		case errors.Is(err, apartmentrepository.ErrModerationConflict):
+			return model.Apartment{}, apartmentservice.ErrModerationConflict
+		case errors.Is(err, apartmentrepository.ErrApartmentAlreadyExists):
+			return model.Apartment{}, apartmentservice.ErrApartmentAlreadyExists
+		default:
+			return model.Apartment{}, apartmentservice.ErrInternal
+		}
+	}
+
+	//SUBSCRIBERS LEARN ABOUT THE APARTMENT ONCE IT PASSES MODERATION, A RE-APPROVAL ISN'T NEWS
+	if firstApproval {
+		s.notifier.Notify(updated)
+	}
+
+	return updated, nil
+}
+
+// Edit lets the owner change the apartment. Any edit invalidates the previous moderation
+// decision, so the apartment goes back to the queue whatever status it had.
+func (s *service) Edit(ctx context.Context, apartment model.Apartment, ownerID int64) (model.Apartment, error) {
+	current, err := s.rep.Apartment(ctx, apartment.ID)
+	if err != nil {
+		switch {
+		case errors.Is(err, apartmentrepository.ErrApartmentNotFound):
+			return model.Apartment{}, apartmentservice.ErrApartmentNotFound
+		default:
+			return model.Apartment{}, apartmentservice.ErrInternal
+		}
+	}
+
+	if current.OwnerID != ownerID {
+		return model.Apartment{}, apartmentservice.ErrNotApartmentOwner
+	}
+	apartment.OwnerID = ownerID
+
+	edited, err := s.rep.Edit(ctx, apartment, current.ModerationStatus)
+	if err != nil {
+		switch {
+		case errors.Is(err, apartmentrepository.ErrApartmentNotFound):
+			return model.Apartment{}, apartmentservice.ErrApartmentNotFound
+		case errors.Is(err, apartmentrepository.ErrModerationConflict):
+			return model.Apartment{}, apartmentservice.ErrModerationConflict
+		default:
+			return model.Apartment{}, apartmentservice.ErrInternal
+		}
+	}
+
+	return edited, nil
+}
+
+func (s *service) Withdraw(ctx context.Context, apartmentID int64, ownerID int64) (model.Apartment, error) {
+	current, err := s.rep.Apartment(ctx, apartmentID)
+	if err != nil {
+		switch {
+		case errors.Is(err, apartmentrepository.ErrApartmentNotFound):
+			return model.Apartment{}, apartmentservice.ErrApartmentNotFound
 		default:
-			return apartmentservice.ErrInternal
+			return model.Apartment{}, apartmentservice.ErrInternal
 		}
 	}
 
-	return nil
+	if current.OwnerID != ownerID {
+		return model.Apartment{}, apartmentservice.ErrNotApartmentOwner
+	}
+
+	return s.delete(ctx, apartmentID, apartmentservice.StatusWithdrawn, "")
+}
+
+func (s *service) Remove(ctx context.Context, apartmentID int64, reason string) (model.Apartment, error) {
+	if strings.TrimSpace(reason) == "" {
+		return model.Apartment{}, apartmentservice.ErrRemovalReasonRequired
+	}
+
+	return s.delete(ctx, apartmentID, apartmentservice.StatusRemoved, reason)
+}
+
+func (s *service) delete(ctx context.Context, apartmentID int64, status string, reason string) (model.Apartment, error) {
+	apartment, err := s.rep.Delete(ctx, apartmentID, status, reason)
+	if err != nil {
+		switch {
+		case errors.Is(err, apartmentrepository.ErrApartmentNotFound):
+			return model.Apartment{}, apartmentservice.ErrApartmentNotFound
+		default:
+			return model.Apartment{}, apartmentservice.ErrInternal
+		}
+	}
+
+	return apartment, nil
+}
+
+func (s *service) NextForModeration(ctx context.Context, moderatorID int64, filter model.ModerationQueueFilter) (model.Apartment, error) {
+	apartment, err := s.rep.NextForModeration(ctx, moderatorID, filter)
+	if err != nil {
+		switch {
+		case errors.Is(err, apartmentrepository.ErrModerationQueueEmpty):
+			return model.Apartment{}, apartmentservice.ErrModerationQueueEmpty
+		default:
+			return model.Apartment{}, apartmentservice.ErrInternal
+		}
+	}
+
+	return apartment, nil
+}
+
+func (s *service) ReleaseStaleClaims(ctx context.Context, timeout time.Duration) ([]model.Apartment, error) {
+	apartments, err := s.rep.ReleaseStaleClaims(ctx, timeout)
+	if err != nil {
+		return nil, apartmentservice.ErrInternal
+	}
+
+	return apartments, nil
+}
+
+// Apartments returns the page and a cursor to the next one, nil on the last page.
+// One extra row is requested to tell whether anything follows the page.
+func (s *service) Apartments(ctx context.Context, houseID int64, page model.Page, role string, criteria model.ApartmentCriteria) ([]model.Apartment, *model.Cursor, error) {
+	if criteria.ModerationStatus != "" && role != apartmentservice.RoleModerator {
+		return nil, nil, apartmentservice.ErrStatusFilterForbidden
+	}
+
+	limit := page.Limit
+	page.Limit++
+
+	apartments, err := s.rep.Apartments(ctx, houseID, page, role != apartmentservice.RoleModerator, criteria)
+	if err != nil {
+		return nil, nil, apartmentservice.ErrInternal
+	}
+
+	if len(apartments) <= limit {
+		return apartments, nil, nil
+	}
+
+	apartments = apartments[:limit]
+	return apartments, apartmentCursor(apartments[limit-1], criteria), nil
+}
+
+func apartmentCursor(apartment model.Apartment, criteria model.ApartmentCriteria) *model.Cursor {
+	cursor := &model.Cursor{
+		SortBy:     criteria.SortBy,
+		Descending: criteria.Descending,
+		ID:         apartment.ID,
+	}
+
+	switch criteria.SortBy {
+	case model.SortByPrice:
+		cursor.SortValue = int64(apartment.Price)
+	case model.SortByRooms:
+		cursor.SortValue = int64(apartment.NumberOfRooms)
+	}
+
+	return cursor
+}
+
+func (s *service) Search(ctx context.Context, criteria model.ApartmentSearchCriteria) ([]model.ApartmentWithHouse, int, error) {
+	apartments, total, err := s.rep.Search(ctx, criteria)
+	if err != nil {
+		return nil, 0, apartmentservice.ErrInternal
+	}
+
+	return apartments, total, nil
 }
 
-func (s *service) Apartments(ctx context.Context, houseID uint32, offset int, limit int, role string) ([]model.Apartment, error) {
-	apartments, err := s.rep.Apartments(ctx, houseID, offset, limit, role != moderator)
+func (s *service) OwnerApartments(ctx context.Context, ownerID int64) ([]model.Apartment, error) {
+	apartments, err := s.rep.OwnerApartments(ctx, ownerID)
 	if err != nil {
 		return nil, apartmentservice.ErrInternal
 	}
@@ -53,10 +265,11 @@ func (s *service) Apartments(ctx context.Context, houseID uint32, offset int, li
 	return apartments, nil
 }
 
-func New(rep apartmentrepository.Repository, logger *slog.Logger) apartmentservice.Service {
+func New(rep apartmentrepository.Repository, notifier notifier.Notifier, logger *slog.Logger) apartmentservice.Service {
 	s := &service{
-		rep:    rep,
-		logger: logger,
+		rep:      rep,
+		notifier: notifier,
+		logger:   logger,
 	}
 
 	return s
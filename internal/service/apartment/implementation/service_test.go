@@ -0,0 +1,126 @@
+package apartmentserviceimpl
+
+import (
+	"avito/internal/model"
+	"avito/internal/notifier"
+	apartmentrepository "avito/internal/repository/apartment"
+	apartmentservice "avito/internal/service/apartment"
+	stubwriter "avito/pkg/stub_writer"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"log/slog"
+	"testing"
+)
+
+func TestUpdate(t *testing.T) {
+	ctrl, mockRepository, mockNotifier, s := testService(t)
+	defer ctrl.Finish()
+
+	onModeration := model.Apartment{ID: 1, HouseID: 1, ModerationStatus: apartmentservice.StatusOnModeration, ModeratorID: 2}
+	approved := model.Apartment{ID: 1, HouseID: 1, ModerationStatus: apartmentservice.StatusApproved}
+
+	gomock.InOrder(
+		//THE FIRST APPROVAL IS ANNOUNCED
+		mockRepository.EXPECT().Apartment(gomock.Any(), int64(1)).Return(onModeration, nil),
+		mockRepository.EXPECT().Update(gomock.Any(), approved, apartmentservice.StatusOnModeration, int64(2)).Return(approved, true, nil),
+		mockNotifier.EXPECT().Notify(approved),
+
+		//THE APARTMENT GOES BACK TO MODERATION
+		mockRepository.EXPECT().Apartment(gomock.Any(), int64(1)).Return(approved, nil),
+		mockRepository.EXPECT().Update(gomock.Any(), onModeration, apartmentservice.StatusApproved, int64(2)).Return(onModeration, false, nil),
+
+		//A RE-APPROVAL IS NOT ANNOUNCED AGAIN
+		mockRepository.EXPECT().Apartment(gomock.Any(), int64(1)).Return(onModeration, nil),
+		mockRepository.EXPECT().Update(gomock.Any(), approved, apartmentservice.StatusOnModeration, int64(2)).Return(approved, false, nil),
+	)
+
+	for _, status := range []string{apartmentservice.StatusApproved, apartmentservice.StatusOnModeration, apartmentservice.StatusApproved} {
+		updated, err := s.Update(context.Background(), model.Apartment{ID: 1, HouseID: 1, ModerationStatus: status}, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, status, updated.ModerationStatus)
+	}
+}
+
+func TestUpdateErr(t *testing.T) {
+	cases := []struct {
+		name        string
+		apartment   model.Apartment
+		expectedErr error
+		prepareFunc func(mockRepository *apartmentrepository.MockRepository)
+	}{
+		{
+			name:        "ERR NOT FOUND",
+			apartment:   model.Apartment{ID: 1, ModerationStatus: apartmentservice.StatusApproved},
+			expectedErr: apartmentservice.ErrApartmentNotFound,
+			prepareFunc: func(mockRepository *apartmentrepository.MockRepository) {
+				mockRepository.EXPECT().Apartment(gomock.Any(), int64(1)).Return(model.Apartment{}, apartmentrepository.ErrApartmentNotFound)
+			},
+		},
+		{
+			name:        "ERR CLAIMED BY ANOTHER MODERATOR",
+			apartment:   model.Apartment{ID: 1, ModerationStatus: apartmentservice.StatusApproved},
+			expectedErr: apartmentservice.ErrModerationConflict,
+			prepareFunc: func(mockRepository *apartmentrepository.MockRepository) {
+				mockRepository.EXPECT().Apartment(gomock.Any(), int64(1)).Return(model.Apartment{ID: 1, ModerationStatus: apartmentservice.StatusOnModeration, ModeratorID: 3}, nil)
+			},
+		},
+		{
+			name:        "ERR INVALID TRANSITION",
+			apartment:   model.Apartment{ID: 1, ModerationStatus: apartmentservice.StatusApproved},
+			expectedErr: apartmentservice.ErrInvalidTransition,
+			prepareFunc: func(mockRepository *apartmentrepository.MockRepository) {
+				mockRepository.EXPECT().Apartment(gomock.Any(), int64(1)).Return(model.Apartment{ID: 1, ModerationStatus: apartmentservice.StatusCreated}, nil)
+			},
+		},
+		{
+			name:        "ERR DECLINE REASON REQUIRED",
+			apartment:   model.Apartment{ID: 1, ModerationStatus: apartmentservice.StatusDeclined},
+			expectedErr: apartmentservice.ErrDeclineReasonRequired,
+			prepareFunc: func(mockRepository *apartmentrepository.MockRepository) {
+				mockRepository.EXPECT().Apartment(gomock.Any(), int64(1)).Return(model.Apartment{ID: 1, ModerationStatus: apartmentservice.StatusOnModeration, ModeratorID: 2}, nil)
+			},
+		},
+		{
+			name:        "ERR LOST CAS",
+			apartment:   model.Apartment{ID: 1, ModerationStatus: apartmentservice.StatusApproved},
+			expectedErr: apartmentservice.ErrModerationConflict,
+			prepareFunc: func(mockRepository *apartmentrepository.MockRepository) {
+				mockRepository.EXPECT().Apartment(gomock.Any(), int64(1)).Return(model.Apartment{ID: 1, ModerationStatus: apartmentservice.StatusOnModeration, ModeratorID: 2}, nil)
+				mockRepository.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(model.Apartment{}, false, apartmentrepository.ErrModerationConflict)
+			},
+		},
+		{
+			name:        "ERR APARTMENT ALREADY EXISTS",
+			apartment:   model.Apartment{ID: 1, ModerationStatus: apartmentservice.StatusApproved},
+			expectedErr: apartmentservice.ErrApartmentAlreadyExists,
+			prepareFunc: func(mockRepository *apartmentrepository.MockRepository) {
+				mockRepository.EXPECT().Apartment(gomock.Any(), int64(1)).Return(model.Apartment{ID: 1, ModerationStatus: apartmentservice.StatusOnModeration, ModeratorID: 2}, nil)
+				mockRepository.EXPECT().Update(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(model.Apartment{}, false, apartmentrepository.ErrApartmentAlreadyExists)
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			//THE NOTIFIER MOCK FAILS THE TEST IF A FAILED UPDATE IS ANNOUNCED
+			ctrl, mockRepository, _, s := testService(t)
+			defer ctrl.Finish()
+
+			c.prepareFunc(mockRepository)
+
+			_, err := s.Update(context.Background(), c.apartment, 2)
+			assert.ErrorIs(t, err, c.expectedErr)
+		})
+	}
+}
+
+func testService(t *testing.T) (ctrl *gomock.Controller, mockRepository *apartmentrepository.MockRepository, mockNotifier *notifier.MockNotifier, s apartmentservice.Service) {
+	ctrl = gomock.NewController(t)
+
+	mockRepository = apartmentrepository.NewMockRepository(ctrl)
+	mockNotifier = notifier.NewMockNotifier(ctrl)
+	logger := slog.New(slog.NewTextHandler(&stubwriter.Writer{}, nil))
+
+	return ctrl, mockRepository, mockNotifier, New(mockRepository, mockNotifier, logger)
+}
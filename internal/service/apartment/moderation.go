@@ -0,0 +1,31 @@
+package apartmentservice
+
+import "slices"
+
+const (
+	StatusCreated      = "created"
+	StatusOnModeration = "on moderation"
+	StatusApproved     = "approved"
+	StatusDeclined     = "declined"
+
+	//TERMINAL STATUSES OF SOFT-DELETED APARTMENTS
+	StatusWithdrawn = "withdrawn"
+	StatusRemoved   = "removed"
+)
+
+// transitions lists the moderation statuses reachable from each status.
+// Approved and declined apartments go through moderation again before their status can change.
+var transitions = map[string][]string{
+	StatusCreated:      {StatusOnModeration},
+	StatusOnModeration: {StatusApproved, StatusDeclined, StatusCreated},
+	StatusApproved:     {StatusOnModeration},
+	StatusDeclined:     {StatusOnModeration},
+}
+
+func AllowedTransitions(from string) []string {
+	return slices.Clone(transitions[from])
+}
+
+func CanTransition(from, to string) bool {
+	return slices.Contains(transitions[from], to)
+}
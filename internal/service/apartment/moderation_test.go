@@ -0,0 +1,43 @@
+package apartmentservice
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCanTransition(t *testing.T) {
+	cases := []struct {
+		from     string
+		to       string
+		expected bool
+	}{
+		{from: StatusCreated, to: StatusOnModeration, expected: true},
+		{from: StatusCreated, to: StatusApproved, expected: false},
+		{from: StatusCreated, to: StatusDeclined, expected: false},
+		{from: StatusOnModeration, to: StatusApproved, expected: true},
+		{from: StatusOnModeration, to: StatusDeclined, expected: true},
+		{from: StatusOnModeration, to: StatusCreated, expected: true},
+		{from: StatusApproved, to: StatusOnModeration, expected: true},
+		{from: StatusApproved, to: StatusDeclined, expected: false},
+		{from: StatusDeclined, to: StatusCreated, expected: false},
+		{from: StatusDeclined, to: StatusOnModeration, expected: true},
+		{from: StatusApproved, to: StatusApproved, expected: false},
+		{from: StatusOnModeration, to: StatusRemoved, expected: false},
+		{from: StatusWithdrawn, to: StatusCreated, expected: false},
+		{from: StatusRemoved, to: StatusOnModeration, expected: false},
+		{from: "unknown", to: StatusCreated, expected: false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.from+" -> "+c.to, func(t *testing.T) {
+			assert.Equal(t, c.expected, CanTransition(c.from, c.to))
+		})
+	}
+}
+
+func TestTransitionError(t *testing.T) {
+	err := NewTransitionError(StatusCreated, StatusApproved)
+
+	assert.ErrorIs(t, err, ErrInvalidTransition)
+	assert.Equal(t, []string{StatusOnModeration}, err.Allowed)
+}
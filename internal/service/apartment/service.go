@@ -3,10 +3,19 @@ package apartmentservice
 import (
 	"avito/internal/model"
 	"context"
+	"time"
 )
 
 type Service interface {
-	Create(ctx context.Context, apartment model.Apartment) error
-	Update(ctx context.Context, apartment model.Apartment) error
-	Apartments(ctx context.Context, houseID uint32, offset int, limit int, role string) ([]model.Apartment, error)
+	Create(ctx context.Context, apartment model.Apartment) (model.Apartment, error)
+	Apartment(ctx context.Context, apartmentID int64, userID int64, role string) (model.Apartment, error)
+	Update(ctx context.Context, apartment model.Apartment, moderatorID int64) (model.Apartment, error)
+	Edit(ctx context.Context, apartment model.Apartment, ownerID int64) (model.Apartment, error)
+	Withdraw(ctx context.Context, apartmentID int64, ownerID int64) (model.Apartment, error)
+	Remove(ctx context.Context, apartmentID int64, reason string) (model.Apartment, error)
+	NextForModeration(ctx context.Context, moderatorID int64, filter model.ModerationQueueFilter) (model.Apartment, error)
+	ReleaseStaleClaims(ctx context.Context, timeout time.Duration) ([]model.Apartment, error)
+	Apartments(ctx context.Context, houseID int64, page model.Page, role string, criteria model.ApartmentCriteria) ([]model.Apartment, *model.Cursor, error)
+	Search(ctx context.Context, criteria model.ApartmentSearchCriteria) ([]model.ApartmentWithHouse, int, error)
+	OwnerApartments(ctx context.Context, ownerID int64) ([]model.Apartment, error)
 }
@@ -13,6 +13,7 @@ import (
 	model "avito/internal/model"
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "go.uber.org/mock/gomock"
 )
@@ -40,27 +41,44 @@ func (m *MockService) EXPECT() *MockServiceMockRecorder {
 	return m.recorder
 }
 
-// Apartments mocks base method.
-func (m *MockService) Apartments(ctx context.Context, houseID uint32, offset, limit int, role string) ([]model.Apartment, error) {
+// Apartment mocks base method.
+func (m *MockService) Apartment(ctx context.Context, apartmentID, userID int64, role string) (model.Apartment, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Apartments", ctx, houseID, offset, limit, role)
-	ret0, _ := ret[0].([]model.Apartment)
+	ret := m.ctrl.Call(m, "Apartment", ctx, apartmentID, userID, role)
+	ret0, _ := ret[0].(model.Apartment)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
+// Apartment indicates an expected call of Apartment.
+func (mr *MockServiceMockRecorder) Apartment(ctx, apartmentID, userID, role any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Apartment", reflect.TypeOf((*MockService)(nil).Apartment), ctx, apartmentID, userID, role)
+}
+
+// Apartments mocks base method.
+func (m *MockService) Apartments(ctx context.Context, houseID int64, page model.Page, role string, criteria model.ApartmentCriteria) ([]model.Apartment, *model.Cursor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Apartments", ctx, houseID, page, role, criteria)
+	ret0, _ := ret[0].([]model.Apartment)
+	ret1, _ := ret[1].(*model.Cursor)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
 // Apartments indicates an expected call of Apartments.
-func (mr *MockServiceMockRecorder) Apartments(ctx, houseID, offset, limit, role any) *gomock.Call {
+func (mr *MockServiceMockRecorder) Apartments(ctx, houseID, page, role, criteria any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Apartments", reflect.TypeOf((*MockService)(nil).Apartments), ctx, houseID, offset, limit, role)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Apartments", reflect.TypeOf((*MockService)(nil).Apartments), ctx, houseID, page, role, criteria)
 }
 
 // Create mocks base method.
-func (m *MockService) Create(ctx context.Context, apartment model.Apartment) error {
+func (m *MockService) Create(ctx context.Context, apartment model.Apartment) (model.Apartment, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Create", ctx, apartment)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(model.Apartment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // Create indicates an expected call of Create.
@@ -69,16 +87,123 @@ func (mr *MockServiceMockRecorder) Create(ctx, apartment any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockService)(nil).Create), ctx, apartment)
 }
 
+// Edit mocks base method.
+func (m *MockService) Edit(ctx context.Context, apartment model.Apartment, ownerID int64) (model.Apartment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Edit", ctx, apartment, ownerID)
+	ret0, _ := ret[0].(model.Apartment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Edit indicates an expected call of Edit.
+func (mr *MockServiceMockRecorder) Edit(ctx, apartment, ownerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Edit", reflect.TypeOf((*MockService)(nil).Edit), ctx, apartment, ownerID)
+}
+
+// NextForModeration mocks base method.
+func (m *MockService) NextForModeration(ctx context.Context, moderatorID int64, filter model.ModerationQueueFilter) (model.Apartment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NextForModeration", ctx, moderatorID, filter)
+	ret0, _ := ret[0].(model.Apartment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// NextForModeration indicates an expected call of NextForModeration.
+func (mr *MockServiceMockRecorder) NextForModeration(ctx, moderatorID, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NextForModeration", reflect.TypeOf((*MockService)(nil).NextForModeration), ctx, moderatorID, filter)
+}
+
+// OwnerApartments mocks base method.
+func (m *MockService) OwnerApartments(ctx context.Context, ownerID int64) ([]model.Apartment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OwnerApartments", ctx, ownerID)
+	ret0, _ := ret[0].([]model.Apartment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OwnerApartments indicates an expected call of OwnerApartments.
+func (mr *MockServiceMockRecorder) OwnerApartments(ctx, ownerID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OwnerApartments", reflect.TypeOf((*MockService)(nil).OwnerApartments), ctx, ownerID)
+}
+
+// ReleaseStaleClaims mocks base method.
+func (m *MockService) ReleaseStaleClaims(ctx context.Context, timeout time.Duration) ([]model.Apartment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseStaleClaims", ctx, timeout)
+	ret0, _ := ret[0].([]model.Apartment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReleaseStaleClaims indicates an expected call of ReleaseStaleClaims.
+func (mr *MockServiceMockRecorder) ReleaseStaleClaims(ctx, timeout any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseStaleClaims", reflect.TypeOf((*MockService)(nil).ReleaseStaleClaims), ctx, timeout)
+}
+
+// Remove mocks base method.
+func (m *MockService) Remove(ctx context.Context, apartmentID int64, reason string) (model.Apartment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Remove", ctx, apartmentID, reason)
+	ret0, _ := ret[0].(model.Apartment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Remove indicates an expected call of Remove.
+func (mr *MockServiceMockRecorder) Remove(ctx, apartmentID, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Remove", reflect.TypeOf((*MockService)(nil).Remove), ctx, apartmentID, reason)
+}
+
+// Search mocks base method.
+func (m *MockService) Search(ctx context.Context, criteria model.ApartmentSearchCriteria) ([]model.ApartmentWithHouse, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, criteria)
+	ret0, _ := ret[0].([]model.ApartmentWithHouse)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockServiceMockRecorder) Search(ctx, criteria any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockService)(nil).Search), ctx, criteria)
+}
+
 // Update mocks base method.
-func (m *MockService) Update(ctx context.Context, apartment model.Apartment) error {
+func (m *MockService) Update(ctx context.Context, apartment model.Apartment, moderatorID int64) (model.Apartment, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", ctx, apartment)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "Update", ctx, apartment, moderatorID)
+	ret0, _ := ret[0].(model.Apartment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockServiceMockRecorder) Update(ctx, apartment any) *gomock.Call {
+func (mr *MockServiceMockRecorder) Update(ctx, apartment, moderatorID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockService)(nil).Update), ctx, apartment, moderatorID)
+}
+
+// Withdraw mocks base method.
+func (m *MockService) Withdraw(ctx context.Context, apartmentID, ownerID int64) (model.Apartment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Withdraw", ctx, apartmentID, ownerID)
+	ret0, _ := ret[0].(model.Apartment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Withdraw indicates an expected call of Withdraw.
+func (mr *MockServiceMockRecorder) Withdraw(ctx, apartmentID, ownerID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockService)(nil).Update), ctx, apartment)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Withdraw", reflect.TypeOf((*MockService)(nil).Withdraw), ctx, apartmentID, ownerID)
 }
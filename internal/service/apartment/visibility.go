@@ -0,0 +1,31 @@
+package apartmentservice
+
+import "avito/internal/model"
+
+const (
+	RoleModerator = "moderator"
+)
+
+// CanView reports whether the user may see the apartment. Moderators see every apartment,
+// everyone else sees approved apartments and the ones they published themselves.
+func CanView(apartment model.Apartment, userID int64, role string) bool {
+	return role == RoleModerator ||
+		apartment.ModerationStatus == StatusApproved ||
+		(apartment.OwnerID != 0 && apartment.OwnerID == userID)
+}
+
+// Redact strips the account ids and the moderation notes from an apartment shown to someone
+// who is neither its owner nor a moderator.
+func Redact(apartment model.Apartment, userID int64, role string) model.Apartment {
+	if role == RoleModerator || (apartment.OwnerID != 0 && apartment.OwnerID == userID) {
+		return apartment
+	}
+
+	apartment.OwnerID = 0
+	apartment.ModeratorID = 0
+	apartment.DeclineReason = ""
+	apartment.ModeratorComment = ""
+	apartment.RemovalReason = ""
+
+	return apartment
+}
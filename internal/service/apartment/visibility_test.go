@@ -0,0 +1,51 @@
+package apartmentservice
+
+import (
+	"avito/internal/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCanView(t *testing.T) {
+	cases := []struct {
+		name      string
+		apartment model.Apartment
+		userID    int64
+		role      string
+		expected  bool
+	}{
+		{name: "CLIENT APPROVED", apartment: model.Apartment{OwnerID: 2, ModerationStatus: StatusApproved}, userID: 1, role: "client", expected: true},
+		{name: "CLIENT FOREIGN DECLINED", apartment: model.Apartment{OwnerID: 2, ModerationStatus: StatusDeclined}, userID: 1, role: "client", expected: false},
+		{name: "CLIENT FOREIGN CREATED", apartment: model.Apartment{OwnerID: 2, ModerationStatus: StatusCreated}, userID: 1, role: "client", expected: false},
+		{name: "CLIENT OWN DECLINED", apartment: model.Apartment{OwnerID: 1, ModerationStatus: StatusDeclined}, userID: 1, role: "client", expected: true},
+		{name: "CLIENT WITHOUT OWNER", apartment: model.Apartment{ModerationStatus: StatusOnModeration}, userID: 0, role: "client", expected: false},
+		{name: "MODERATOR FOREIGN DECLINED", apartment: model.Apartment{OwnerID: 2, ModerationStatus: StatusDeclined}, userID: 1, role: RoleModerator, expected: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.expected, CanView(c.apartment, c.userID, c.role))
+		})
+	}
+}
+
+func TestRedact(t *testing.T) {
+	apartment := model.Apartment{ID: 1, OwnerID: 2, ModeratorID: 3, ModerationStatus: StatusApproved, DeclineReason: "wrong_price", ModeratorComment: "comment"}
+
+	cases := []struct {
+		name     string
+		userID   int64
+		role     string
+		expected model.Apartment
+	}{
+		{name: "CLIENT FOREIGN", userID: 1, role: "client", expected: model.Apartment{ID: 1, ModerationStatus: StatusApproved}},
+		{name: "CLIENT OWN", userID: 2, role: "client", expected: apartment},
+		{name: "MODERATOR", userID: 1, role: RoleModerator, expected: apartment},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.expected, Redact(apartment, c.userID, c.role))
+		})
+	}
+}
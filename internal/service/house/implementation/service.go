@@ -15,30 +15,55 @@ type service struct {
 	logger *slog.Logger
 }
 
-func (s *service) Create(ctx context.Context, house model.House) error {
-	if err := s.rep.Create(ctx, house); err != nil {
+func (s *service) Create(ctx context.Context, house model.House) (model.House, error) {
+	created, err := s.rep.Create(ctx, house)
+	if err != nil {
 		switch {
 		case errors.Is(err, houserepository.ErrHouseAlreadyExists):
-			return houseservice.ErrHouseAlreadyExists
+			return model.House{}, houseservice.ErrHouseAlreadyExists
 		default:
-			return houseservice.ErrInternal
+			return model.House{}, houseservice.ErrInternal
 		}
 	}
-	return nil
+	return created, nil
+}
+
+// Houses returns the page and a cursor to the next one, nil on the last page.
+// One extra row is requested to tell whether anything follows the page.
+func (s *service) Houses(ctx context.Context, page model.Page) ([]model.House, *model.Cursor, error) {
+	limit := page.Limit
+	page.Limit++
+
+	houses, err := s.rep.Houses(ctx, page)
+	if err != nil {
+		switch {
+		case errors.Is(err, houserepository.ErrHouseNotFound):
+			return nil, nil, houseservice.ErrHouseNotFound
+		default:
+			return nil, nil, houseservice.ErrInternal
+		}
+	}
+
+	if len(houses) <= limit {
+		return houses, nil, nil
+	}
+
+	houses = houses[:limit]
+	return houses, &model.Cursor{ID: houses[limit-1].HouseId}, nil
 }
 
-func (s *service) Houses(ctx context.Context, offset int, limit int) ([]model.House, error) {
-	houses, err := s.rep.Houses(ctx, offset, limit)
+func (s *service) House(ctx context.Context, houseID int64) (model.House, error) {
+	house, err := s.rep.House(ctx, houseID)
 	if err != nil {
 		switch {
 		case errors.Is(err, houserepository.ErrHouseNotFound):
-			return nil, houseservice.ErrHouseNotFound
+			return model.House{}, houseservice.ErrHouseNotFound
 		default:
-			return nil, houseservice.ErrInternal
+			return model.House{}, houseservice.ErrInternal
 		}
 	}
 
-	return houses, nil
+	return house, nil
 }
 
 func New(rep houserepository.Repository, logger *slog.Logger) houseservice.Service {
@@ -41,11 +41,12 @@ func (m *MockService) EXPECT() *MockServiceMockRecorder {
 }
 
 // Create mocks base method.
-func (m *MockService) Create(ctx context.Context, house model.House) error {
+func (m *MockService) Create(ctx context.Context, house model.House) (model.House, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Create", ctx, house)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(model.House)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // Create indicates an expected call of Create.
@@ -54,17 +55,33 @@ func (mr *MockServiceMockRecorder) Create(ctx, house any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockService)(nil).Create), ctx, house)
 }
 
-// Houses mocks base method.
-func (m *MockService) Houses(ctx context.Context, offset, limit int) ([]model.House, error) {
+// House mocks base method.
+func (m *MockService) House(ctx context.Context, houseID int64) (model.House, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Houses", ctx, offset, limit)
-	ret0, _ := ret[0].([]model.House)
+	ret := m.ctrl.Call(m, "House", ctx, houseID)
+	ret0, _ := ret[0].(model.House)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
+// House indicates an expected call of House.
+func (mr *MockServiceMockRecorder) House(ctx, houseID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "House", reflect.TypeOf((*MockService)(nil).House), ctx, houseID)
+}
+
+// Houses mocks base method.
+func (m *MockService) Houses(ctx context.Context, page model.Page) ([]model.House, *model.Cursor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Houses", ctx, page)
+	ret0, _ := ret[0].([]model.House)
+	ret1, _ := ret[1].(*model.Cursor)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
 // Houses indicates an expected call of Houses.
-func (mr *MockServiceMockRecorder) Houses(ctx, offset, limit any) *gomock.Call {
+func (mr *MockServiceMockRecorder) Houses(ctx, page any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Houses", reflect.TypeOf((*MockService)(nil).Houses), ctx, offset, limit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Houses", reflect.TypeOf((*MockService)(nil).Houses), ctx, page)
 }
@@ -6,4 +6,5 @@ var (
 	ErrInternal            = errors.New("internal error")
 	ErrNoSession           = errors.New("no session")
 	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token reused")
 )
@@ -3,167 +3,292 @@ package sessionserviceimpl
 import (
 	"avito/internal/model"
 	sessionrepository "avito/internal/repository/session"
+	userrepository "avito/internal/repository/user"
 	sessionservice "avito/internal/service/session"
+	"avito/internal/transaction"
 	"avito/pkg/hasher"
 	"avito/pkg/logger"
+	"avito/pkg/revocation"
 	tokenmanager "avito/pkg/token_manager"
 	"context"
 	"errors"
-	"github.com/google/uuid"
 	"log/slog"
 	"time"
 )
 
+// expiredBatchSize bounds the sessions deleted by one statement of DeleteExpired.
+const expiredBatchSize = 1000
+
 type service struct {
 	sessionRepository sessionrepository.Repository
+	userRepository    userrepository.Repository
+	txManager         transaction.Manager
 
 	tokenManager tokenmanager.Manager
+	revoked      revocation.List
 
 	logger *slog.Logger
 }
 
-func (s *service) generateTokens(userID uint32, role string, l *slog.Logger) (accessToken string, refreshToken string, refreshTokenExpiresAt time.Time, err error) {
-	accessToken, err = s.tokenManager.GenerateAccessToken(userID, role)
+func (s *service) generateRefreshToken(l *slog.Logger) (refreshToken string, refreshTokenExpiresAt time.Time, err error) {
+	refreshToken, refreshTokenExpiresAt, err = s.tokenManager.GenerateRefreshToken()
 	if err != nil {
-		l.Error("Failed to generate access token", "error", err.Error())
-		return "", "", time.Time{}, sessionservice.ErrInternal
+		l.Error("Failed to generate refresh token", "error", err.Error())
+		return "", time.Time{}, sessionservice.ErrInternal
 	}
 
-	refreshToken, refreshTokenExpiresAt, err = s.tokenManager.GenerateRefreshToken()
+	return refreshToken, refreshTokenExpiresAt, nil
+}
+
+func (s *service) generateAccessToken(userID int64, role string, sessionID int64, l *slog.Logger) (accessToken string, claims tokenmanager.Claims, err error) {
+	accessToken, claims, err = s.tokenManager.GenerateAccessToken(userID, role, sessionID)
 	if err != nil {
-		l.Error("Failed to generate refresh token", "error", err.Error())
-		return "", "", time.Time{}, sessionservice.ErrInternal
+		l.Error("Failed to generate access token", "error", err.Error())
+		return "", tokenmanager.Claims{}, sessionservice.ErrInternal
 	}
 
-	return accessToken, refreshToken, refreshTokenExpiresAt, nil
+	return accessToken, claims, nil
+}
+
+// revokeAccessToken makes the last access token issued for the session unusable right away.
+func (s *service) revokeAccessToken(session model.Session) {
+	if session.AccessTokenID != "" {
+		s.revoked.Revoke(session.AccessTokenID, session.AccessTokenExpiresAt)
+	}
 }
 
-func (s *service) Create(ctx context.Context, userID uint32, role string) (accessToken, refreshToken string, err error) {
+// Create starts a new session on the device, the sessions on other devices stay alive.
+// Every refresh token issued for the session later belongs to the same family. The session
+// is saved together with its access token id, a session without one couldn't be revoked.
+func (s *service) Create(ctx context.Context, userID int64, role string, device model.Device) (accessToken, refreshToken string, err error) {
 	l := logger.EndToEndLogging(ctx, s.logger)
 
-	accessToken, refreshToken, refreshTokenExpiresAt, err := s.generateTokens(userID, role, l)
+	refreshToken, refreshTokenExpiresAt, err := s.generateRefreshToken(l)
 	if err != nil {
 		return "", "", err
 	}
 
-	hashRefreshToken, err := hasher.Hash(refreshToken)
-	if err != nil {
-		l.Error("Failed to hash refresh token", "error", err.Error())
-		return "", "", sessionservice.ErrInternal
-	}
-
 	session := model.Session{
-		SessionID:        uuid.New().ID(),
 		UserID:           userID,
-		HashRefreshToken: hashRefreshToken,
+		HashRefreshToken: hasher.Digest(refreshToken),
 		ExpiresAt:        refreshTokenExpiresAt,
+		UserAgent:        device.UserAgent,
+		IP:               device.IP,
 	}
 
-	if err = s.sessionRepository.Create(ctx, session); err != nil {
+	err = s.txManager.Do(ctx, func(ctx context.Context) error {
+		session, err = s.sessionRepository.Create(ctx, session)
+		if err != nil {
+			return sessionservice.ErrInternal
+		}
+
+		//THE ACCESS TOKEN CARRIES THE ID ASSIGNED TO THE SESSION
+		var claims tokenmanager.Claims
+		accessToken, claims, err = s.generateAccessToken(userID, role, session.SessionID, l)
+		if err != nil {
+			return err
+		}
+
+		if err = s.sessionRepository.BindAccessToken(ctx, session.SessionID, claims.ID, claims.ExpiresAt.Time); err != nil {
+			return sessionservice.ErrInternal
+		}
+
+		return nil
+	})
+	if err != nil {
 		return "", "", sessionservice.ErrInternal
 	}
 
 	return accessToken, refreshToken, nil
 }
 
-func (s *service) Update(ctx context.Context, userID uint32, role string, expiredRefreshToken string) (accessToken, refreshToken string, err error) {
+// Update rotates the refresh token, the presented one is marked used. Presenting a used token
+// means it was stolen or replayed, so the whole family is revoked. New tokens are issued for
+// the role stored with the user, never for the one claimed by the old access token.
+func (s *service) Update(ctx context.Context, userID, sessionID int64, refreshToken string) (accessToken, newRefreshToken string, err error) {
 	l := logger.EndToEndLogging(ctx, s.logger)
 
-	session, err := s.sessionRepository.SessionByUserId(ctx, userID)
+	hashRefreshToken := hasher.Digest(refreshToken)
+
+	session, err := s.sessionRepository.SessionByRefreshToken(ctx, hashRefreshToken)
 	switch {
 	case errors.Is(err, sessionrepository.ErrNoSession):
-		return "", "", sessionservice.ErrNoSession
+		return "", "", s.checkReuse(ctx, hashRefreshToken, l)
 	case err != nil:
 		return "", "", sessionservice.ErrInternal
 	}
 
-	if time.Now().After(session.ExpiresAt) {
+	if session.UserID != userID || session.SessionID != sessionID || time.Now().After(session.ExpiresAt) {
 		return "", "", sessionservice.ErrInvalidRefreshToken
 	}
 
-	err = hasher.Compare(expiredRefreshToken, session.HashRefreshToken)
-	if err != nil {
-		return "", "", sessionservice.ErrInvalidRefreshToken
+	user, err := s.userRepository.UserByID(ctx, userID)
+	switch {
+	case errors.Is(err, userrepository.ErrUserNotFound):
+		return "", "", sessionservice.ErrNoSession
+	case err != nil:
+		return "", "", sessionservice.ErrInternal
 	}
 
-	accessToken, refreshToken, refreshTokenExpiresAt, err := s.generateTokens(userID, role, l)
+	newRefreshToken, refreshTokenExpiresAt, err := s.generateRefreshToken(l)
 	if err != nil {
 		return "", "", err
 	}
 
-	hashRefreshToken, err := hasher.Hash(refreshToken)
+	accessToken, claims, err := s.generateAccessToken(userID, user.Role, session.SessionID, l)
 	if err != nil {
-		l.Error("Failed to hash refresh token", "error", err.Error())
-		return "", "", sessionservice.ErrInternal
+		return "", "", err
 	}
 
-	session = model.Session{
-		UserID:           userID,
-		HashRefreshToken: hashRefreshToken,
-		ExpiresAt:        refreshTokenExpiresAt,
-	}
+	rotated := session
+	rotated.HashRefreshToken = hasher.Digest(newRefreshToken)
+	rotated.ExpiresAt = refreshTokenExpiresAt
+	rotated.AccessTokenID = claims.ID
+	rotated.AccessTokenExpiresAt = claims.ExpiresAt.Time
 
-	if err = s.sessionRepository.ResetSession(ctx, session); err != nil {
+	err = s.sessionRepository.Rotate(ctx, rotated, hashRefreshToken)
+	switch {
+	case errors.Is(err, sessionrepository.ErrNoSession):
+		//A CONCURRENT REFRESH WITH THE SAME TOKEN WON, THIS ONE IS A REPLAY
+		return "", "", s.checkReuse(ctx, hashRefreshToken, l)
+	case err != nil:
 		return "", "", sessionservice.ErrInternal
 	}
 
-	return accessToken, refreshToken, nil
+	//ONLY THE LAST ACCESS TOKEN OF THE SESSION STAYS VALID
+	s.revokeAccessToken(session)
+
+	return accessToken, newRefreshToken, nil
 }
 
-func (s *service) checkSessionByUserId(ctx context.Context, userID uint32) (exist bool, err error) {
-	exist, err = s.sessionRepository.CheckSessionByUserId(ctx, userID)
-	if err != nil {
-		return false, sessionservice.ErrInternal
+// checkReuse is called for a token that isn't the current one of any session.
+// If it was rotated out before, the family it belongs to is revoked.
+func (s *service) checkReuse(ctx context.Context, hashRefreshToken string, l *slog.Logger) error {
+	session, err := s.sessionRepository.SessionByUsedRefreshToken(ctx, hashRefreshToken)
+	switch {
+	case errors.Is(err, sessionrepository.ErrNoSession):
+		return sessionservice.ErrInvalidRefreshToken
+	case err != nil:
+		return sessionservice.ErrInternal
 	}
 
-	return exist, nil
+	l.Warn("Refresh token reuse detected, revoking the session",
+		slog.String("security_event", "refresh_token_reuse"),
+		slog.Int64("session_id", session.SessionID),
+		slog.Int64("user_id", session.UserID))
+
+	session, err = s.sessionRepository.Delete(ctx, session.UserID, session.SessionID)
+	switch {
+	case errors.Is(err, sessionrepository.ErrNoSession):
+	case err != nil:
+		return sessionservice.ErrInternal
+	default:
+		s.revokeAccessToken(session)
+	}
+
+	return sessionservice.ErrRefreshTokenReused
 }
 
-func (s *service) ResetSession(ctx context.Context, userID uint32, role string) (accessToken, refreshToken string, err error) {
-	l := logger.EndToEndLogging(ctx, s.logger)
-	//SEARCHING USER SESSION
-	exist, err := s.checkSessionByUserId(ctx, userID)
+func (s *service) Sessions(ctx context.Context, userID int64) ([]model.Session, error) {
+	sessions, err := s.sessionRepository.SessionsByUserID(ctx, userID)
 	if err != nil {
-		return "", "", err
+		return nil, sessionservice.ErrInternal
 	}
 
-	//CREATE A NEW ONE SESSION
-	if !exist {
-		return s.Create(ctx, userID, role)
+	return sessions, nil
+}
+
+// Revoke ends a session of the user, a session of another user is reported as missing.
+func (s *service) Revoke(ctx context.Context, userID, sessionID int64) error {
+	session, err := s.sessionRepository.Delete(ctx, userID, sessionID)
+	switch {
+	case errors.Is(err, sessionrepository.ErrNoSession):
+		return sessionservice.ErrNoSession
+	case err != nil:
+		return sessionservice.ErrInternal
 	}
 
-	//GENERATE TOKENS
-	var refreshTokenExpiresAt time.Time
-	accessToken, refreshToken, refreshTokenExpiresAt, err = s.generateTokens(userID, role, l)
+	s.revokeAccessToken(session)
+
+	return nil
+}
+
+// RevokeAll ends every session of the user and takes away the access at once,
+// this is what a demotion or a ban of the user has to go through.
+func (s *service) RevokeAll(ctx context.Context, userID int64) error {
+	sessions, err := s.DeleteAll(ctx, userID)
 	if err != nil {
-		return "", "", err
+		return err
 	}
 
-	hashRefreshToken, err := hasher.Hash(refreshToken)
+	s.RevokeAccessTokens(sessions)
+
+	return nil
+}
+
+// DeleteAll ends every session of the user but leaves their access tokens valid.
+// A caller running it in a transaction revokes the returned sessions once it commits,
+// a revocation can't be rolled back.
+func (s *service) DeleteAll(ctx context.Context, userID int64) ([]model.Session, error) {
+	sessions, err := s.sessionRepository.DeleteByUserID(ctx, userID)
 	if err != nil {
-		l.Error("Failed to hash refresh token", "error", err.Error())
-		return "", "", sessionservice.ErrInternal
+		return nil, sessionservice.ErrInternal
 	}
 
-	session := model.Session{
-		UserID:           userID,
-		HashRefreshToken: hashRefreshToken,
-		ExpiresAt:        refreshTokenExpiresAt,
+	return sessions, nil
+}
+
+// RevokeAccessTokens makes the last access tokens issued for the sessions unusable.
+func (s *service) RevokeAccessTokens(sessions []model.Session) {
+	for _, session := range sessions {
+		s.revokeAccessToken(session)
 	}
+}
 
-	//RESET SESSION
-	err = s.sessionRepository.ResetSession(ctx, session)
-	if err != nil {
-		return "", "", sessionservice.ErrInternal
+// LogOut revokes the presented access token and ends the session it was issued for, if any.
+func (s *service) LogOut(ctx context.Context, claims tokenmanager.Claims) error {
+	if claims.ExpiresAt != nil {
+		s.revoked.Revoke(claims.ID, claims.ExpiresAt.Time)
 	}
 
-	return accessToken, refreshToken, nil
+	if claims.SessionID == 0 {
+		return nil
+	}
+
+	if err := s.Revoke(ctx, claims.UserID, claims.SessionID); err != nil && !errors.Is(err, sessionservice.ErrNoSession) {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteExpired deletes the expired sessions batch by batch and returns how many were deleted.
+// The used refresh tokens of a session are only kept to detect a replay while it is alive.
+func (s *service) DeleteExpired(ctx context.Context) (int, error) {
+	deleted := 0
+
+	for {
+		sessions, err := s.sessionRepository.DeleteExpired(ctx, time.Now(), expiredBatchSize)
+		if err != nil {
+			return deleted, sessionservice.ErrInternal
+		}
+
+		s.RevokeAccessTokens(sessions)
+		deleted += len(sessions)
+
+		if len(sessions) < expiredBatchSize {
+			return deleted, nil
+		}
+	}
 }
 
-func New(sessionRepository sessionrepository.Repository, tokenManager tokenmanager.Manager, logger *slog.Logger) sessionservice.Service {
+func New(sessionRepository sessionrepository.Repository, userRepository userrepository.Repository, txManager transaction.Manager, tokenManager tokenmanager.Manager, revoked revocation.List, logger *slog.Logger) sessionservice.Service {
 	s := &service{
 		sessionRepository: sessionRepository,
+		userRepository:    userRepository,
+		txManager:         txManager,
 		tokenManager:      tokenManager,
+		revoked:           revoked,
 		logger:            logger,
 	}
 	return s
@@ -0,0 +1,447 @@
+package sessionserviceimpl
+
+import (
+	"avito/internal/model"
+	sessionrepository "avito/internal/repository/session"
+	userrepository "avito/internal/repository/user"
+	sessionservice "avito/internal/service/session"
+	"avito/internal/transaction"
+	"avito/pkg/hasher"
+	"avito/pkg/revocation"
+	"avito/pkg/revocation/memory"
+	stubwriter "avito/pkg/stub_writer"
+	tokenmanager "avito/pkg/token_manager"
+	"context"
+	"errors"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestCreate(t *testing.T) {
+	ctrl, mockSessionRepository, _, mockTokenManager, _, s := testService(t)
+	defer ctrl.Finish()
+
+	expiresAt := time.Now().Add(time.Hour)
+
+	mockTokenManager.EXPECT().GenerateRefreshToken().Return("refresh", expiresAt, nil)
+	//ONLY THE DIGEST OF THE REFRESH TOKEN IS STORED
+	mockSessionRepository.EXPECT().Create(gomock.Any(), model.Session{UserID: 1, HashRefreshToken: hasher.Digest("refresh"), ExpiresAt: expiresAt, UserAgent: "phone", IP: "10.0.0.1"}).Return(model.Session{SessionID: 3, UserID: 1}, nil)
+	//THE ACCESS TOKEN IS BOUND TO THE CREATED SESSION
+	mockTokenManager.EXPECT().GenerateAccessToken(int64(1), "client", int64(3)).Return("access", accessClaims("access-id", expiresAt), nil)
+	//THE SESSION REMEMBERS THE ACCESS TOKEN TO REVOKE IT WITH THE SESSION
+	mockSessionRepository.EXPECT().BindAccessToken(gomock.Any(), int64(3), "access-id", expiresAt.Truncate(time.Second)).Return(nil)
+
+	accessToken, refreshToken, err := s.Create(context.Background(), 1, "client", model.Device{UserAgent: "phone", IP: "10.0.0.1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "access", accessToken)
+	assert.Equal(t, "refresh", refreshToken)
+}
+
+func TestCreateErr(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour)
+
+	cases := []struct {
+		name        string
+		prepareFunc func(mockSessionRepository *sessionrepository.MockRepository, mockTokenManager *tokenmanager.MockManager)
+	}{
+		{
+			name: "ERR CREATE",
+			prepareFunc: func(mockSessionRepository *sessionrepository.MockRepository, mockTokenManager *tokenmanager.MockManager) {
+				mockSessionRepository.EXPECT().Create(gomock.Any(), gomock.Any()).Return(model.Session{}, sessionrepository.ErrInternal)
+			},
+		},
+		{
+			name: "ERR ACCESS TOKEN",
+			prepareFunc: func(mockSessionRepository *sessionrepository.MockRepository, mockTokenManager *tokenmanager.MockManager) {
+				mockSessionRepository.EXPECT().Create(gomock.Any(), gomock.Any()).Return(model.Session{SessionID: 3, UserID: 1}, nil)
+				mockTokenManager.EXPECT().GenerateAccessToken(gomock.Any(), gomock.Any(), gomock.Any()).Return("", tokenmanager.Claims{}, errors.New("sign error"))
+			},
+		},
+		{
+			//THE SESSION ISN'T LEFT WITHOUT AN ACCESS TOKEN ID TO REVOKE
+			name: "ERR BIND ACCESS TOKEN",
+			prepareFunc: func(mockSessionRepository *sessionrepository.MockRepository, mockTokenManager *tokenmanager.MockManager) {
+				mockSessionRepository.EXPECT().Create(gomock.Any(), gomock.Any()).Return(model.Session{SessionID: 3, UserID: 1}, nil)
+				mockTokenManager.EXPECT().GenerateAccessToken(gomock.Any(), gomock.Any(), gomock.Any()).Return("access", accessClaims("access-id", expiresAt), nil)
+				mockSessionRepository.EXPECT().BindAccessToken(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(sessionrepository.ErrInternal)
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockSessionRepository := sessionrepository.NewMockRepository(ctrl)
+			mockTokenManager := tokenmanager.NewMockManager(ctrl)
+			mockTxManager := transaction.NewMockManager(ctrl)
+			logger := slog.New(slog.NewTextHandler(&stubwriter.Writer{}, nil))
+
+			//THE TRANSACTION IS ROLLED BACK
+			mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+				err := fn(ctx)
+				assert.Error(t, err)
+				return err
+			})
+			mockTokenManager.EXPECT().GenerateRefreshToken().Return("refresh", expiresAt, nil)
+			c.prepareFunc(mockSessionRepository, mockTokenManager)
+
+			s := New(mockSessionRepository, userrepository.NewMockRepository(ctrl), mockTxManager, mockTokenManager, memory.New(0), logger)
+
+			accessToken, refreshToken, err := s.Create(context.Background(), 1, "client", model.Device{})
+			assert.ErrorIs(t, err, sessionservice.ErrInternal)
+			assert.Empty(t, accessToken)
+			assert.Empty(t, refreshToken)
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	ctrl, mockSessionRepository, mockUserRepository, mockTokenManager, revoked, s := testService(t)
+	defer ctrl.Finish()
+
+	expiresAt := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	mockSessionRepository.EXPECT().SessionByRefreshToken(gomock.Any(), hasher.Digest("refresh")).Return(model.Session{SessionID: 7, UserID: 1, HashRefreshToken: hasher.Digest("refresh"), ExpiresAt: expiresAt, AccessTokenID: "old-access-id", AccessTokenExpiresAt: expiresAt}, nil)
+	//THE ROLE IS TAKEN FROM THE STORED USER
+	mockUserRepository.EXPECT().UserByID(gomock.Any(), int64(1)).Return(model.User{ID: 1, Role: "client"}, nil)
+	mockTokenManager.EXPECT().GenerateRefreshToken().Return("new-refresh", expiresAt, nil)
+	mockTokenManager.EXPECT().GenerateAccessToken(int64(1), "client", int64(7)).Return("access", accessClaims("access-id", expiresAt), nil)
+	//THE PRESENTED TOKEN IS ROTATED OUT WITHIN THE SAME SESSION
+	mockSessionRepository.EXPECT().Rotate(gomock.Any(), model.Session{SessionID: 7, UserID: 1, HashRefreshToken: hasher.Digest("new-refresh"), ExpiresAt: expiresAt, AccessTokenID: "access-id", AccessTokenExpiresAt: expiresAt}, hasher.Digest("refresh")).Return(nil)
+
+	accessToken, refreshToken, err := s.Update(context.Background(), 1, 7, "refresh")
+	assert.NoError(t, err)
+	assert.Equal(t, "access", accessToken)
+	assert.Equal(t, "new-refresh", refreshToken)
+
+	//THE PREVIOUS ACCESS TOKEN OF THE SESSION IS REVOKED
+	assert.True(t, revoked.Revoked("old-access-id"))
+	assert.False(t, revoked.Revoked("access-id"))
+}
+
+func TestUpdateReuse(t *testing.T) {
+	ctrl, mockSessionRepository, _, _, revoked, s := testService(t)
+	defer ctrl.Finish()
+
+	//A USED TOKEN REVOKES THE WHOLE FAMILY
+	mockSessionRepository.EXPECT().SessionByRefreshToken(gomock.Any(), hasher.Digest("refresh")).Return(model.Session{}, sessionrepository.ErrNoSession)
+	mockSessionRepository.EXPECT().SessionByUsedRefreshToken(gomock.Any(), hasher.Digest("refresh")).Return(model.Session{SessionID: 7, UserID: 1}, nil)
+	mockSessionRepository.EXPECT().Delete(gomock.Any(), int64(1), int64(7)).Return(model.Session{SessionID: 7, UserID: 1, AccessTokenID: "access-id", AccessTokenExpiresAt: time.Now().Add(time.Hour)}, nil)
+
+	_, _, err := s.Update(context.Background(), 1, 7, "refresh")
+	assert.ErrorIs(t, err, sessionservice.ErrRefreshTokenReused)
+	assert.True(t, revoked.Revoked("access-id"))
+}
+
+func TestUpdateErr(t *testing.T) {
+	session := model.Session{SessionID: 7, UserID: 1, HashRefreshToken: hasher.Digest("refresh"), ExpiresAt: time.Now().Add(time.Hour)}
+
+	cases := []struct {
+		name        string
+		userID      int64
+		sessionID   int64
+		expectedErr error
+		prepareFunc func(mockSessionRepository *sessionrepository.MockRepository, mockUserRepository *userrepository.MockRepository, mockTokenManager *tokenmanager.MockManager)
+	}{
+		{
+			name:        "ERR UNKNOWN REFRESH TOKEN",
+			userID:      1,
+			sessionID:   7,
+			expectedErr: sessionservice.ErrInvalidRefreshToken,
+			prepareFunc: func(mockSessionRepository *sessionrepository.MockRepository, mockUserRepository *userrepository.MockRepository, mockTokenManager *tokenmanager.MockManager) {
+				mockSessionRepository.EXPECT().SessionByRefreshToken(gomock.Any(), gomock.Any()).Return(model.Session{}, sessionrepository.ErrNoSession)
+				mockSessionRepository.EXPECT().SessionByUsedRefreshToken(gomock.Any(), gomock.Any()).Return(model.Session{}, sessionrepository.ErrNoSession)
+			},
+		},
+		{
+			name:        "ERR ANOTHER USER",
+			userID:      2,
+			sessionID:   7,
+			expectedErr: sessionservice.ErrInvalidRefreshToken,
+			prepareFunc: func(mockSessionRepository *sessionrepository.MockRepository, mockUserRepository *userrepository.MockRepository, mockTokenManager *tokenmanager.MockManager) {
+				mockSessionRepository.EXPECT().SessionByRefreshToken(gomock.Any(), gomock.Any()).Return(session, nil)
+			},
+		},
+		{
+			//THE REFRESH TOKEN OF ONE DEVICE CAN'T BE USED WITH THE ACCESS TOKEN OF ANOTHER
+			name:        "ERR ANOTHER SESSION",
+			userID:      1,
+			sessionID:   8,
+			expectedErr: sessionservice.ErrInvalidRefreshToken,
+			prepareFunc: func(mockSessionRepository *sessionrepository.MockRepository, mockUserRepository *userrepository.MockRepository, mockTokenManager *tokenmanager.MockManager) {
+				mockSessionRepository.EXPECT().SessionByRefreshToken(gomock.Any(), gomock.Any()).Return(session, nil)
+			},
+		},
+		{
+			name:        "ERR EXPIRED REFRESH TOKEN",
+			userID:      1,
+			sessionID:   7,
+			expectedErr: sessionservice.ErrInvalidRefreshToken,
+			prepareFunc: func(mockSessionRepository *sessionrepository.MockRepository, mockUserRepository *userrepository.MockRepository, mockTokenManager *tokenmanager.MockManager) {
+				expired := session
+				expired.ExpiresAt = time.Now().Add(-time.Minute)
+				mockSessionRepository.EXPECT().SessionByRefreshToken(gomock.Any(), gomock.Any()).Return(expired, nil)
+			},
+		},
+		{
+			name:        "ERR USER NOT FOUND",
+			userID:      1,
+			sessionID:   7,
+			expectedErr: sessionservice.ErrNoSession,
+			prepareFunc: func(mockSessionRepository *sessionrepository.MockRepository, mockUserRepository *userrepository.MockRepository, mockTokenManager *tokenmanager.MockManager) {
+				mockSessionRepository.EXPECT().SessionByRefreshToken(gomock.Any(), gomock.Any()).Return(session, nil)
+				mockUserRepository.EXPECT().UserByID(gomock.Any(), int64(1)).Return(model.User{}, userrepository.ErrUserNotFound)
+			},
+		},
+		{
+			name:        "ERR USER REPOSITORY INTERNAL",
+			userID:      1,
+			sessionID:   7,
+			expectedErr: sessionservice.ErrInternal,
+			prepareFunc: func(mockSessionRepository *sessionrepository.MockRepository, mockUserRepository *userrepository.MockRepository, mockTokenManager *tokenmanager.MockManager) {
+				mockSessionRepository.EXPECT().SessionByRefreshToken(gomock.Any(), gomock.Any()).Return(session, nil)
+				mockUserRepository.EXPECT().UserByID(gomock.Any(), int64(1)).Return(model.User{}, userrepository.ErrInternal)
+			},
+		},
+		{
+			//A CONCURRENT REFRESH ROTATED THE TOKEN FIRST
+			name:        "ERR LOST ROTATION",
+			userID:      1,
+			sessionID:   7,
+			expectedErr: sessionservice.ErrRefreshTokenReused,
+			prepareFunc: func(mockSessionRepository *sessionrepository.MockRepository, mockUserRepository *userrepository.MockRepository, mockTokenManager *tokenmanager.MockManager) {
+				mockSessionRepository.EXPECT().SessionByRefreshToken(gomock.Any(), gomock.Any()).Return(session, nil)
+				mockUserRepository.EXPECT().UserByID(gomock.Any(), int64(1)).Return(model.User{ID: 1, Role: "client"}, nil)
+				mockTokenManager.EXPECT().GenerateAccessToken(gomock.Any(), gomock.Any(), gomock.Any()).Return("access", accessClaims("access-id", time.Now().Add(time.Hour)), nil)
+				mockTokenManager.EXPECT().GenerateRefreshToken().Return("new-refresh", time.Now().Add(time.Hour), nil)
+				mockSessionRepository.EXPECT().Rotate(gomock.Any(), gomock.Any(), gomock.Any()).Return(sessionrepository.ErrNoSession)
+				mockSessionRepository.EXPECT().SessionByUsedRefreshToken(gomock.Any(), gomock.Any()).Return(session, nil)
+				mockSessionRepository.EXPECT().Delete(gomock.Any(), int64(1), int64(7)).Return(session, nil)
+			},
+		},
+		{
+			name:        "ERR SESSION REPOSITORY INTERNAL",
+			userID:      1,
+			sessionID:   7,
+			expectedErr: sessionservice.ErrInternal,
+			prepareFunc: func(mockSessionRepository *sessionrepository.MockRepository, mockUserRepository *userrepository.MockRepository, mockTokenManager *tokenmanager.MockManager) {
+				mockSessionRepository.EXPECT().SessionByRefreshToken(gomock.Any(), gomock.Any()).Return(model.Session{}, sessionrepository.ErrInternal)
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctrl, mockSessionRepository, mockUserRepository, mockTokenManager, _, s := testService(t)
+			defer ctrl.Finish()
+
+			c.prepareFunc(mockSessionRepository, mockUserRepository, mockTokenManager)
+
+			_, _, err := s.Update(context.Background(), c.userID, c.sessionID, "refresh")
+			assert.ErrorIs(t, err, c.expectedErr)
+		})
+	}
+}
+
+func TestRevoke(t *testing.T) {
+	cases := []struct {
+		name        string
+		session     model.Session
+		repoErr     error
+		expectedErr error
+	}{
+		{
+			name:    "OK",
+			session: model.Session{SessionID: 2, UserID: 1, AccessTokenID: "access-id", AccessTokenExpiresAt: time.Now().Add(time.Hour)},
+		},
+		{
+			name:        "ERR NO SESSION",
+			repoErr:     sessionrepository.ErrNoSession,
+			expectedErr: sessionservice.ErrNoSession,
+		},
+		{
+			name:        "ERR INTERNAL",
+			repoErr:     sessionrepository.ErrInternal,
+			expectedErr: sessionservice.ErrInternal,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctrl, mockSessionRepository, _, _, revoked, s := testService(t)
+			defer ctrl.Finish()
+
+			//ONLY A SESSION OF THE USER ITSELF CAN BE REVOKED
+			mockSessionRepository.EXPECT().Delete(gomock.Any(), int64(1), int64(2)).Return(c.session, c.repoErr)
+
+			err := s.Revoke(context.Background(), 1, 2)
+			if c.expectedErr == nil {
+				assert.NoError(t, err)
+				assert.True(t, revoked.Revoked("access-id"))
+				return
+			}
+			assert.ErrorIs(t, err, c.expectedErr)
+		})
+	}
+}
+
+func TestRevokeAll(t *testing.T) {
+	ctrl, mockSessionRepository, _, _, revoked, s := testService(t)
+	defer ctrl.Finish()
+
+	expiresAt := time.Now().Add(time.Hour)
+
+	mockSessionRepository.EXPECT().DeleteByUserID(gomock.Any(), int64(1)).Return([]model.Session{
+		{SessionID: 2, UserID: 1, AccessTokenID: "first-access-id", AccessTokenExpiresAt: expiresAt},
+		{SessionID: 3, UserID: 1, AccessTokenID: "second-access-id", AccessTokenExpiresAt: expiresAt},
+	}, nil)
+
+	assert.NoError(t, s.RevokeAll(context.Background(), 1))
+
+	//THE ACCESS OF EVERY DEVICE IS CUT OFF AT ONCE
+	assert.True(t, revoked.Revoked("first-access-id"))
+	assert.True(t, revoked.Revoked("second-access-id"))
+}
+
+func TestDeleteAll(t *testing.T) {
+	ctrl, mockSessionRepository, _, _, revoked, s := testService(t)
+	defer ctrl.Finish()
+
+	sessions := []model.Session{{SessionID: 2, UserID: 1, AccessTokenID: "access-id", AccessTokenExpiresAt: time.Now().Add(time.Hour)}}
+
+	mockSessionRepository.EXPECT().DeleteByUserID(gomock.Any(), int64(1)).Return(sessions, nil)
+
+	deleted, err := s.DeleteAll(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, sessions, deleted)
+
+	//THE ACCESS TOKENS ARE LEFT TO THE CALLER TO REVOKE
+	assert.False(t, revoked.Revoked("access-id"))
+
+	s.RevokeAccessTokens(deleted)
+	assert.True(t, revoked.Revoked("access-id"))
+}
+
+func TestDeleteExpired(t *testing.T) {
+	ctrl, mockSessionRepository, _, _, revoked, s := testService(t)
+	defer ctrl.Finish()
+
+	expiresAt := time.Now().Add(time.Minute)
+
+	full := make([]model.Session, expiredBatchSize)
+	full[0] = model.Session{SessionID: 1, AccessTokenID: "first-access-id", AccessTokenExpiresAt: expiresAt}
+
+	//A FULL BATCH MEANS MORE EXPIRED SESSIONS MAY BE LEFT
+	gomock.InOrder(
+		mockSessionRepository.EXPECT().DeleteExpired(gomock.Any(), gomock.Any(), expiredBatchSize).Return(full, nil),
+		mockSessionRepository.EXPECT().DeleteExpired(gomock.Any(), gomock.Any(), expiredBatchSize).Return([]model.Session{{SessionID: 2, AccessTokenID: "second-access-id", AccessTokenExpiresAt: expiresAt}}, nil),
+	)
+
+	deleted, err := s.DeleteExpired(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, expiredBatchSize+1, deleted)
+
+	//AN ACCESS TOKEN OUTLIVING ITS SESSION IS CUT OFF TOO
+	assert.True(t, revoked.Revoked("first-access-id"))
+	assert.True(t, revoked.Revoked("second-access-id"))
+}
+
+func TestDeleteExpiredErr(t *testing.T) {
+	ctrl, mockSessionRepository, _, _, _, s := testService(t)
+	defer ctrl.Finish()
+
+	mockSessionRepository.EXPECT().DeleteExpired(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, sessionrepository.ErrInternal)
+
+	_, err := s.DeleteExpired(context.Background())
+	assert.ErrorIs(t, err, sessionservice.ErrInternal)
+}
+
+func TestLogOut(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour)
+
+	cases := []struct {
+		name        string
+		sessionID   int64
+		prepareFunc func(mockSessionRepository *sessionrepository.MockRepository)
+		expectedErr error
+	}{
+		{
+			name:      "OK",
+			sessionID: 2,
+			prepareFunc: func(mockSessionRepository *sessionrepository.MockRepository) {
+				mockSessionRepository.EXPECT().Delete(gomock.Any(), int64(1), int64(2)).Return(model.Session{SessionID: 2, UserID: 1, AccessTokenID: "access-id", AccessTokenExpiresAt: expiresAt}, nil)
+			},
+		},
+		{
+			//THE SESSION COULD BE REVOKED FROM ANOTHER DEVICE ALREADY
+			name:      "OK NO SESSION",
+			sessionID: 2,
+			prepareFunc: func(mockSessionRepository *sessionrepository.MockRepository) {
+				mockSessionRepository.EXPECT().Delete(gomock.Any(), int64(1), int64(2)).Return(model.Session{}, sessionrepository.ErrNoSession)
+			},
+		},
+		{
+			//A DUMMY TOKEN HAS NO SESSION
+			name:        "OK WITHOUT SESSION",
+			prepareFunc: func(mockSessionRepository *sessionrepository.MockRepository) {},
+		},
+		{
+			name:      "ERR INTERNAL",
+			sessionID: 2,
+			prepareFunc: func(mockSessionRepository *sessionrepository.MockRepository) {
+				mockSessionRepository.EXPECT().Delete(gomock.Any(), int64(1), int64(2)).Return(model.Session{}, sessionrepository.ErrInternal)
+			},
+			expectedErr: sessionservice.ErrInternal,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctrl, mockSessionRepository, _, _, revoked, s := testService(t)
+			defer ctrl.Finish()
+
+			c.prepareFunc(mockSessionRepository)
+
+			claims := accessClaims("access-id", expiresAt)
+			claims.UserID = 1
+			claims.SessionID = c.sessionID
+
+			err := s.LogOut(context.Background(), claims)
+			if c.expectedErr != nil {
+				assert.ErrorIs(t, err, c.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			//THE PRESENTED TOKEN IS REVOKED IN ANY CASE
+			assert.True(t, revoked.Revoked("access-id"))
+		})
+	}
+}
+
+func testService(t *testing.T) (ctrl *gomock.Controller, mockSessionRepository *sessionrepository.MockRepository, mockUserRepository *userrepository.MockRepository, mockTokenManager *tokenmanager.MockManager, revoked revocation.List, s sessionservice.Service) {
+	ctrl = gomock.NewController(t)
+
+	mockSessionRepository = sessionrepository.NewMockRepository(ctrl)
+	mockUserRepository = userrepository.NewMockRepository(ctrl)
+	mockTokenManager = tokenmanager.NewMockManager(ctrl)
+	revoked = memory.New(0)
+	logger := slog.New(slog.NewTextHandler(&stubwriter.Writer{}, nil))
+
+	mockTxManager := transaction.NewMockManager(ctrl)
+	mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	}).AnyTimes()
+
+	return ctrl, mockSessionRepository, mockUserRepository, mockTokenManager, revoked, New(mockSessionRepository, mockUserRepository, mockTxManager, mockTokenManager, revoked, logger)
+}
+
+func accessClaims(tokenID string, expiresAt time.Time) tokenmanager.Claims {
+	return tokenmanager.Claims{RegisteredClaims: jwt.RegisteredClaims{ID: tokenID, ExpiresAt: jwt.NewNumericDate(expiresAt)}}
+}
@@ -1,11 +1,19 @@
 package sessionservice
 
 import (
+	"avito/internal/model"
+	tokenmanager "avito/pkg/token_manager"
 	"context"
 )
 
 type Service interface {
-	Create(ctx context.Context, userID uint32, role string) (accessToken, refreshToken string, err error)
-	Update(ctx context.Context, userID uint32, role string, expiredRefreshToken string) (accessToken, refreshToken string, err error)
-	ResetSession(ctx context.Context, userID uint32, role string) (accessToken, refreshToken string, err error)
+	Create(ctx context.Context, userID int64, role string, device model.Device) (accessToken, refreshToken string, err error)
+	Update(ctx context.Context, userID, sessionID int64, refreshToken string) (accessToken, newRefreshToken string, err error)
+	Sessions(ctx context.Context, userID int64) ([]model.Session, error)
+	Revoke(ctx context.Context, userID, sessionID int64) error
+	RevokeAll(ctx context.Context, userID int64) error
+	DeleteAll(ctx context.Context, userID int64) ([]model.Session, error)
+	RevokeAccessTokens(sessions []model.Session)
+	DeleteExpired(ctx context.Context) (int, error)
+	LogOut(ctx context.Context, claims tokenmanager.Claims) error
 }
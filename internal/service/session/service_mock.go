@@ -10,6 +10,8 @@
 package sessionservice
 
 import (
+	model "avito/internal/model"
+	tokenmanager "avito/pkg/token_manager"
 	context "context"
 	reflect "reflect"
 
@@ -40,9 +42,9 @@ func (m *MockService) EXPECT() *MockServiceMockRecorder {
 }
 
 // Create mocks base method.
-func (m *MockService) Create(ctx context.Context, userID uint32, role string) (string, string, error) {
+func (m *MockService) Create(ctx context.Context, userID int64, role string, device model.Device) (string, string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", ctx, userID, role)
+	ret := m.ctrl.Call(m, "Create", ctx, userID, role, device)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(string)
 	ret2, _ := ret[2].(error)
@@ -50,31 +52,114 @@ func (m *MockService) Create(ctx context.Context, userID uint32, role string) (s
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockServiceMockRecorder) Create(ctx, userID, role any) *gomock.Call {
+func (mr *MockServiceMockRecorder) Create(ctx, userID, role, device any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockService)(nil).Create), ctx, userID, role)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockService)(nil).Create), ctx, userID, role, device)
 }
 
-// ResetSession mocks base method.
-func (m *MockService) ResetSession(ctx context.Context, userID uint32, role string) (string, string, error) {
+// DeleteAll mocks base method.
+func (m *MockService) DeleteAll(ctx context.Context, userID int64) ([]model.Session, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ResetSession", ctx, userID, role)
-	ret0, _ := ret[0].(string)
-	ret1, _ := ret[1].(string)
-	ret2, _ := ret[2].(error)
-	return ret0, ret1, ret2
+	ret := m.ctrl.Call(m, "DeleteAll", ctx, userID)
+	ret0, _ := ret[0].([]model.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteAll indicates an expected call of DeleteAll.
+func (mr *MockServiceMockRecorder) DeleteAll(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAll", reflect.TypeOf((*MockService)(nil).DeleteAll), ctx, userID)
+}
+
+// DeleteExpired mocks base method.
+func (m *MockService) DeleteExpired(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpired", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpired indicates an expected call of DeleteExpired.
+func (mr *MockServiceMockRecorder) DeleteExpired(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpired", reflect.TypeOf((*MockService)(nil).DeleteExpired), ctx)
+}
+
+// LogOut mocks base method.
+func (m *MockService) LogOut(ctx context.Context, claims tokenmanager.Claims) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LogOut", ctx, claims)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LogOut indicates an expected call of LogOut.
+func (mr *MockServiceMockRecorder) LogOut(ctx, claims any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogOut", reflect.TypeOf((*MockService)(nil).LogOut), ctx, claims)
+}
+
+// Revoke mocks base method.
+func (m *MockService) Revoke(ctx context.Context, userID, sessionID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", ctx, userID, sessionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockServiceMockRecorder) Revoke(ctx, userID, sessionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockService)(nil).Revoke), ctx, userID, sessionID)
+}
+
+// RevokeAccessTokens mocks base method.
+func (m *MockService) RevokeAccessTokens(sessions []model.Session) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RevokeAccessTokens", sessions)
+}
+
+// RevokeAccessTokens indicates an expected call of RevokeAccessTokens.
+func (mr *MockServiceMockRecorder) RevokeAccessTokens(sessions any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAccessTokens", reflect.TypeOf((*MockService)(nil).RevokeAccessTokens), sessions)
+}
+
+// RevokeAll mocks base method.
+func (m *MockService) RevokeAll(ctx context.Context, userID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAll", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAll indicates an expected call of RevokeAll.
+func (mr *MockServiceMockRecorder) RevokeAll(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAll", reflect.TypeOf((*MockService)(nil).RevokeAll), ctx, userID)
+}
+
+// Sessions mocks base method.
+func (m *MockService) Sessions(ctx context.Context, userID int64) ([]model.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Sessions", ctx, userID)
+	ret0, _ := ret[0].([]model.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// ResetSession indicates an expected call of ResetSession.
-func (mr *MockServiceMockRecorder) ResetSession(ctx, userID, role any) *gomock.Call {
+// Sessions indicates an expected call of Sessions.
+func (mr *MockServiceMockRecorder) Sessions(ctx, userID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetSession", reflect.TypeOf((*MockService)(nil).ResetSession), ctx, userID, role)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sessions", reflect.TypeOf((*MockService)(nil).Sessions), ctx, userID)
 }
 
 // Update mocks base method.
-func (m *MockService) Update(ctx context.Context, userID uint32, role, expiredRefreshToken string) (string, string, error) {
+func (m *MockService) Update(ctx context.Context, userID, sessionID int64, refreshToken string) (string, string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", ctx, userID, role, expiredRefreshToken)
+	ret := m.ctrl.Call(m, "Update", ctx, userID, sessionID, refreshToken)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(string)
 	ret2, _ := ret[2].(error)
@@ -82,7 +167,7 @@ func (m *MockService) Update(ctx context.Context, userID uint32, role, expiredRe
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockServiceMockRecorder) Update(ctx, userID, role, expiredRefreshToken any) *gomock.Call {
+func (mr *MockServiceMockRecorder) Update(ctx, userID, sessionID, refreshToken any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockService)(nil).Update), ctx, userID, role, expiredRefreshToken)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockService)(nil).Update), ctx, userID, sessionID, refreshToken)
 }
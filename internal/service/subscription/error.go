@@ -0,0 +1,8 @@
+package subscriptionservice
+
+import "errors"
+
+var (
+	ErrInternal      = errors.New("internal server error")
+	ErrHouseNotFound = errors.New("house not found")
+)
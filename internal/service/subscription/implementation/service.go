@@ -0,0 +1,47 @@
+package subscriptionserviceimpl
+
+import (
+	"avito/internal/model"
+	subscriptionrepository "avito/internal/repository/subscription"
+	subscriptionservice "avito/internal/service/subscription"
+	"context"
+	"errors"
+	"log/slog"
+)
+
+type service struct {
+	rep subscriptionrepository.Repository
+
+	logger *slog.Logger
+}
+
+func (s *service) Subscribe(ctx context.Context, subscription model.Subscription) (model.Subscription, error) {
+	created, err := s.rep.Create(ctx, subscription)
+	if err != nil {
+		switch {
+		case errors.Is(err, subscriptionrepository.ErrHouseNotFound):
+			return model.Subscription{}, subscriptionservice.ErrHouseNotFound
+		default:
+			return model.Subscription{}, subscriptionservice.ErrInternal
+		}
+	}
+
+	return created, nil
+}
+
+func (s *service) Subscriptions(ctx context.Context, houseID int64) ([]model.Subscription, error) {
+	subscriptions, err := s.rep.SubscriptionsByHouseID(ctx, houseID)
+	if err != nil {
+		return nil, subscriptionservice.ErrInternal
+	}
+
+	return subscriptions, nil
+}
+
+func New(rep subscriptionrepository.Repository, logger *slog.Logger) subscriptionservice.Service {
+	s := &service{
+		rep:    rep,
+		logger: logger,
+	}
+	return s
+}
@@ -0,0 +1,11 @@
+package subscriptionservice
+
+import (
+	"avito/internal/model"
+	"context"
+)
+
+type Service interface {
+	Subscribe(ctx context.Context, subscription model.Subscription) (model.Subscription, error)
+	Subscriptions(ctx context.Context, houseID int64) ([]model.Subscription, error)
+}
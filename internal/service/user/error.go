@@ -6,4 +6,5 @@ var (
 	ErrCredentialsInvalid = errors.New("invalid email or password")
 	ErrInternal           = errors.New("internal server error")
 	ErrEmailAlreadyTaken  = errors.New("email already taken")
+	ErrUserNotFound       = errors.New("user not found")
 )
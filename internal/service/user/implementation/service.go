@@ -3,7 +3,9 @@ package userserviceimpl
 import (
 	"avito/internal/model"
 	userrepository "avito/internal/repository/user"
+	sessionservice "avito/internal/service/session"
 	userservice "avito/internal/service/user"
+	"avito/internal/transaction"
 	"avito/pkg/hasher"
 	tokenmanager "avito/pkg/token_manager"
 	"context"
@@ -12,49 +14,108 @@ import (
 )
 
 type service struct {
-	repository userrepository.Repository
+	repository     userrepository.Repository
+	sessionService sessionservice.Service
+	txManager      transaction.Manager
 
 	tokenManager tokenmanager.Manager
 
 	logger *slog.Logger
 }
 
-func (s *service) Save(ctx context.Context, user model.User) error {
-	if err := s.repository.Save(ctx, user); err != nil {
+// Register saves the user together with the first session, a user is never left without one.
+func (s *service) Register(ctx context.Context, user model.User, device model.Device) (accessToken, refreshToken string, err error) {
+	err = s.txManager.Do(ctx, func(ctx context.Context) error {
+		user, err := s.repository.Save(ctx, user)
+		if err != nil {
+			switch {
+			case errors.Is(err, userrepository.ErrEmailAlreadyTaken):
+				return userservice.ErrEmailAlreadyTaken
+			default:
+				return userservice.ErrInternal
+			}
+		}
+
+		if accessToken, refreshToken, err = s.sessionService.Create(ctx, user.ID, user.Role, device); err != nil {
+			return userservice.ErrInternal
+		}
+
+		return nil
+	})
+	if err != nil {
 		switch {
-		case errors.Is(err, userrepository.ErrEmailAlreadyTaken):
-			return userservice.ErrEmailAlreadyTaken
+		case errors.Is(err, userservice.ErrEmailAlreadyTaken):
+			return "", "", userservice.ErrEmailAlreadyTaken
 		default:
-			return userservice.ErrInternal
+			return "", "", userservice.ErrInternal
 		}
 	}
 
-	return nil
+	return accessToken, refreshToken, nil
 }
 
-func (s *service) LogIn(ctx context.Context, email string, password string) (userID uint32, err error) {
+// LogIn returns the stored user, its role is the only one a session may be issued for.
+func (s *service) LogIn(ctx context.Context, email string, password string) (model.User, error) {
 	user, err := s.repository.UserByEmail(ctx, email)
 	if err != nil {
 		switch {
 		case errors.Is(err, userrepository.ErrUserNotFound):
-			return 0, userservice.ErrCredentialsInvalid
+			return model.User{}, userservice.ErrCredentialsInvalid
 		default:
-			return 0, userservice.ErrInternal
+			return model.User{}, userservice.ErrInternal
 		}
 	}
 
 	if err = hasher.Compare(password, user.HashPassword); err != nil {
-		return 0, userservice.ErrCredentialsInvalid
+		return model.User{}, userservice.ErrCredentialsInvalid
+	}
+
+	return user, nil
+}
+
+// ChangeRole stores the new role and ends every session of the user in the same transaction,
+// the tokens issued for the old role are revoked once it commits and the user has to log in again.
+func (s *service) ChangeRole(ctx context.Context, userID int64, role string) (user model.User, err error) {
+	var sessions []model.Session
+
+	err = s.txManager.Do(ctx, func(ctx context.Context) error {
+		user, err = s.repository.UpdateRole(ctx, userID, role)
+		if err != nil {
+			switch {
+			case errors.Is(err, userrepository.ErrUserNotFound):
+				return userservice.ErrUserNotFound
+			default:
+				return userservice.ErrInternal
+			}
+		}
+
+		if sessions, err = s.sessionService.DeleteAll(ctx, userID); err != nil {
+			return userservice.ErrInternal
+		}
+
+		return nil
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, userservice.ErrUserNotFound):
+			return model.User{}, userservice.ErrUserNotFound
+		default:
+			return model.User{}, userservice.ErrInternal
+		}
 	}
 
-	return user.ID, nil
+	s.sessionService.RevokeAccessTokens(sessions)
+
+	return user, nil
 }
 
-func New(repository userrepository.Repository, tokenManager tokenmanager.Manager, logger *slog.Logger) userservice.Service {
+func New(repository userrepository.Repository, sessionService sessionservice.Service, txManager transaction.Manager, tokenManager tokenmanager.Manager, logger *slog.Logger) userservice.Service {
 	s := &service{
-		repository:   repository,
-		tokenManager: tokenManager,
-		logger:       logger,
+		repository:     repository,
+		sessionService: sessionService,
+		txManager:      txManager,
+		tokenManager:   tokenManager,
+		logger:         logger,
 	}
 	return s
 }
@@ -0,0 +1,236 @@
+package userserviceimpl
+
+import (
+	"avito/internal/model"
+	userrepository "avito/internal/repository/user"
+	sessionservice "avito/internal/service/session"
+	userservice "avito/internal/service/user"
+	"avito/internal/transaction"
+	"avito/pkg/hasher"
+	stubwriter "avito/pkg/stub_writer"
+	tokenmanager "avito/pkg/token_manager"
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"log/slog"
+	"testing"
+)
+
+func TestRegister(t *testing.T) {
+	ctrl, mockRepository, mockSessionService, mockTxManager, s := testService(t)
+	defer ctrl.Finish()
+
+	user := model.User{Role: "client", Email: "test@gmail.com"}
+
+	mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	})
+	//THE SESSION GETS THE ID ASSIGNED BY THE DATABASE
+	mockRepository.EXPECT().Save(gomock.Any(), user).Return(model.User{ID: 1, Role: "client", Email: "test@gmail.com"}, nil)
+	mockSessionService.EXPECT().Create(gomock.Any(), int64(1), user.Role, model.Device{UserAgent: "curl", IP: "127.0.0.1"}).Return("access", "refresh", nil)
+
+	accessToken, refreshToken, err := s.Register(context.Background(), user, model.Device{UserAgent: "curl", IP: "127.0.0.1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "access", accessToken)
+	assert.Equal(t, "refresh", refreshToken)
+}
+
+func TestRegisterErr(t *testing.T) {
+	cases := []struct {
+		name        string
+		expectedErr error
+		prepareFunc func(mockRepository *userrepository.MockRepository, mockSessionService *sessionservice.MockService)
+	}{
+		{
+			name:        "ERR EMAIL ALREADY TAKEN",
+			expectedErr: userservice.ErrEmailAlreadyTaken,
+			prepareFunc: func(mockRepository *userrepository.MockRepository, mockSessionService *sessionservice.MockService) {
+				mockRepository.EXPECT().Save(gomock.Any(), gomock.Any()).Return(model.User{}, userrepository.ErrEmailAlreadyTaken)
+			},
+		},
+		{
+			//THE TRANSACTION IS ROLLED BACK, THE USER ISN'T SAVED WITHOUT A SESSION
+			name:        "ERR SESSION",
+			expectedErr: userservice.ErrInternal,
+			prepareFunc: func(mockRepository *userrepository.MockRepository, mockSessionService *sessionservice.MockService) {
+				mockRepository.EXPECT().Save(gomock.Any(), gomock.Any()).Return(model.User{ID: 1, Role: "client"}, nil)
+				mockSessionService.EXPECT().Create(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return("", "", sessionservice.ErrInternal)
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctrl, mockRepository, mockSessionService, mockTxManager, s := testService(t)
+			defer ctrl.Finish()
+
+			mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+				err := fn(ctx)
+				assert.Error(t, err)
+				return err
+			})
+			c.prepareFunc(mockRepository, mockSessionService)
+
+			_, _, err := s.Register(context.Background(), model.User{Role: "client"}, model.Device{})
+			assert.ErrorIs(t, err, c.expectedErr)
+		})
+	}
+}
+
+func TestLogIn(t *testing.T) {
+	ctrl, mockRepository, _, _, s := testService(t)
+	defer ctrl.Finish()
+
+	hashPassword, err := hasher.Hash("123456")
+	assert.NoError(t, err)
+
+	stored := model.User{ID: 1, Role: "client", Email: "test@gmail.com", HashPassword: hashPassword}
+
+	mockRepository.EXPECT().UserByEmail(gomock.Any(), "test@gmail.com").Return(stored, nil)
+
+	//THE STORED USER IS RETURNED WITH ITS ROLE
+	user, err := s.LogIn(context.Background(), "test@gmail.com", "123456")
+	assert.NoError(t, err)
+	assert.Equal(t, stored, user)
+}
+
+func TestLogInErr(t *testing.T) {
+	hashPassword, err := hasher.Hash("123456")
+	assert.NoError(t, err)
+
+	cases := []struct {
+		name        string
+		password    string
+		expectedErr error
+		prepareFunc func(mockRepository *userrepository.MockRepository)
+	}{
+		{
+			name:        "ERR USER NOT FOUND",
+			password:    "123456",
+			expectedErr: userservice.ErrCredentialsInvalid,
+			prepareFunc: func(mockRepository *userrepository.MockRepository) {
+				mockRepository.EXPECT().UserByEmail(gomock.Any(), gomock.Any()).Return(model.User{}, userrepository.ErrUserNotFound)
+			},
+		},
+		{
+			name:        "ERR WRONG PASSWORD",
+			password:    "654321",
+			expectedErr: userservice.ErrCredentialsInvalid,
+			prepareFunc: func(mockRepository *userrepository.MockRepository) {
+				mockRepository.EXPECT().UserByEmail(gomock.Any(), gomock.Any()).Return(model.User{ID: 1, Role: "client", HashPassword: hashPassword}, nil)
+			},
+		},
+		{
+			name:        "ERR INTERNAL",
+			password:    "123456",
+			expectedErr: userservice.ErrInternal,
+			prepareFunc: func(mockRepository *userrepository.MockRepository) {
+				mockRepository.EXPECT().UserByEmail(gomock.Any(), gomock.Any()).Return(model.User{}, userrepository.ErrInternal)
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctrl, mockRepository, _, _, s := testService(t)
+			defer ctrl.Finish()
+
+			c.prepareFunc(mockRepository)
+
+			user, err := s.LogIn(context.Background(), "test@gmail.com", c.password)
+			assert.ErrorIs(t, err, c.expectedErr)
+			assert.Equal(t, model.User{}, user)
+		})
+	}
+}
+
+func TestChangeRole(t *testing.T) {
+	ctrl, mockRepository, mockSessionService, mockTxManager, s := testService(t)
+	defer ctrl.Finish()
+
+	sessions := []model.Session{{SessionID: 2, UserID: 1, AccessTokenID: "access-id"}}
+
+	committed := false
+	mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+		err := fn(ctx)
+		committed = err == nil
+		return err
+	})
+	mockRepository.EXPECT().UpdateRole(gomock.Any(), int64(1), "moderator").Return(model.User{ID: 1, Role: "moderator"}, nil)
+	mockSessionService.EXPECT().DeleteAll(gomock.Any(), int64(1)).Return(sessions, nil)
+	//THE TOKENS ISSUED FOR THE OLD ROLE ARE REVOKED ONLY AFTER THE COMMIT
+	mockSessionService.EXPECT().RevokeAccessTokens(sessions).Do(func([]model.Session) {
+		assert.True(t, committed)
+	})
+
+	user, err := s.ChangeRole(context.Background(), 1, "moderator")
+	assert.NoError(t, err)
+	assert.Equal(t, model.User{ID: 1, Role: "moderator"}, user)
+}
+
+func TestChangeRoleErr(t *testing.T) {
+	cases := []struct {
+		name        string
+		commitErr   error
+		expectedErr error
+		prepareFunc func(mockRepository *userrepository.MockRepository, mockSessionService *sessionservice.MockService)
+	}{
+		{
+			name:        "ERR USER NOT FOUND",
+			expectedErr: userservice.ErrUserNotFound,
+			prepareFunc: func(mockRepository *userrepository.MockRepository, mockSessionService *sessionservice.MockService) {
+				mockRepository.EXPECT().UpdateRole(gomock.Any(), gomock.Any(), gomock.Any()).Return(model.User{}, userrepository.ErrUserNotFound)
+			},
+		},
+		{
+			//THE TRANSACTION IS ROLLED BACK, THE ROLE ISN'T CHANGED WHILE THE OLD TOKENS ARE ALIVE
+			name:        "ERR DELETE SESSIONS",
+			expectedErr: userservice.ErrInternal,
+			prepareFunc: func(mockRepository *userrepository.MockRepository, mockSessionService *sessionservice.MockService) {
+				mockRepository.EXPECT().UpdateRole(gomock.Any(), gomock.Any(), gomock.Any()).Return(model.User{ID: 1, Role: "moderator"}, nil)
+				mockSessionService.EXPECT().DeleteAll(gomock.Any(), int64(1)).Return(nil, sessionservice.ErrInternal)
+			},
+		},
+		{
+			//THE ROLE STAYS THE SAME, SO THE TOKENS OF THE DELETED SESSIONS AREN'T REVOKED
+			name:        "ERR COMMIT",
+			commitErr:   errors.New("commit failed"),
+			expectedErr: userservice.ErrInternal,
+			prepareFunc: func(mockRepository *userrepository.MockRepository, mockSessionService *sessionservice.MockService) {
+				mockRepository.EXPECT().UpdateRole(gomock.Any(), gomock.Any(), gomock.Any()).Return(model.User{ID: 1, Role: "moderator"}, nil)
+				mockSessionService.EXPECT().DeleteAll(gomock.Any(), int64(1)).Return([]model.Session{{SessionID: 2, UserID: 1, AccessTokenID: "access-id"}}, nil)
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctrl, mockRepository, mockSessionService, mockTxManager, s := testService(t)
+			defer ctrl.Finish()
+
+			mockTxManager.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+				if err := fn(ctx); err != nil {
+					return err
+				}
+				return c.commitErr
+			})
+			c.prepareFunc(mockRepository, mockSessionService)
+
+			user, err := s.ChangeRole(context.Background(), 1, "moderator")
+			assert.ErrorIs(t, err, c.expectedErr)
+			assert.Equal(t, model.User{}, user)
+		})
+	}
+}
+
+func testService(t *testing.T) (ctrl *gomock.Controller, mockRepository *userrepository.MockRepository, mockSessionService *sessionservice.MockService, mockTxManager *transaction.MockManager, s userservice.Service) {
+	ctrl = gomock.NewController(t)
+
+	mockRepository = userrepository.NewMockRepository(ctrl)
+	mockSessionService = sessionservice.NewMockService(ctrl)
+	mockTxManager = transaction.NewMockManager(ctrl)
+	logger := slog.New(slog.NewTextHandler(&stubwriter.Writer{}, nil))
+
+	return ctrl, mockRepository, mockSessionService, mockTxManager, New(mockRepository, mockSessionService, mockTxManager, tokenmanager.NewMockManager(ctrl), logger)
+}
@@ -6,6 +6,7 @@ import (
 )
 
 type Service interface {
-	Save(ctx context.Context, user model.User) error
-	LogIn(ctx context.Context, email, password string) (userID uint32, err error)
+	Register(ctx context.Context, user model.User, device model.Device) (accessToken, refreshToken string, err error)
+	LogIn(ctx context.Context, email, password string) (model.User, error)
+	ChangeRole(ctx context.Context, userID int64, role string) (model.User, error)
 }
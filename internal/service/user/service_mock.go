@@ -40,11 +40,26 @@ func (m *MockService) EXPECT() *MockServiceMockRecorder {
 	return m.recorder
 }
 
+// ChangeRole mocks base method.
+func (m *MockService) ChangeRole(ctx context.Context, userID int64, role string) (model.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangeRole", ctx, userID, role)
+	ret0, _ := ret[0].(model.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChangeRole indicates an expected call of ChangeRole.
+func (mr *MockServiceMockRecorder) ChangeRole(ctx, userID, role any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeRole", reflect.TypeOf((*MockService)(nil).ChangeRole), ctx, userID, role)
+}
+
 // LogIn mocks base method.
-func (m *MockService) LogIn(ctx context.Context, email, password string) (uint32, error) {
+func (m *MockService) LogIn(ctx context.Context, email, password string) (model.User, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "LogIn", ctx, email, password)
-	ret0, _ := ret[0].(uint32)
+	ret0, _ := ret[0].(model.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -55,16 +70,18 @@ func (mr *MockServiceMockRecorder) LogIn(ctx, email, password any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogIn", reflect.TypeOf((*MockService)(nil).LogIn), ctx, email, password)
 }
 
-// Save mocks base method.
-func (m *MockService) Save(ctx context.Context, user model.User) error {
+// Register mocks base method.
+func (m *MockService) Register(ctx context.Context, user model.User, device model.Device) (string, string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Save", ctx, user)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "Register", ctx, user, device)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
-// Save indicates an expected call of Save.
-func (mr *MockServiceMockRecorder) Save(ctx, user any) *gomock.Call {
+// Register indicates an expected call of Register.
+func (mr *MockServiceMockRecorder) Register(ctx, user, device any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockService)(nil).Save), ctx, user)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockService)(nil).Register), ctx, user, device)
 }
@@ -0,0 +1,61 @@
+package transactionpostgres
+
+import (
+	"avito/internal/transaction"
+	"avito/pkg/database"
+	"avito/pkg/logger"
+	"context"
+	"errors"
+	"log/slog"
+)
+
+var _ transaction.Manager = &manager{}
+
+type manager struct {
+	db     *database.DB
+	logger *slog.Logger
+}
+
+// Do commits when fn succeeds and rolls back on its error or panic.
+func (m *manager) Do(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if _, ok := database.TxFromContext(ctx); ok {
+		return fn(ctx)
+	}
+
+	l := logger.EndToEndLogging(ctx, m.logger)
+
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		l.Error("Failed to begin transaction", "error", err.Error())
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err = fn(database.WithTx(ctx, tx)); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			l.Error("Failed to rollback transaction", "error", rbErr.Error())
+			return errors.Join(err, rbErr)
+		}
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		l.Error("Failed to commit transaction", "error", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func New(db *database.DB, logger *slog.Logger) transaction.Manager {
+	return &manager{
+		db:     db,
+		logger: logger,
+	}
+}
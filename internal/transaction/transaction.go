@@ -0,0 +1,9 @@
+package transaction
+
+import "context"
+
+// Manager runs fn in one transaction. Repositories called with the context passed to fn
+// take part in it. A nested call joins the outer transaction.
+type Manager interface {
+	Do(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -11,6 +11,7 @@ const (
 	PasswordTag         = "password"
 	RoleTag             = "role"
 	ModerationStatusTag = "moderation_status"
+	DeclineReasonTag    = "decline_reason"
 )
 
 var (
@@ -18,6 +19,7 @@ var (
 	ErrInvalidPassword         = errors.New("password validation failed. length should be at least 6 characters")
 	ErrInvalidRole             = errors.New("invalid role. possible roles: client, moderator")
 	ErrInvalidModerationStatus = errors.New("invalid moderation status. possible status: created, approved, declined, on moderation")
+	ErrInvalidDeclineReason    = errors.New("invalid decline reason. possible reasons: prohibited_content, wrong_price, duplicate")
 )
 
 type Validate struct {
@@ -39,6 +41,8 @@ func (v *Validate) Validate(model any) (resErr error) {
 					resErr = multierror.Append(resErr, ErrInvalidRole)
 				case ModerationStatusTag:
 					resErr = multierror.Append(resErr, ErrInvalidModerationStatus)
+				case DeclineReasonTag:
+					resErr = multierror.Append(resErr, ErrInvalidDeclineReason)
 				default:
 					resErr = multierror.Append(resErr, err)
 				}
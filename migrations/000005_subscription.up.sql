@@ -0,0 +1,7 @@
+CREATE TABLE subscriptions (
+    subscription_id BIGINT PRIMARY KEY,
+    house_id        BIGINT       NOT NULL REFERENCES houses (house_id),
+    email           VARCHAR(255) NOT NULL,
+    created_at      TIMESTAMP DEFAULT NOW(),
+    UNIQUE (house_id, email)
+);
@@ -0,0 +1 @@
+ALTER TABLE apartments DROP COLUMN IF EXISTS moderator_id;
\ No newline at end of file
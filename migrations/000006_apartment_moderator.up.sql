@@ -0,0 +1 @@
+ALTER TABLE apartments ADD COLUMN moderator_id BIGINT;
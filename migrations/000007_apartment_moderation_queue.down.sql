@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS apartments_moderation_queue_idx;
+ALTER TABLE apartments DROP COLUMN IF EXISTS created_at;
\ No newline at end of file
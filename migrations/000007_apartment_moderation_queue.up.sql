@@ -0,0 +1,3 @@
+ALTER TABLE apartments ADD COLUMN created_at TIMESTAMP NOT NULL DEFAULT NOW();
+
+CREATE INDEX apartments_moderation_queue_idx ON apartments (created_at, apartment_id) WHERE moderation_status = 'created';
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS apartments_claimed_at_idx;
+ALTER TABLE apartments DROP COLUMN IF EXISTS claimed_at;
\ No newline at end of file
@@ -0,0 +1,5 @@
+ALTER TABLE apartments ADD COLUMN claimed_at TIMESTAMP;
+
+UPDATE apartments SET claimed_at = NOW() WHERE moderation_status = 'on moderation';
+
+CREATE INDEX apartments_claimed_at_idx ON apartments (claimed_at) WHERE moderation_status = 'on moderation';
\ No newline at end of file
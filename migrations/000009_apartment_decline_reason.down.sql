@@ -0,0 +1,4 @@
+ALTER TABLE apartments DROP COLUMN IF EXISTS moderator_comment;
+ALTER TABLE apartments DROP COLUMN IF EXISTS decline_reason;
+
+DROP TYPE IF EXISTS decline_reason;
\ No newline at end of file
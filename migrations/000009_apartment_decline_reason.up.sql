@@ -0,0 +1,4 @@
+CREATE TYPE decline_reason AS ENUM ('prohibited_content', 'wrong_price', 'duplicate');
+
+ALTER TABLE apartments ADD COLUMN decline_reason decline_reason;
+ALTER TABLE apartments ADD COLUMN moderator_comment TEXT;
\ No newline at end of file
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS apartments_owner_id_idx;
+ALTER TABLE apartments DROP COLUMN IF EXISTS owner_id;
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE apartments ADD COLUMN owner_id BIGINT;
+
+CREATE INDEX apartments_owner_id_idx ON apartments (owner_id);
\ No newline at end of file
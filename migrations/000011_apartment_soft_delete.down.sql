@@ -0,0 +1,15 @@
+DELETE FROM apartments WHERE deleted_at IS NOT NULL;
+
+ALTER TABLE apartments DROP COLUMN IF EXISTS removal_reason;
+ALTER TABLE apartments DROP COLUMN IF EXISTS deleted_at;
+
+DROP INDEX IF EXISTS apartments_moderation_queue_idx;
+DROP INDEX IF EXISTS apartments_claimed_at_idx;
+
+ALTER TYPE moderation_status RENAME TO moderation_status_old;
+CREATE TYPE moderation_status AS ENUM ('created', 'approved', 'declined', 'on moderation');
+ALTER TABLE apartments ALTER COLUMN moderation_status TYPE moderation_status USING moderation_status::text::moderation_status;
+DROP TYPE moderation_status_old;
+
+CREATE INDEX apartments_moderation_queue_idx ON apartments (created_at, apartment_id) WHERE moderation_status = 'created';
+CREATE INDEX apartments_claimed_at_idx ON apartments (claimed_at) WHERE moderation_status = 'on moderation';
\ No newline at end of file
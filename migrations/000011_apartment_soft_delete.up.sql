@@ -0,0 +1,5 @@
+ALTER TYPE moderation_status ADD VALUE IF NOT EXISTS 'withdrawn';
+ALTER TYPE moderation_status ADD VALUE IF NOT EXISTS 'removed';
+
+ALTER TABLE apartments ADD COLUMN deleted_at TIMESTAMP;
+ALTER TABLE apartments ADD COLUMN removal_reason TEXT;
\ No newline at end of file
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS apartments_house_id_apartment_number_key;
\ No newline at end of file
@@ -0,0 +1,11 @@
+UPDATE apartments SET moderation_status = 'removed', deleted_at = NOW(), removal_reason = 'duplicate apartment number'
+WHERE deleted_at IS NULL AND apartment_id IN (
+    SELECT apartment_id FROM (
+        SELECT apartment_id, ROW_NUMBER() OVER (PARTITION BY house_id, apartment_number ORDER BY created_at, apartment_id) AS n
+        FROM apartments
+        WHERE deleted_at IS NULL
+    ) numbered
+    WHERE n > 1
+);
+
+CREATE UNIQUE INDEX apartments_house_id_apartment_number_key ON apartments (house_id, apartment_number) WHERE deleted_at IS NULL;
\ No newline at end of file
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS houses_developer_idx;
+DROP INDEX IF EXISTS houses_year_idx;
+DROP INDEX IF EXISTS apartments_search_idx;
\ No newline at end of file
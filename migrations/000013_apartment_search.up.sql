@@ -0,0 +1,5 @@
+CREATE INDEX apartments_search_idx ON apartments (price, apartment_id) INCLUDE (house_id, number_of_rooms)
+    WHERE moderation_status = 'approved' AND deleted_at IS NULL;
+
+CREATE INDEX houses_year_idx ON houses (year);
+CREATE INDEX houses_developer_idx ON houses (developer);
\ No newline at end of file
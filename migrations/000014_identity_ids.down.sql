@@ -0,0 +1,5 @@
+ALTER TABLE subscriptions ALTER COLUMN subscription_id DROP IDENTITY IF EXISTS;
+ALTER TABLE apartments ALTER COLUMN apartment_id DROP IDENTITY IF EXISTS;
+ALTER TABLE houses ALTER COLUMN house_id DROP IDENTITY IF EXISTS;
+ALTER TABLE sessions ALTER COLUMN session_id DROP IDENTITY IF EXISTS;
+ALTER TABLE users ALTER COLUMN user_id DROP IDENTITY IF EXISTS;
\ No newline at end of file
@@ -0,0 +1,12 @@
+ALTER TABLE users ALTER COLUMN user_id ADD GENERATED BY DEFAULT AS IDENTITY;
+ALTER TABLE sessions ALTER COLUMN session_id ADD GENERATED BY DEFAULT AS IDENTITY;
+ALTER TABLE houses ALTER COLUMN house_id ADD GENERATED BY DEFAULT AS IDENTITY;
+ALTER TABLE apartments ALTER COLUMN apartment_id ADD GENERATED BY DEFAULT AS IDENTITY;
+ALTER TABLE subscriptions ALTER COLUMN subscription_id ADD GENERATED BY DEFAULT AS IDENTITY;
+
+-- Existing rows carry random 32-bit ids, new ones start above the largest of them.
+SELECT setval(pg_get_serial_sequence('users', 'user_id'), COALESCE(MAX(user_id), 0) + 1, false) FROM users;
+SELECT setval(pg_get_serial_sequence('sessions', 'session_id'), COALESCE(MAX(session_id), 0) + 1, false) FROM sessions;
+SELECT setval(pg_get_serial_sequence('houses', 'house_id'), COALESCE(MAX(house_id), 0) + 1, false) FROM houses;
+SELECT setval(pg_get_serial_sequence('apartments', 'apartment_id'), COALESCE(MAX(apartment_id), 0) + 1, false) FROM apartments;
+SELECT setval(pg_get_serial_sequence('subscriptions', 'subscription_id'), COALESCE(MAX(subscription_id), 0) + 1, false) FROM subscriptions;
\ No newline at end of file
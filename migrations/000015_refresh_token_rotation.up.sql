@@ -0,0 +1,10 @@
+-- Refresh tokens were bcrypt hashed and can't be looked up by their hash, these sessions log in again.
+DELETE FROM sessions;
+
+CREATE TABLE used_refresh_tokens (
+    hash_refresh_token VARCHAR(255) PRIMARY KEY,
+    session_id         BIGINT    NOT NULL REFERENCES sessions (session_id) ON DELETE CASCADE,
+    used_at            TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX used_refresh_tokens_session_idx ON used_refresh_tokens (session_id);
\ No newline at end of file
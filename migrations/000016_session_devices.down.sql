@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS sessions_user_idx;
+ALTER TABLE sessions
+    DROP COLUMN IF EXISTS user_agent,
+    DROP COLUMN IF EXISTS ip,
+    DROP COLUMN IF EXISTS created_at,
+    DROP COLUMN IF EXISTS last_used_at;
\ No newline at end of file
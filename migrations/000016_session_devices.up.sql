@@ -0,0 +1,7 @@
+ALTER TABLE sessions
+    ADD COLUMN user_agent   TEXT      NOT NULL DEFAULT '',
+    ADD COLUMN ip           TEXT      NOT NULL DEFAULT '',
+    ADD COLUMN created_at   TIMESTAMP NOT NULL DEFAULT NOW(),
+    ADD COLUMN last_used_at TIMESTAMP NOT NULL DEFAULT NOW();
+
+CREATE INDEX sessions_user_idx ON sessions (user_id, created_at);
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE sessions
+    DROP COLUMN IF EXISTS access_token_id,
+    DROP COLUMN IF EXISTS access_token_expires_at;
\ No newline at end of file
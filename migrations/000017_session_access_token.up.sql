@@ -0,0 +1,3 @@
+ALTER TABLE sessions
+    ADD COLUMN access_token_id         TEXT      NOT NULL DEFAULT '',
+    ADD COLUMN access_token_expires_at TIMESTAMP NOT NULL DEFAULT NOW();
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE apartments DROP COLUMN IF EXISTS first_approved_at;
\ No newline at end of file
@@ -0,0 +1,4 @@
+ALTER TABLE apartments ADD COLUMN first_approved_at TIMESTAMP;
+
+-- Flats approved before the column existed were announced to subscribers already.
+UPDATE apartments SET first_approved_at = NOW() WHERE moderation_status = 'approved';
\ No newline at end of file
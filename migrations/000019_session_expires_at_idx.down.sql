@@ -0,0 +1 @@
+DROP INDEX IF EXISTS sessions_expires_at_idx;
\ No newline at end of file
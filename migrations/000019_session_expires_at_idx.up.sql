@@ -0,0 +1,2 @@
+-- Expired sessions are deleted in batches by the reaper, their used refresh tokens go with them.
+CREATE INDEX sessions_expires_at_idx ON sessions (expires_at);
\ No newline at end of file
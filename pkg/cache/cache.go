@@ -0,0 +1,8 @@
+package cache
+
+type Cache[K comparable, V any] interface {
+	Get(key K) (V, bool)
+	Add(key K, value V)
+	Remove(key K)
+	Len() int
+}
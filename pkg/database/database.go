@@ -0,0 +1,203 @@
+package database
+
+import (
+	"container/list"
+	"context"
+	"database/sql"
+	"errors"
+	_ "github.com/lib/pq"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+const postgresDriverName = "postgres"
+
+// defaultMaxStatements bounds the statement cache when the config leaves it unset.
+const defaultMaxStatements = 256
+
+// Config sizes the connection pool. Zero values keep the database/sql defaults.
+// MaxStatements bounds the statement cache, zero means defaultMaxStatements.
+type Config struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+	MaxStatements   int
+}
+
+// DB is the pool shared by all postgres repositories plus a cache of prepared statements.
+// The cache is keyed by query text. Filters and sort orders make the number of texts grow
+// combinatorially and every connection prepares each text it runs, so the cache keeps only
+// the maxStatements most recently used ones.
+type DB struct {
+	*sql.DB
+
+	maxStatements int
+
+	mu    sync.Mutex
+	order *list.List
+	stmts map[string]*list.Element
+}
+
+// statement is a cached statement. An evicted statement may still be leased by callers,
+// it is closed once the last of them releases it.
+type statement struct {
+	query   string
+	stmt    *sql.Stmt
+	leases  int
+	evicted bool
+}
+
+type txKey struct{}
+
+// WithTx binds the transaction to the context, statements taken with that context run in it.
+func WithTx(ctx context.Context, tx *sql.Tx) context.Context {
+	return context.WithValue(ctx, txKey{}, tx)
+}
+
+func TxFromContext(ctx context.Context) (*sql.Tx, bool) {
+	tx, ok := ctx.Value(txKey{}).(*sql.Tx)
+	return tx, ok
+}
+
+// Statement returns the statement prepared for the query, preparing it on first use, and
+// a release func to call once the statement and the rows read from it are no longer used.
+// Inside a transaction bound to ctx the statement is rebound to it and closed with it.
+// The statement belongs to the cache, callers must not close it.
+func (db *DB) Statement(ctx context.Context, query string) (*sql.Stmt, func(), error) {
+	s, err := db.statement(ctx, query)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	release := func() { db.release(s) }
+
+	if tx, ok := TxFromContext(ctx); ok {
+		return tx.StmtContext(ctx, s.stmt), release, nil
+	}
+
+	return s.stmt, release, nil
+}
+
+func (db *DB) statement(ctx context.Context, query string) (*statement, error) {
+	if s, ok := db.lease(query); ok {
+		return s, nil
+	}
+
+	//PREPARE OUTSIDE THE LOCK, A ROUND TRIP MUSTN'T STALL THE CACHED STATEMENTS
+	stmt, err := db.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if el, ok := db.stmts[query]; ok {
+		stmt.Close()
+
+		db.order.MoveToFront(el)
+		s := el.Value.(*statement)
+		s.leases++
+		return s, nil
+	}
+
+	s := &statement{query: query, stmt: stmt, leases: 1}
+	db.stmts[query] = db.order.PushFront(s)
+
+	if db.order.Len() > db.maxStatements {
+		db.evict(db.order.Back())
+	}
+
+	return s, nil
+}
+
+func (db *DB) lease(query string) (*statement, bool) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	el, ok := db.stmts[query]
+	if !ok {
+		return nil, false
+	}
+
+	db.order.MoveToFront(el)
+	s := el.Value.(*statement)
+	s.leases++
+	return s, true
+}
+
+func (db *DB) release(s *statement) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	s.leases--
+	if s.evicted && s.leases == 0 {
+		s.stmt.Close()
+	}
+}
+
+// evict drops the least recently used statement, db.mu must be held. Closing an unleased
+// statement doesn't wait, no call on it is in progress and open rows keep it alive themselves.
+func (db *DB) evict(el *list.Element) {
+	s := db.order.Remove(el).(*statement)
+	delete(db.stmts, s.query)
+
+	s.evicted = true
+	if s.leases == 0 {
+		s.stmt.Close()
+	}
+}
+
+// Close closes the cached statements and then the pool.
+func (db *DB) Close() error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	var errs []error
+	for query, el := range db.stmts {
+		errs = append(errs, el.Value.(*statement).stmt.Close())
+		delete(db.stmts, query)
+	}
+	db.order.Init()
+	errs = append(errs, db.DB.Close())
+
+	return errors.Join(errs...)
+}
+
+func newDB(db *sql.DB, maxStatements int) *DB {
+	if maxStatements < 1 {
+		maxStatements = defaultMaxStatements
+	}
+
+	return &DB{
+		DB:            db,
+		maxStatements: maxStatements,
+		order:         list.New(),
+		stmts:         make(map[string]*list.Element),
+	}
+}
+
+// New opens the pool shared by all postgres repositories. The caller owns it
+// and closes it once every repository is done.
+func New(dataSourceName string, cfg Config, logger *slog.Logger) (*DB, error) {
+	db, err := sql.Open(postgresDriverName, dataSourceName)
+	if err != nil {
+		logger.Error("failed to open postgres database connection", "error", err.Error())
+		return nil, err
+	}
+
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+
+	if err = db.Ping(); err != nil {
+		logger.Error("failed to ping postgres database connection", "error", err.Error())
+		db.Close()
+		return nil, err
+	}
+
+	return newDB(db, cfg.MaxStatements), nil
+}
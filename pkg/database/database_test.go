@@ -0,0 +1,113 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"sync/atomic"
+	"testing"
+)
+
+// stubDriver counts the statements prepared and closed on its connections.
+type stubDriver struct {
+	prepared atomic.Int64
+	closed   atomic.Int64
+}
+
+func (d *stubDriver) Open(string) (driver.Conn, error) { return &stubConn{d: d}, nil }
+
+type stubConn struct{ d *stubDriver }
+
+func (c *stubConn) Prepare(string) (driver.Stmt, error) {
+	c.d.prepared.Add(1)
+	return &stubStmt{d: c.d}, nil
+}
+func (c *stubConn) Close() error              { return nil }
+func (c *stubConn) Begin() (driver.Tx, error) { return nil, driver.ErrSkip }
+
+type stubStmt struct{ d *stubDriver }
+
+func (s *stubStmt) Close() error                               { s.d.closed.Add(1); return nil }
+func (s *stubStmt) NumInput() int                              { return -1 }
+func (s *stubStmt) Exec([]driver.Value) (driver.Result, error) { return driver.RowsAffected(0), nil }
+func (s *stubStmt) Query([]driver.Value) (driver.Rows, error)  { return stubRows{}, nil }
+
+type stubRows struct{}
+
+func (stubRows) Columns() []string         { return nil }
+func (stubRows) Close() error              { return nil }
+func (stubRows) Next([]driver.Value) error { return io.EOF }
+
+func testDB(t *testing.T, maxStatements int) (*stubDriver, *DB) {
+	d := &stubDriver{}
+	db := newDB(sql.OpenDB(stubConnector{d: d}), maxStatements)
+	t.Cleanup(func() { db.Close() })
+
+	return d, db
+}
+
+type stubConnector struct{ d *stubDriver }
+
+func (c stubConnector) Connect(context.Context) (driver.Conn, error) { return c.d.Open("") }
+func (c stubConnector) Driver() driver.Driver                        { return c.d }
+
+func TestStatement(t *testing.T) {
+	d, db := testDB(t, 2)
+
+	for range 3 {
+		_, release, err := db.Statement(context.Background(), "SELECT 1")
+		assert.NoError(t, err)
+		release()
+	}
+
+	//THE QUERY IS PREPARED ONCE AND REUSED
+	assert.Equal(t, int64(1), d.prepared.Load())
+	assert.Equal(t, int64(0), d.closed.Load())
+}
+
+func TestStatementEviction(t *testing.T) {
+	d, db := testDB(t, 1)
+	ctx := context.Background()
+
+	_, release, err := db.Statement(ctx, "SELECT 1")
+	assert.NoError(t, err)
+	release()
+
+	_, release, err = db.Statement(ctx, "SELECT 2")
+	assert.NoError(t, err)
+	release()
+
+	//THE CACHE IS FULL, THE LEAST RECENTLY USED STATEMENT IS CLOSED
+	assert.Equal(t, int64(1), d.closed.Load())
+
+	_, release, err = db.Statement(ctx, "SELECT 1")
+	assert.NoError(t, err)
+	release()
+
+	assert.Equal(t, int64(3), d.prepared.Load())
+	assert.Equal(t, int64(2), d.closed.Load())
+}
+
+func TestStatementEvictionLeased(t *testing.T) {
+	d, db := testDB(t, 1)
+	ctx := context.Background()
+
+	leased, release, err := db.Statement(ctx, "SELECT 1")
+	assert.NoError(t, err)
+
+	_, releaseOther, err := db.Statement(ctx, "SELECT 2")
+	assert.NoError(t, err)
+	releaseOther()
+
+	//AN EVICTED STATEMENT STAYS USABLE UNTIL IT IS RELEASED
+	assert.Equal(t, int64(0), d.closed.Load())
+
+	rows, err := leased.QueryContext(ctx)
+	assert.NoError(t, err)
+	assert.NoError(t, rows.Close())
+
+	release()
+	assert.Equal(t, int64(1), d.closed.Load())
+}
@@ -1,6 +1,8 @@
 package hasher
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"golang.org/x/crypto/bcrypt"
 	"unsafe"
 )
@@ -26,3 +28,10 @@ func Compare(data, hash string) error {
 
 	return nil
 }
+
+// Digest returns the hex encoded sha256. Unlike Hash it is deterministic,
+// it is meant for random tokens that have to be looked up by their hash.
+func Digest(data string) string {
+	sum := sha256.Sum256(unsafe.Slice(unsafe.StringData(data), len(data)))
+	return hex.EncodeToString(sum[:])
+}
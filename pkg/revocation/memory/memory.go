@@ -0,0 +1,61 @@
+package memory
+
+import (
+	"avito/pkg/revocation"
+	"sync"
+	"time"
+)
+
+var _ revocation.List = &list{}
+
+// sweepInterval bounds how often Revoke walks the whole set to drop expired ids.
+const sweepInterval = time.Minute
+
+// list keeps the revoked ids of a single instance in memory. Every id lives until
+// the expiration of its token plus the grace, the leeway the tokens are validated with.
+type list struct {
+	mu        sync.RWMutex
+	grace     time.Duration
+	ids       map[string]time.Time
+	lastSweep time.Time
+}
+
+func (l *list) Revoke(tokenID string, expiresAt time.Time) {
+	now := time.Now()
+	until := expiresAt.Add(l.grace)
+	if !now.Before(until) {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.ids[tokenID] = until
+
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+
+	for id, until := range l.ids {
+		if !now.Before(until) {
+			delete(l.ids, id)
+		}
+	}
+	l.lastSweep = now
+}
+
+func (l *list) Revoked(tokenID string) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	until, ok := l.ids[tokenID]
+	return ok && time.Now().Before(until)
+}
+
+func New(grace time.Duration) revocation.List {
+	return &list{
+		grace:     grace,
+		ids:       make(map[string]time.Time),
+		lastSweep: time.Now(),
+	}
+}
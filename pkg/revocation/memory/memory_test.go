@@ -0,0 +1,47 @@
+package memory
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestRevoke(t *testing.T) {
+	l := New(0)
+
+	l.Revoke("jti", time.Now().Add(time.Minute))
+
+	assert.True(t, l.Revoked("jti"))
+	assert.False(t, l.Revoked("another"))
+}
+
+func TestRevokeExpired(t *testing.T) {
+	l := New(0)
+
+	//AN EXPIRED TOKEN IS REJECTED ANYWAY, THERE IS NOTHING TO KEEP
+	l.Revoke("jti", time.Now().Add(-time.Minute))
+
+	assert.False(t, l.Revoked("jti"))
+	assert.Empty(t, l.(*list).ids)
+}
+
+func TestRevokeGrace(t *testing.T) {
+	l := New(time.Minute)
+
+	//THE TOKEN IS STILL ACCEPTED WITHIN THE LEEWAY, SO THE ID IS KEPT
+	l.Revoke("jti", time.Now().Add(-time.Second))
+
+	assert.True(t, l.Revoked("jti"))
+}
+
+func TestSweep(t *testing.T) {
+	l := New(0).(*list)
+
+	l.ids["stale"] = time.Now().Add(-time.Second)
+	l.lastSweep = time.Now().Add(-2 * sweepInterval)
+
+	l.Revoke("jti", time.Now().Add(time.Minute))
+
+	assert.NotContains(t, l.ids, "stale")
+	assert.Contains(t, l.ids, "jti")
+}
@@ -0,0 +1,10 @@
+package revocation
+
+import "time"
+
+// List holds the ids (jti) of access tokens revoked before their expiration.
+// An id only has to be kept until the token it belongs to expires.
+type List interface {
+	Revoke(tokenID string, expiresAt time.Time)
+	Revoked(tokenID string) bool
+}
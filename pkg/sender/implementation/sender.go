@@ -0,0 +1,31 @@
+package senderimpl
+
+import (
+	"avito/pkg/sender"
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+type stubSender struct{}
+
+// SendEmail imitates email delivery with a random latency and a 10% failure rate.
+func (s *stubSender) SendEmail(_ context.Context, recipient string, message string) error {
+	duration := time.Duration(rand.Int63n(3000)) * time.Millisecond
+	time.Sleep(duration)
+
+	errorProbability := 0.1
+	if rand.Float64() < errorProbability {
+		return errors.New("internal error")
+	}
+
+	fmt.Printf("send message '%s' to '%s'\n", message, recipient)
+
+	return nil
+}
+
+func New() sender.Sender {
+	return &stubSender{}
+}
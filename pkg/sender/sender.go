@@ -0,0 +1,7 @@
+package sender
+
+import "context"
+
+type Sender interface {
+	SendEmail(ctx context.Context, recipient string, message string) error
+}
@@ -0,0 +1,32 @@
+package tokenmanager
+
+import "github.com/golang-jwt/jwt/v5"
+
+// Claims are carried by the access token. The registered part holds the jti (ID),
+// the issuer, the audience and the expiration time. A dummy token is not bound to
+// a registered user, its user id is negative and never matches a stored one.
+type Claims struct {
+	UserID    int64  `json:"user_id"`
+	Role      string `json:"role"`
+	SessionID int64  `json:"session_id,omitempty"`
+	Dummy     bool   `json:"dummy,omitempty"`
+
+	jwt.RegisteredClaims
+}
+
+// Validate is called by the parser once the registered claims are verified,
+// a token without a user, a role or a jti is never let through.
+func (c Claims) Validate() error {
+	switch {
+	case c.Dummy && (c.UserID >= 0 || c.SessionID != 0):
+		return ErrInvalidClaims
+	case !c.Dummy && c.UserID <= 0:
+		return ErrInvalidClaims
+	case c.Role == "":
+		return ErrInvalidClaims
+	case c.ID == "":
+		return ErrInvalidClaims
+	default:
+		return nil
+	}
+}
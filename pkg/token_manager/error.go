@@ -0,0 +1,9 @@
+package tokenmanager
+
+import "errors"
+
+var (
+	ErrInvalidToken  = errors.New("invalid token")
+	ErrInvalidClaims = errors.New("invalid token claims")
+	ErrTokenExpired  = errors.New("token expired")
+)
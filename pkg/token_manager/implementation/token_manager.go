@@ -7,30 +7,73 @@ import (
 	"errors"
 	"fmt"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"time"
 	"unsafe"
 )
 
-const (
-	RoleClaimsTag   = "role"
-	ExpClaimsTag    = "exp"
-	UserIDClaimsTag = "user_id"
-)
+// Config configures signing and validation of the access tokens. An empty issuer
+// or audience is neither set nor checked.
+type Config struct {
+	SignedKey             string
+	AccessTokenExpiresIn  time.Duration
+	RefreshTokenExpiresIn time.Duration
+	Issuer                string
+	Audience              string
+	Leeway                time.Duration
+}
 
 type manager struct {
 	jwtSignedKey          []byte
 	accessTokenExpiresIn  time.Duration
 	refreshTokenExpiresIn time.Duration
+	issuer                string
+	audience              string
+
+	parser *jwt.Parser
 }
 
-func (m *manager) GenerateAccessToken(userID uint32, role string) (string, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		UserIDClaimsTag: userID,
-		RoleClaimsTag:   role,
-		ExpClaimsTag:    time.Now().Add(m.accessTokenExpiresIn).Unix(),
+// GenerateAccessToken binds the token to the session it was issued for,
+// a zero sessionID leaves the token without one. The claims are returned
+// for the caller to keep track of the jti and the expiration.
+func (m *manager) GenerateAccessToken(userID int64, role string, sessionID int64) (string, tokenmanager.Claims, error) {
+	return m.generateAccessToken(tokenmanager.Claims{
+		UserID:    userID,
+		Role:      role,
+		SessionID: sessionID,
 	})
+}
 
-	return token.SignedString(m.jwtSignedKey)
+// GenerateDummyAccessToken issues a token without a session for a synthetic user.
+// The id is taken from the negative range, identity columns never produce it.
+func (m *manager) GenerateDummyAccessToken(role string) (string, tokenmanager.Claims, error) {
+	return m.generateAccessToken(tokenmanager.Claims{
+		UserID: -int64(uuid.New().ID()) - 1,
+		Role:   role,
+		Dummy:  true,
+	})
+}
+
+func (m *manager) generateAccessToken(claims tokenmanager.Claims) (string, tokenmanager.Claims, error) {
+	now := time.Now()
+
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		ID:        uuid.NewString(),
+		Issuer:    m.issuer,
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(m.accessTokenExpiresIn)),
+	}
+
+	if m.audience != "" {
+		claims.Audience = jwt.ClaimStrings{m.audience}
+	}
+
+	accessToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(m.jwtSignedKey)
+	if err != nil {
+		return "", tokenmanager.Claims{}, err
+	}
+
+	return accessToken, claims, nil
 }
 
 func (m *manager) GenerateRefreshToken() (refreshToken string, expiresAt time.Time, err error) {
@@ -43,35 +86,62 @@ func (m *manager) GenerateRefreshToken() (refreshToken string, expiresAt time.Ti
 	return hex.EncodeToString(token), time.Now().Add(m.refreshTokenExpiresIn), nil
 }
 
-func (m *manager) Parse(tokenString string) (jwt.MapClaims, error) {
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
+// Parse verifies the signature and the claims. An expired token is still returned
+// together with ErrTokenExpired, the refresh flow has to know whose token it was.
+func (m *manager) Parse(tokenString string) (tokenmanager.Claims, error) {
+	claims := tokenmanager.Claims{}
+
+	_, err := m.parser.ParseWithClaims(tokenString, &claims, func(*jwt.Token) (interface{}, error) {
 		return m.jwtSignedKey, nil
 	})
 
-	if err != nil {
-		switch {
-		case errors.Is(err, jwt.ErrTokenExpired):
-		default:
-			return nil, err
-		}
+	switch {
+	case err == nil:
+		return claims, nil
+	case expiredOnly(err):
+		return claims, tokenmanager.ErrTokenExpired
+	default:
+		return tokenmanager.Claims{}, fmt.Errorf("%w: %w", tokenmanager.ErrInvalidToken, err)
 	}
+}
 
-	claims, ok := token.Claims.(jwt.MapClaims)
-	if !ok {
-		return nil, errors.New("invalid token")
+// expiredOnly reports whether the expiration is the only claim the token failed on.
+func expiredOnly(err error) bool {
+	if !errors.Is(err, jwt.ErrTokenExpired) {
+		return false
+	}
+
+	for _, e := range []error{
+		jwt.ErrTokenInvalidIssuer,
+		jwt.ErrTokenInvalidAudience,
+		jwt.ErrTokenNotValidYet,
+		jwt.ErrTokenUsedBeforeIssued,
+		jwt.ErrTokenRequiredClaimMissing,
+		tokenmanager.ErrInvalidClaims,
+	} {
+		if errors.Is(err, e) {
+			return false
+		}
 	}
 
-	return claims, nil
+	return true
 }
 
-func New(jwtSignedKey string, accessTokenExpiresIn, refreshTokenExpiresIn time.Duration) tokenmanager.Manager {
+func New(cfg Config) tokenmanager.Manager {
 	tm := &manager{
-		jwtSignedKey:          unsafe.Slice(unsafe.StringData(jwtSignedKey), len(jwtSignedKey)),
-		accessTokenExpiresIn:  accessTokenExpiresIn,
-		refreshTokenExpiresIn: refreshTokenExpiresIn,
+		jwtSignedKey:          unsafe.Slice(unsafe.StringData(cfg.SignedKey), len(cfg.SignedKey)),
+		accessTokenExpiresIn:  cfg.AccessTokenExpiresIn,
+		refreshTokenExpiresIn: cfg.RefreshTokenExpiresIn,
+		issuer:                cfg.Issuer,
+		audience:              cfg.Audience,
+		parser: jwt.NewParser(
+			jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+			jwt.WithExpirationRequired(),
+			jwt.WithIssuedAt(),
+			jwt.WithIssuer(cfg.Issuer),
+			jwt.WithAudience(cfg.Audience),
+			jwt.WithLeeway(cfg.Leeway),
+		),
 	}
 
 	return tm
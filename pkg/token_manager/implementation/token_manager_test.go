@@ -0,0 +1,210 @@
+package tokenmanagerimpl
+
+import (
+	tokenmanager "avito/pkg/token_manager"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tm := testManager(time.Minute)
+
+	accessToken, generated, err := tm.GenerateAccessToken(1, "client", 7)
+	assert.NoError(t, err)
+
+	claims, err := tm.Parse(accessToken)
+	assert.NoError(t, err)
+	assert.Equal(t, generated, claims)
+	assert.Equal(t, int64(1), claims.UserID)
+	assert.Equal(t, "client", claims.Role)
+	assert.Equal(t, int64(7), claims.SessionID)
+	assert.Equal(t, "avito", claims.Issuer)
+	assert.Equal(t, jwt.ClaimStrings{"avito-api"}, claims.Audience)
+	assert.NotEmpty(t, claims.ID)
+}
+
+func TestParseDummy(t *testing.T) {
+	tm := testManager(time.Minute)
+
+	accessToken, generated, err := tm.GenerateDummyAccessToken("moderator")
+	assert.NoError(t, err)
+
+	//A DUMMY USER ID NEVER OVERLAPS WITH THE IDENTITY RANGE
+	claims, err := tm.Parse(accessToken)
+	assert.NoError(t, err)
+	assert.Equal(t, generated, claims)
+	assert.True(t, claims.Dummy)
+	assert.Less(t, claims.UserID, int64(0))
+	assert.Equal(t, "moderator", claims.Role)
+	assert.Zero(t, claims.SessionID)
+}
+
+func TestParseExpired(t *testing.T) {
+	tm := testManager(-time.Minute)
+
+	accessToken, _, err := tm.GenerateAccessToken(1, "client", 0)
+	assert.NoError(t, err)
+
+	//THE CLAIMS OF AN EXPIRED TOKEN ARE STILL RETURNED
+	claims, err := tm.Parse(accessToken)
+	assert.ErrorIs(t, err, tokenmanager.ErrTokenExpired)
+	assert.Equal(t, int64(1), claims.UserID)
+}
+
+func TestParseErr(t *testing.T) {
+	now := time.Now()
+	valid := tokenmanager.Claims{
+		UserID: 1,
+		Role:   "client",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        "jti",
+			Issuer:    "avito",
+			Audience:  jwt.ClaimStrings{"avito-api"},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Minute)),
+		},
+	}
+
+	cases := []struct {
+		name        string
+		prepareFunc func() string
+	}{
+		{
+			name: "MALFORMED",
+			prepareFunc: func() string {
+				return "not-a-token"
+			},
+		},
+		{
+			name: "WRONG KEY",
+			prepareFunc: func() string {
+				return sign(t, valid, jwt.SigningMethodHS256, []byte("another-key"))
+			},
+		},
+		{
+			name: "WRONG SIGNING METHOD",
+			prepareFunc: func() string {
+				return sign(t, valid, jwt.SigningMethodHS512, []byte("key"))
+			},
+		},
+		{
+			name: "WRONG ISSUER",
+			prepareFunc: func() string {
+				c := valid
+				c.Issuer = "another"
+				return sign(t, c, jwt.SigningMethodHS256, []byte("key"))
+			},
+		},
+		{
+			name: "WRONG AUDIENCE",
+			prepareFunc: func() string {
+				c := valid
+				c.Audience = jwt.ClaimStrings{"another"}
+				return sign(t, c, jwt.SigningMethodHS256, []byte("key"))
+			},
+		},
+		{
+			name: "NO EXPIRATION",
+			prepareFunc: func() string {
+				c := valid
+				c.ExpiresAt = nil
+				return sign(t, c, jwt.SigningMethodHS256, []byte("key"))
+			},
+		},
+		{
+			name: "NO USER ID",
+			prepareFunc: func() string {
+				c := valid
+				c.UserID = 0
+				return sign(t, c, jwt.SigningMethodHS256, []byte("key"))
+			},
+		},
+		{
+			name: "DUMMY WITH USER ID",
+			prepareFunc: func() string {
+				c := valid
+				c.Dummy = true
+				return sign(t, c, jwt.SigningMethodHS256, []byte("key"))
+			},
+		},
+		{
+			name: "NEGATIVE USER ID",
+			prepareFunc: func() string {
+				c := valid
+				c.UserID = -1
+				return sign(t, c, jwt.SigningMethodHS256, []byte("key"))
+			},
+		},
+		{
+			name: "NO ROLE",
+			prepareFunc: func() string {
+				c := valid
+				c.Role = ""
+				return sign(t, c, jwt.SigningMethodHS256, []byte("key"))
+			},
+		},
+		{
+			name: "NO JTI",
+			prepareFunc: func() string {
+				c := valid
+				c.ID = ""
+				return sign(t, c, jwt.SigningMethodHS256, []byte("key"))
+			},
+		},
+		{
+			//AN EXPIRED TOKEN FROM ANOTHER ISSUER IS INVALID, NOT JUST EXPIRED
+			name: "EXPIRED WRONG ISSUER",
+			prepareFunc: func() string {
+				c := valid
+				c.Issuer = "another"
+				c.ExpiresAt = jwt.NewNumericDate(now.Add(-time.Hour))
+				return sign(t, c, jwt.SigningMethodHS256, []byte("key"))
+			},
+		},
+	}
+
+	tm := testManager(time.Minute)
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			claims, err := tm.Parse(c.prepareFunc())
+			assert.ErrorIs(t, err, tokenmanager.ErrInvalidToken)
+			assert.Equal(t, tokenmanager.Claims{}, claims)
+		})
+	}
+}
+
+func TestParseLeeway(t *testing.T) {
+	tm := New(Config{
+		SignedKey:            "key",
+		AccessTokenExpiresIn: -time.Second,
+		Issuer:               "avito",
+		Audience:             "avito-api",
+		Leeway:               time.Minute,
+	})
+
+	accessToken, _, err := tm.GenerateAccessToken(1, "client", 0)
+	assert.NoError(t, err)
+
+	_, err = tm.Parse(accessToken)
+	assert.NoError(t, err)
+}
+
+func sign(t *testing.T, claims tokenmanager.Claims, method jwt.SigningMethod, key []byte) string {
+	token, err := jwt.NewWithClaims(method, claims).SignedString(key)
+	assert.NoError(t, err)
+
+	return token
+}
+
+func testManager(accessTokenExpiresIn time.Duration) tokenmanager.Manager {
+	return New(Config{
+		SignedKey:             "key",
+		AccessTokenExpiresIn:  accessTokenExpiresIn,
+		RefreshTokenExpiresIn: time.Hour,
+		Issuer:                "avito",
+		Audience:              "avito-api",
+	})
+}
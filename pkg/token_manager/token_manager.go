@@ -1,12 +1,12 @@
 package tokenmanager
 
 import (
-	"github.com/golang-jwt/jwt/v5"
 	"time"
 )
 
 type Manager interface {
-	GenerateAccessToken(userID uint32, role string) (accessToken string, err error)
+	GenerateAccessToken(userID int64, role string, sessionID int64) (accessToken string, claims Claims, err error)
+	GenerateDummyAccessToken(role string) (accessToken string, claims Claims, err error)
 	GenerateRefreshToken() (refreshToken string, expiresAt time.Time, err error)
-	Parse(tokenString string) (jwt.MapClaims, error)
+	Parse(tokenString string) (Claims, error)
 }
@@ -3,7 +3,7 @@
 //
 // Generated by this command:
 //
-//	mockgen -source pkg/token_manager/token_manager.go -destination pkg/token_manager/token_manager_mock.go
+//	mockgen -source pkg/token_manager/token_manager.go -destination pkg/token_manager/token_manager_mock.go -self_package avito/pkg/token_manager
 //
 
 // Package mock_tokenmanager is a generated GoMock package.
@@ -13,7 +13,6 @@ import (
 	reflect "reflect"
 	time "time"
 
-	jwt "github.com/golang-jwt/jwt/v5"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -41,18 +40,35 @@ func (m *MockManager) EXPECT() *MockManagerMockRecorder {
 }
 
 // GenerateAccessToken mocks base method.
-func (m *MockManager) GenerateAccessToken(userID uint32, role string) (string, error) {
+func (m *MockManager) GenerateAccessToken(userID int64, role string, sessionID int64) (string, Claims, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GenerateAccessToken", userID, role)
+	ret := m.ctrl.Call(m, "GenerateAccessToken", userID, role, sessionID)
 	ret0, _ := ret[0].(string)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret1, _ := ret[1].(Claims)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 // GenerateAccessToken indicates an expected call of GenerateAccessToken.
-func (mr *MockManagerMockRecorder) GenerateAccessToken(userID, role any) *gomock.Call {
+func (mr *MockManagerMockRecorder) GenerateAccessToken(userID, role, sessionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateAccessToken", reflect.TypeOf((*MockManager)(nil).GenerateAccessToken), userID, role, sessionID)
+}
+
+// GenerateDummyAccessToken mocks base method.
+func (m *MockManager) GenerateDummyAccessToken(role string) (string, Claims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GenerateDummyAccessToken", role)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(Claims)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GenerateDummyAccessToken indicates an expected call of GenerateDummyAccessToken.
+func (mr *MockManagerMockRecorder) GenerateDummyAccessToken(role any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateAccessToken", reflect.TypeOf((*MockManager)(nil).GenerateAccessToken), userID, role)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateDummyAccessToken", reflect.TypeOf((*MockManager)(nil).GenerateDummyAccessToken), role)
 }
 
 // GenerateRefreshToken mocks base method.
@@ -72,10 +88,10 @@ func (mr *MockManagerMockRecorder) GenerateRefreshToken() *gomock.Call {
 }
 
 // Parse mocks base method.
-func (m *MockManager) Parse(tokenString string) (jwt.MapClaims, error) {
+func (m *MockManager) Parse(tokenString string) (Claims, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Parse", tokenString)
-	ret0, _ := ret[0].(jwt.MapClaims)
+	ret0, _ := ret[0].(Claims)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }